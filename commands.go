@@ -0,0 +1,185 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"kasir-api/repositories"
+	"kasir-api/services"
+)
+
+const commandUsage = `Perintah:
+  rollup-rebuild [-from YYYY-MM-DD] [-to YYYY-MM-DD]
+      Hitung ulang rollup penjualan harian. Tanpa flag semua tanggal dihitung ulang.
+  basket-rebuild [-days N]
+      Hitung ulang analisis keranjang (produk yang sering dibeli bersama) dari N hari terakhir.
+  bench-seed [-transactions N] [-products N] [-days N] -yes
+      Isi database dengan data penjualan palsu untuk benchmark. Jangan dipakai di production.`
+
+// runCommand menjalankan perintah maintenance dari command line, misalnya
+// "kasir-api rollup-rebuild". Server tidak dijalankan.
+func runCommand(args []string, db *sql.DB, location *time.Location) error {
+	reportService := services.NewReportService(repositories.NewReportRepository(db), location)
+
+	switch args[0] {
+	case "rollup-rebuild":
+		return rollupRebuildCommand(args[1:], reportService)
+	case "basket-rebuild":
+		return basketRebuildCommand(args[1:], db, location)
+	case "bench-seed":
+		return benchSeedCommand(args[1:], db, reportService, location)
+	default:
+		return fmt.Errorf("perintah %q tidak dikenal\n%s", args[0], commandUsage)
+	}
+}
+
+func rollupRebuildCommand(args []string, reportService *services.ReportService) error {
+	fs := flag.NewFlagSet("rollup-rebuild", flag.ExitOnError)
+	from := fs.String("from", "", "tanggal awal (YYYY-MM-DD), kosong berarti tanpa batas")
+	to := fs.String("to", "", "tanggal akhir (YYYY-MM-DD), kosong berarti tanpa batas")
+	fs.Parse(args)
+
+	started := time.Now()
+	days, err := reportService.RebuildRollups(*from, *to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rollup dihitung ulang: %d hari dengan transaksi (%s)\n", days, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+func basketRebuildCommand(args []string, db *sql.DB, location *time.Location) error {
+	fs := flag.NewFlagSet("basket-rebuild", flag.ExitOnError)
+	days := fs.Int("days", 90, "jendela analisis dalam hari")
+	fs.Parse(args)
+
+	basketService, err := services.NewBasketService(repositories.NewBasketRepository(db), location, *days)
+	if err != nil {
+		return err
+	}
+	started := time.Now()
+	analysis, err := basketService.Refresh()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Analisis keranjang dihitung ulang: %d transaksi (%s)\n", analysis.Transactions, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// benchSeedCommand membuat kategori "Bench" berisi produk palsu lalu
+// transaksi acak yang tersebar di beberapa hari terakhir. Data dibuat
+// langsung dengan SQL per batch karena checkout satu per satu terlalu lambat
+// untuk jutaan baris; rollup dihitung ulang di akhir.
+func benchSeedCommand(args []string, db *sql.DB, reportService *services.ReportService, location *time.Location) error {
+	fs := flag.NewFlagSet("bench-seed", flag.ExitOnError)
+	transactions := fs.Int("transactions", 1000000, "jumlah transaksi")
+	products := fs.Int("products", 500, "jumlah produk")
+	days := fs.Int("days", 365, "transaksi disebar ke sekian hari terakhir")
+	confirm := fs.Bool("yes", false, "konfirmasi menambah data palsu ke database")
+	fs.Parse(args)
+
+	if !*confirm {
+		return errors.New("bench-seed menambah data palsu ke database; jalankan dengan -yes untuk melanjutkan")
+	}
+	if *transactions <= 0 || *products <= 0 || *days <= 0 {
+		return errors.New("transactions, products dan days harus lebih dari 0")
+	}
+
+	started := time.Now()
+	var categoryID int
+	err := db.QueryRow(`INSERT INTO categories (name, description) VALUES ('Bench', 'Data benchmark') RETURNING id`).Scan(&categoryID)
+	if err != nil {
+		return err
+	}
+	var firstProduct, lastProduct int
+	err = db.QueryRow(`
+		WITH p AS (
+			INSERT INTO products (name, price, cost_price, stock, category_id)
+			SELECT 'Bench Produk ' || g, 1000 + (g * 37 % 50) * 500, 700 + (g * 37 % 50) * 350, 1000000, $1
+			FROM generate_series(1, $2) g
+			RETURNING id
+		)
+		SELECT MIN(id), MAX(id) FROM p
+	`, categoryID, *products).Scan(&firstProduct, &lastProduct)
+	if err != nil {
+		return err
+	}
+
+	const batchSize = 50000
+	end := time.Now().In(location)
+	for done := 0; done < *transactions; done += batchSize {
+		n := min(batchSize, *transactions-done)
+		if err := seedTransactionBatch(db, n, end, *days, firstProduct, lastProduct); err != nil {
+			return err
+		}
+		fmt.Printf("\r%d/%d transaksi", done+n, *transactions)
+	}
+	fmt.Println()
+
+	if _, err := db.Exec("ANALYZE transactions; ANALYZE transaction_details"); err != nil {
+		return err
+	}
+	if _, err := reportService.RebuildRollups("", ""); err != nil {
+		return err
+	}
+	fmt.Printf("Selesai dalam %s (kategori Bench id %d)\n", time.Since(started).Round(time.Second), categoryID)
+	return nil
+}
+
+// seedTransactionBatch membuat n transaksi dengan 1-4 item acak dari produk
+// firstProduct..lastProduct.
+func seedTransactionBatch(db *sql.DB, n int, end time.Time, days, firstProduct, lastProduct int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var firstID, lastID int
+	err = tx.QueryRow(`
+		WITH t AS (
+			INSERT INTO transactions (total_amount, created_at)
+			SELECT 0, $1::timestamptz - random() * ($2 * interval '1 day')
+			FROM generate_series(1, $3)
+			RETURNING id
+		)
+		SELECT MIN(id), MAX(id) FROM t
+	`, end, days, n).Scan(&firstID, &lastID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO transaction_details (transaction_id, product_id, quantity, unit, base_quantity, unit_price, subtotal, cost_amount)
+		SELECT item.transaction_id, p.id, item.qty, p.unit, item.qty, p.price, p.price * item.qty, p.cost_price * item.qty
+		FROM (
+			SELECT t.id AS transaction_id,
+				$3 + floor(random() * ($4 - $3 + 1))::int AS product_id,
+				1 + floor(random() * 3)::int AS qty
+			FROM generate_series($1, $2) t(id)
+			CROSS JOIN LATERAL generate_series(1, 1 + t.id % 4) g
+		) item
+		JOIN products p ON p.id = item.product_id
+	`, firstID, lastID, firstProduct, lastProduct)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE transactions t SET total_amount = s.total
+		FROM (
+			SELECT transaction_id, SUM(subtotal) AS total
+			FROM transaction_details
+			WHERE transaction_id BETWEEN $1 AND $2
+			GROUP BY transaction_id
+		) s
+		WHERE t.id = s.transaction_id
+	`, firstID, lastID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
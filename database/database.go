@@ -8,10 +8,14 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/stdlib"
 )
 
-func InitDB(connectionString string) (*sql.DB, error) {
+// InitDB membuka koneksi database. Sesi database memakai zona waktu toko dan
+// nilai timestamptz dibaca dalam location, sehingga waktu di respons API
+// selalu membawa offset zona waktu toko.
+func InitDB(connectionString string, location *time.Location) (*sql.DB, error) {
 	// Parse config untuk disable prepared statements (needed for Supabase pooler)
 	config, err := pgx.ParseConfig(connectionString)
 	if err != nil {
@@ -21,12 +25,16 @@ func InitDB(connectionString string) (*sql.DB, error) {
 	// Disable prepared statements untuk support Supabase transaction pooler
 	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
 
-	// Register driver dan open connection
-	connStr := stdlib.RegisterConnConfig(config)
-	db, err := sql.Open("pgx", connStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
+	config.RuntimeParams["timezone"] = location.String()
+
+	db := stdlib.OpenDB(*config, stdlib.OptionAfterConnect(func(ctx context.Context, conn *pgx.Conn) error {
+		conn.TypeMap().RegisterType(&pgtype.Type{
+			Name:  "timestamptz",
+			OID:   pgtype.TimestamptzOID,
+			Codec: &pgtype.TimestamptzCodec{ScanLocation: location},
+		})
+		return nil
+	}))
 
 	// Set connection pool settings
 	db.SetMaxOpenConns(25)
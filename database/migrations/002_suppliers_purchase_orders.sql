@@ -0,0 +1,56 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price INT NOT NULL DEFAULT 0;
+
+CREATE TABLE suppliers (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR NOT NULL,
+  contact_name VARCHAR NOT NULL DEFAULT '',
+  phone VARCHAR NOT NULL DEFAULT '',
+  email VARCHAR NOT NULL DEFAULT '',
+  address VARCHAR NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP
+);
+
+CREATE TABLE purchase_orders (
+  id SERIAL PRIMARY KEY,
+  supplier_id INT NOT NULL REFERENCES suppliers(id),
+  status VARCHAR NOT NULL DEFAULT 'draft',
+  notes VARCHAR NOT NULL DEFAULT '',
+  total_cost INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP,
+  sent_at TIMESTAMP,
+  received_at TIMESTAMP,
+  CONSTRAINT purchase_orders_status_check
+    CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled'))
+);
+
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+
+CREATE TABLE purchase_order_items (
+  id SERIAL PRIMARY KEY,
+  purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+  product_id INT NOT NULL REFERENCES products(id),
+  quantity INT NOT NULL CHECK (quantity > 0),
+  received_quantity INT NOT NULL DEFAULT 0,
+  unit_cost INT NOT NULL CHECK (unit_cost >= 0)
+);
+
+CREATE INDEX idx_purchase_order_items_po_id ON purchase_order_items(purchase_order_id);
+
+-- Ledger semua perubahan stok. quantity bernilai positif untuk stok masuk
+-- dan negatif untuk stok keluar.
+CREATE TABLE stock_movements (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  quantity INT NOT NULL,
+  type VARCHAR NOT NULL,
+  reference_type VARCHAR,
+  reference_id INT,
+  unit_cost INT,
+  stock_after INT NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_movements_product_id ON stock_movements(product_id, created_at);
@@ -0,0 +1,14 @@
+CREATE TABLE product_images (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  storage_key VARCHAR NOT NULL,
+  thumbnail_key VARCHAR NOT NULL,
+  content_type VARCHAR NOT NULL,
+  size_bytes INT NOT NULL,
+  width INT NOT NULL,
+  height INT NOT NULL,
+  position INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_images_product_id ON product_images(product_id, position);
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode VARCHAR;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products (lower(sku)) WHERE sku IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_barcode ON products (barcode) WHERE barcode IS NOT NULL;
+
+-- Trigram index untuk pencarian typo-tolerant dan ILIKE '%x%'
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_sku_trgm ON products USING gin (sku gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_barcode_trgm ON products USING gin (barcode gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING gin (name gin_trgm_ops);
+
+-- Full-text index untuk prefix matching (as-you-type)
+CREATE INDEX IF NOT EXISTS idx_products_name_fts ON products USING gin (to_tsvector('simple', name));
@@ -0,0 +1,24 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_bundle BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE bundle_components (
+  bundle_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  component_id INT NOT NULL REFERENCES products(id),
+  quantity INT NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (bundle_id, component_id),
+  CHECK (bundle_id <> component_id)
+);
+
+CREATE INDEX idx_bundle_components_component_id ON bundle_components(component_id);
+
+-- Atribusi pendapatan bundle ke setiap komponennya. revenue dialokasikan
+-- proporsional terhadap harga jual normal komponen saat checkout.
+CREATE TABLE transaction_detail_components (
+  id SERIAL PRIMARY KEY,
+  transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+  product_id INT NOT NULL REFERENCES products(id),
+  quantity INT NOT NULL,
+  revenue INT NOT NULL
+);
+
+CREATE INDEX idx_transaction_detail_components_detail_id ON transaction_detail_components(transaction_detail_id);
+CREATE INDEX idx_transaction_detail_components_product_id ON transaction_detail_components(product_id);
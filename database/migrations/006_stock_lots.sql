@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS track_lots BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Untuk produk dengan track_lots, products.stock selalu sama dengan jumlah
+-- quantity semua lot-nya (termasuk yang sudah kedaluwarsa).
+CREATE TABLE stock_lots (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  lot_number VARCHAR NOT NULL,
+  expiry_date DATE,
+  quantity INT NOT NULL CHECK (quantity >= 0),
+  initial_quantity INT NOT NULL,
+  purchase_order_id INT REFERENCES purchase_orders(id),
+  received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (product_id, lot_number)
+);
+
+CREATE INDEX idx_stock_lots_fefo ON stock_lots(product_id, expiry_date) WHERE quantity > 0;
+CREATE INDEX idx_stock_lots_expiry ON stock_lots(expiry_date) WHERE quantity > 0;
+
+CREATE TABLE transaction_detail_lots (
+  id SERIAL PRIMARY KEY,
+  transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+  product_id INT NOT NULL REFERENCES products(id),
+  lot_id INT NOT NULL REFERENCES stock_lots(id),
+  quantity INT NOT NULL
+);
+
+CREATE INDEX idx_transaction_detail_lots_detail_id ON transaction_detail_lots(transaction_detail_id);
+CREATE INDEX idx_transaction_detail_lots_lot_id ON transaction_detail_lots(lot_id);
@@ -0,0 +1,37 @@
+-- Quantity pecahan (presisi 3 desimal) untuk stok dan penjualan
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unit VARCHAR NOT NULL DEFAULT 'pcs';
+ALTER TABLE products ALTER COLUMN stock TYPE NUMERIC(14,3);
+
+ALTER TABLE stock_movements ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_movements ALTER COLUMN stock_after TYPE NUMERIC(14,3);
+
+ALTER TABLE stock_lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_lots ALTER COLUMN initial_quantity TYPE NUMERIC(14,3);
+
+ALTER TABLE bundle_components ALTER COLUMN quantity TYPE NUMERIC(14,3);
+
+ALTER TABLE purchase_order_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE purchase_order_items ALTER COLUMN received_quantity TYPE NUMERIC(14,3);
+
+-- quantity adalah jumlah dalam unit yang dijual, base_quantity adalah
+-- jumlah dalam unit dasar produk (yang mengurangi stok dan dipakai laporan)
+ALTER TABLE transaction_details ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit VARCHAR;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS base_quantity NUMERIC(14,3);
+UPDATE transaction_details SET base_quantity = quantity WHERE base_quantity IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN base_quantity SET NOT NULL;
+
+ALTER TABLE transaction_detail_components ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE transaction_detail_lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
+
+-- Unit jual alternatif, misalnya "box" = 12 pcs. factor adalah jumlah unit
+-- dasar per satu unit ini. price NULL berarti harga = price produk x factor.
+CREATE TABLE product_units (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  factor NUMERIC(14,3) NOT NULL CHECK (factor > 0),
+  price INT CHECK (price >= 0)
+);
+
+CREATE UNIQUE INDEX idx_product_units_name ON product_units(product_id, lower(name));
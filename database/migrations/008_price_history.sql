@@ -0,0 +1,33 @@
+-- Jadwal perubahan harga dan riwayat setiap perubahan products.price
+CREATE TABLE price_schedules (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  new_price INT NOT NULL CHECK (new_price >= 0),
+  effective_at TIMESTAMPTZ NOT NULL,
+  status VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'cancelled')),
+  created_by VARCHAR,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  applied_at TIMESTAMPTZ
+);
+
+-- Scheduler hanya mencari jadwal pending yang sudah jatuh tempo
+CREATE INDEX idx_price_schedules_due ON price_schedules(effective_at) WHERE status = 'pending';
+CREATE INDEX idx_price_schedules_product ON price_schedules(product_id);
+
+CREATE TABLE price_history (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  old_price INT,
+  new_price INT NOT NULL,
+  source VARCHAR NOT NULL CHECK (source IN ('manual', 'scheduled')),
+  schedule_id INT REFERENCES price_schedules(id) ON DELETE SET NULL,
+  changed_by VARCHAR,
+  changed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_price_history_product ON price_history(product_id, changed_at);
+
+-- Harga yang sudah ada dicatat sebagai titik awal riwayat. Waktu produk
+-- masih TIMESTAMP yang ditulis dalam UTC, jadi dikonversi sebagai UTC.
+INSERT INTO price_history (product_id, old_price, new_price, source, changed_at)
+SELECT id, NULL, price, 'manual', COALESCE(COALESCE(updated_at, created_at) AT TIME ZONE 'UTC', CURRENT_TIMESTAMP) FROM products;
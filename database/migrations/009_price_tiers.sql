@@ -0,0 +1,31 @@
+-- Kelompok pelanggan, misalnya "Warung" atau "Reseller", untuk daftar harga khusus
+CREATE TABLE customer_groups (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_customer_groups_name ON customer_groups(lower(name));
+
+-- Harga bertingkat per produk. customer_group_id NULL berlaku untuk semua
+-- pembeli; price adalah harga per unit dasar jika jumlah (dalam unit dasar)
+-- mencapai min_quantity.
+CREATE TABLE product_price_tiers (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  customer_group_id INT REFERENCES customer_groups(id) ON DELETE CASCADE,
+  min_quantity NUMERIC(14,3) NOT NULL CHECK (min_quantity > 0),
+  price INT NOT NULL CHECK (price >= 0)
+);
+
+CREATE UNIQUE INDEX idx_product_price_tiers_unique ON product_price_tiers(product_id, COALESCE(customer_group_id, 0), min_quantity);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS customer_group_id INT REFERENCES customer_groups(id) ON DELETE SET NULL;
+
+-- Harga per unit yang benar-benar dipakai dan tier yang berlaku (snapshot,
+-- karena tier bisa diubah setelah transaksi)
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_price INT;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tier_min_quantity NUMERIC(14,3);
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tier_customer_group_id INT;
@@ -0,0 +1,8 @@
+-- Nama kategori unik tanpa membedakan huruf besar/kecil. Duplikat yang sudah
+-- ada diberi akhiran id supaya index bisa dibuat.
+UPDATE categories c SET name = c.name || ' (' || c.id || ')'
+WHERE EXISTS (
+  SELECT 1 FROM categories o WHERE lower(o.name) = lower(c.name) AND o.id < c.id
+);
+
+CREATE UNIQUE INDEX idx_categories_name_unique ON categories(lower(name));
@@ -0,0 +1,25 @@
+-- HPP (harga pokok) per item transaksi, disimpan saat checkout supaya laporan
+-- laba tidak berubah ketika cost_price produk diperbarui
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS cost_amount INT;
+
+-- Transaksi lama diisi dengan cost_price saat ini (perkiraan terbaik)
+UPDATE transaction_details td
+SET cost_amount = ROUND(td.base_quantity * p.cost_price)::int
+FROM products p
+WHERE p.id = td.product_id
+  AND td.cost_amount IS NULL
+  AND NOT EXISTS (SELECT 1 FROM transaction_detail_components c WHERE c.transaction_detail_id = td.id);
+
+UPDATE transaction_details td
+SET cost_amount = sub.cost
+FROM (
+  SELECT c.transaction_detail_id, ROUND(SUM(c.quantity * p.cost_price))::int AS cost
+  FROM transaction_detail_components c
+  JOIN products p ON p.id = c.product_id
+  GROUP BY c.transaction_detail_id
+) sub
+WHERE sub.transaction_detail_id = td.id AND td.cost_amount IS NULL;
+
+UPDATE transaction_details SET cost_amount = 0 WHERE cost_amount IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN cost_amount SET DEFAULT 0;
+ALTER TABLE transaction_details ALTER COLUMN cost_amount SET NOT NULL;
@@ -0,0 +1,18 @@
+-- Riwayat perubahan products.cost_price (HPP rata-rata), dipakai untuk
+-- menilai persediaan pada tanggal tertentu
+CREATE TABLE cost_history (
+  id SERIAL PRIMARY KEY,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  old_cost INT,
+  new_cost INT NOT NULL,
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_cost_history_product ON cost_history(product_id, changed_at);
+
+-- HPP yang sudah ada dicatat sebagai titik awal riwayat
+INSERT INTO cost_history (product_id, old_cost, new_cost, changed_at)
+SELECT id, NULL, cost_price, COALESCE(updated_at, created_at, NOW()) FROM products;
+
+-- Penilaian persediaan per tanggal menjumlahkan mutasi setelah tanggal tsb
+CREATE INDEX IF NOT EXISTS idx_stock_movements_created_at ON stock_movements(created_at);
@@ -0,0 +1,35 @@
+-- Rollup penjualan harian supaya laporan rentang tanggal tidak perlu
+-- memindai transaction_details. sales_date adalah tanggal transaksi di zona
+-- waktu toko. Diperbarui saat checkout; bisa dihitung ulang dengan perintah
+-- "rollup-rebuild".
+CREATE TABLE daily_sales (
+  sales_date DATE PRIMARY KEY,
+  revenue BIGINT NOT NULL DEFAULT 0,
+  transactions INT NOT NULL DEFAULT 0
+);
+
+-- transactions adalah jumlah transaksi yang memuat produk tersebut
+CREATE TABLE daily_product_sales (
+  sales_date DATE NOT NULL,
+  product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  quantity NUMERIC(16,3) NOT NULL DEFAULT 0,
+  revenue BIGINT NOT NULL DEFAULT 0,
+  cost BIGINT NOT NULL DEFAULT 0,
+  transactions INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (sales_date, product_id)
+);
+
+CREATE INDEX idx_daily_product_sales_product ON daily_product_sales(product_id, sales_date);
+
+-- Isi awal dari transaksi yang sudah ada. Sesi database memakai zona waktu
+-- toko (STORE_TIMEZONE), jadi created_at::date adalah tanggal toko.
+INSERT INTO daily_sales (sales_date, revenue, transactions)
+SELECT created_at::date, SUM(total_amount), COUNT(*)
+FROM transactions
+GROUP BY 1;
+
+INSERT INTO daily_product_sales (sales_date, product_id, quantity, revenue, cost, transactions)
+SELECT t.created_at::date, td.product_id, SUM(td.base_quantity), SUM(td.subtotal), SUM(td.cost_amount), COUNT(DISTINCT td.transaction_id)
+FROM transaction_details td
+JOIN transactions t ON t.id = td.transaction_id
+GROUP BY 1, 2;
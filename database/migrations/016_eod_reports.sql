@@ -0,0 +1,40 @@
+-- Arsip laporan tutup hari (Z-report). Satu laporan per tanggal bisnis;
+-- isi lengkap disimpan di data, revenue dan transactions diduplikasi untuk
+-- daftar arsip. Laporan tidak boleh diubah atau dihapus setelah dibuat.
+CREATE TABLE eod_reports (
+  id SERIAL PRIMARY KEY,
+  business_date DATE NOT NULL UNIQUE,
+  period_start TIMESTAMPTZ NOT NULL,
+  period_end TIMESTAMPTZ NOT NULL,
+  generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  revenue BIGINT NOT NULL,
+  transactions INT NOT NULL,
+  data JSONB NOT NULL
+);
+
+CREATE FUNCTION eod_reports_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'laporan tutup hari tidak bisa diubah atau dihapus';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER eod_reports_immutable
+  BEFORE UPDATE OR DELETE ON eod_reports
+  FOR EACH ROW EXECUTE FUNCTION eod_reports_immutable();
+
+CREATE TRIGGER eod_reports_no_truncate
+  BEFORE TRUNCATE ON eod_reports
+  FOR EACH STATEMENT EXECUTE FUNCTION eod_reports_immutable();
+
+-- Setiap percobaan pengiriman laporan ke sebuah channel (file, smtp,
+-- webhook). Pengiriman yang gagal dicoba lagi oleh scheduler.
+CREATE TABLE eod_report_deliveries (
+  id SERIAL PRIMARY KEY,
+  report_id INT NOT NULL REFERENCES eod_reports(id),
+  channel VARCHAR NOT NULL,
+  status VARCHAR NOT NULL CHECK (status IN ('sent', 'failed')),
+  error VARCHAR,
+  attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_eod_report_deliveries_report ON eod_report_deliveries(report_id, channel);
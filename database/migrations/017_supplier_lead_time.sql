@@ -0,0 +1,7 @@
+-- Lead time supplier (hari sejak PO dikirim sampai barang diterima), dipakai
+-- saran pemesanan ulang.
+ALTER TABLE suppliers ADD COLUMN IF NOT EXISTS lead_time_days INT NOT NULL DEFAULT 7
+  CHECK (lead_time_days >= 0);
+
+-- Mencari supplier terakhir sebuah produk dari riwayat purchase order
+CREATE INDEX IF NOT EXISTS idx_purchase_order_items_product_id ON purchase_order_items(product_id);
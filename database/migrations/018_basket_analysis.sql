@@ -0,0 +1,28 @@
+-- Hasil analisis keranjang belanja (produk yang sering dibeli bersamaan)
+-- dari transaksi dalam satu jendela waktu. Dihitung ulang seluruhnya oleh
+-- job harian atau perintah "basket-rebuild"; hanya hasil terakhir disimpan.
+CREATE TABLE basket_analysis (
+  id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  window_start TIMESTAMPTZ NOT NULL,
+  window_end TIMESTAMPTZ NOT NULL,
+  transactions INT NOT NULL,
+  generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Jumlah transaksi dalam jendela yang memuat produk
+CREATE TABLE basket_products (
+  product_id INT PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+  transactions INT NOT NULL
+);
+
+-- Jumlah transaksi yang memuat kedua produk; setiap pasangan disimpan sekali
+-- dengan product_a < product_b
+CREATE TABLE basket_pairs (
+  product_a INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  product_b INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  transactions INT NOT NULL,
+  PRIMARY KEY (product_a, product_b),
+  CHECK (product_a < product_b)
+);
+
+CREATE INDEX idx_basket_pairs_product_b ON basket_pairs(product_b);
@@ -0,0 +1,19 @@
+-- Pelanggan terdaftar. customer_group_id menentukan daftar harga saat
+-- checkout jika kasir tidak memilih kelompok lain. phone dipakai untuk
+-- promo WhatsApp dan unik jika diisi.
+CREATE TABLE customers (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR NOT NULL,
+  phone VARCHAR NOT NULL DEFAULT '',
+  email VARCHAR NOT NULL DEFAULT '',
+  customer_group_id INT REFERENCES customer_groups(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_customers_phone ON customers(phone) WHERE phone <> '';
+
+-- Pelanggan transaksi; NULL untuk pembeli umum
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS customer_id INT REFERENCES customers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_customer_id ON transactions(customer_id, created_at);
@@ -0,0 +1,28 @@
+// Package delivery mengirim laporan ke tujuan di luar aplikasi: folder file,
+// email lewat SMTP atau webhook HTTP.
+package delivery
+
+import "context"
+
+// Attachment adalah file yang ikut dikirim bersama pesan.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message adalah satu laporan yang akan dikirim. Text dipakai sebagai isi
+// email dan JSON sebagai body webhook.
+type Message struct {
+	Subject     string
+	Text        string
+	JSON        []byte
+	Attachments []Attachment
+}
+
+// Channel adalah satu tujuan pengiriman. Name dicatat di riwayat pengiriman,
+// jadi harus tetap sama antar restart.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
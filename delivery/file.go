@@ -0,0 +1,33 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+
+	"kasir-api/storage"
+)
+
+// FileChannel menyimpan lampiran pesan ke storage, misalnya folder yang
+// disinkronkan ke komputer pemilik toko.
+type FileChannel struct {
+	store storage.Storage
+}
+
+func NewFileChannel(store storage.Storage) *FileChannel {
+	return &FileChannel{store: store}
+}
+
+func (c *FileChannel) Name() string {
+	return "file"
+}
+
+// Send menulis setiap lampiran dengan nama file aslinya. File yang sudah ada
+// ditimpa, jadi pengiriman ulang aman.
+func (c *FileChannel) Send(ctx context.Context, msg *Message) error {
+	for _, a := range msg.Attachments {
+		if err := c.store.Put(ctx, a.Filename, bytes.NewReader(a.Data), int64(len(a.Data)), a.ContentType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// SMTPChannel mengirim pesan sebagai email dengan lampiran. Jika server
+// mendukung STARTTLS koneksi dienkripsi; login hanya dipakai jika username
+// diisi.
+type SMTPChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func NewSMTPChannel(host string, port int, username, password, from string, to []string) (*SMTPChannel, error) {
+	if host == "" || from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("SMTP butuh host, pengirim dan minimal satu penerima")
+	}
+	return &SMTPChannel{host: host, port: port, username: username, password: password, from: from, to: to}, nil
+}
+
+func (c *SMTPChannel) Name() string {
+	return "smtp"
+}
+
+func (c *SMTPChannel) Send(ctx context.Context, msg *Message) error {
+	body, err := c.buildEmail(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if c.username != "" {
+		auth = smtp.PlainAuth("", c.username, c.password, c.host)
+	}
+
+	// smtp.SendMail tidak menerima context, jadi dijalankan di goroutine
+	// supaya pemanggil tidak tertahan server yang tidak merespons
+	addr := net.JoinHostPort(c.host, fmt.Sprint(c.port))
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, c.from, c.to, body)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildEmail menyusun email multipart/mixed: teks lalu lampiran base64.
+func (c *SMTPChannel) buildEmail(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", c.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(c.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(part, []byte(msg.Text)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 menulis data sebagai base64 dengan baris maksimal 76 karakter
+// sesuai RFC 2045.
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
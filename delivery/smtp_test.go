@@ -0,0 +1,216 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpMail adalah satu email yang diterima fakeSMTP.
+type smtpMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// fakeSMTP menjalankan server SMTP minimal di listener lokal: tanpa STARTTLS
+// dan tanpa AUTH, cukup untuk smtp.SendMail. Setiap email yang diterima
+// dikirim ke channel yang dikembalikan.
+func fakeSMTP(t *testing.T) (host string, port int, mails <-chan smtpMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan smtpMail, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, received)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func serveSMTP(conn net.Conn, received chan<- smtpMail) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP test")
+
+	var m smtpMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL":
+			m.from = arg
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			m.to = append(m.to, arg)
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data = data
+			received <- m
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPChannelSend(t *testing.T) {
+	host, port, mails := fakeSMTP(t)
+	ch, err := NewSMTPChannel(host, port, "", "", "kasir@toko.test", []string{"pemilik@toko.test", "admin@toko.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PDF lebih panjang dari satu baris base64 dan berisi byte biner
+	pdf := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte{0x00, 0xff, '.', '\n'}, 100)...)
+	reportJSON := []byte(`{"business_date":"2026-10-18","sales":{"revenue":1500000}}`)
+	msg := &Message{
+		Subject: "Laporan Tutup Hari Toko Maju – 2026-10-18",
+		Text:    "Total revenue: Rp 1.500.000\n",
+		JSON:    []byte(`{"event":"eod_report"}`),
+		Attachments: []Attachment{
+			{Filename: "laporan-tutup-hari-2026-10-18.pdf", ContentType: "application/pdf", Data: pdf},
+			{Filename: "laporan-tutup-hari-2026-10-18.json", ContentType: "application/json", Data: reportJSON},
+		},
+	}
+	if err := ch.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var m smtpMail
+	select {
+	case m = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+	if m.from != "FROM:<kasir@toko.test>" {
+		t.Errorf("MAIL %s, want FROM:<kasir@toko.test>", m.from)
+	}
+	if len(m.to) != 2 {
+		t.Errorf("RCPT %v, want both recipients", m.to)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(m.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != msg.Subject {
+		t.Errorf("Subject = %q, want %q", subject, msg.Subject)
+	}
+	if got := parsed.Header.Get("To"); got != "pemilik@toko.test, admin@toko.test" {
+		t.Errorf("To = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, want multipart/mixed", parsed.Header.Get("Content-Type"))
+	}
+
+	type part struct {
+		contentType string
+		filename    string
+		data        []byte
+	}
+	var parts []part
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enc := p.Header.Get("Content-Transfer-Encoding"); enc != "base64" {
+			t.Errorf("part %q encoding = %q, want base64", p.Header.Get("Content-Type"), enc)
+		}
+		raw, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Fields(string(raw)) {
+			if len(line) > 76 {
+				t.Errorf("base64 line of %d characters, want at most 76", len(line))
+			}
+		}
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(raw)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part{contentType: p.Header.Get("Content-Type"), filename: p.FileName(), data: data})
+	}
+
+	want := []part{
+		{contentType: "text/plain; charset=utf-8", data: []byte(msg.Text)},
+		{contentType: "application/pdf", filename: "laporan-tutup-hari-2026-10-18.pdf", data: pdf},
+		{contentType: "application/json", filename: "laporan-tutup-hari-2026-10-18.json", data: reportJSON},
+	}
+	if len(parts) != len(want) {
+		t.Fatalf("got %d MIME parts, want %d", len(parts), len(want))
+	}
+	for i, w := range want {
+		p := parts[i]
+		if p.contentType != w.contentType || p.filename != w.filename || !bytes.Equal(p.data, w.data) {
+			t.Errorf("part %d = {%q %q %d bytes}, want {%q %q %d bytes}", i,
+				p.contentType, p.filename, len(p.data), w.contentType, w.filename, len(w.data))
+		}
+	}
+}
+
+func TestSMTPChannelSendContextCancelled(t *testing.T) {
+	// Server yang menerima koneksi tapi tidak pernah membalas
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	addr := ln.Addr().(*net.TCPAddr)
+
+	ch, err := NewSMTPChannel(addr.IP.String(), addr.Port, "", "", "kasir@toko.test", []string{"pemilik@toko.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := ch.Send(ctx, &Message{Subject: "x"}); err != context.DeadlineExceeded {
+		t.Errorf("Send err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,58 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookChannel mengirim JSON pesan dengan POST ke url. Jika secret diisi,
+// body ditandatangani HMAC-SHA256 di header X-Kasir-Signature supaya
+// penerima bisa memverifikasi asal pesan.
+type WebhookChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookChannel(url, secret string) (*WebhookChannel, error) {
+	if url == "" {
+		return nil, fmt.Errorf("URL webhook kosong")
+	}
+	return &WebhookChannel{url: url, secret: secret, client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, msg *Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(msg.JSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.secret))
+		mac.Write(msg.JSON)
+		req.Header.Set("X-Kasir-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook membalas %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package delivery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookChannelSignature(t *testing.T) {
+	const secret = "rahasia-webhook"
+	payload := []byte(`{"event":"eod_report","report":{"business_date":"2026-10-18"}}`)
+
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{"signed", secret},
+		{"unsigned", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody []byte
+			var gotHeader http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("method = %s, want POST", r.Method)
+				}
+				gotHeader = r.Header.Clone()
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			ch, err := NewWebhookChannel(server.URL, tt.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ch.Send(context.Background(), &Message{JSON: payload}); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			if string(gotBody) != string(payload) {
+				t.Errorf("body = %s, want %s", gotBody, payload)
+			}
+			if ct := gotHeader.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			signature := gotHeader.Get("X-Kasir-Signature")
+			if tt.secret == "" {
+				if signature != "" {
+					t.Errorf("X-Kasir-Signature = %q, want none without secret", signature)
+				}
+				return
+			}
+			// Verifikasi seperti yang dilakukan penerima: HMAC-SHA256 dari
+			// body mentah dengan secret bersama
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(gotBody)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(signature), []byte(want)) {
+				t.Errorf("X-Kasir-Signature = %q, want %q", signature, want)
+			}
+		})
+	}
+}
+
+func TestWebhookChannelErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "signature tidak valid", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ch, err := NewWebhookChannel(server.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ch.Send(context.Background(), &Message{JSON: []byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "signature tidak valid") {
+		t.Errorf("Send err = %v, want 401 with response body", err)
+	}
+}
@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/categories": {
             "get": {
-                "description": "Get list of all categories",
+                "description": "Get list of all categories with product count, units in stock and stock value (at price and at cost), including sub-categories",
                 "produces": [
                     "application/json"
                 ],
@@ -81,13 +81,48 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Category name already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/tree": {
+            "get": {
+                "description": "Get all categories as a tree: root categories with nested children, sorted by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get category tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
         "/categories/{id}": {
             "get": {
-                "description": "Get details of a single category",
+                "description": "Get details of a single category, including its path from the root category",
                 "produces": [
                     "application/json"
                 ],
@@ -126,7 +161,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing category",
+                "description": "Update an existing category. parent_id may not be the category itself or one of its descendants.",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,11 +208,17 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Category name already exists",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a category",
+                "description": "Delete a category in one transaction. strategy=reject (default) fails with 409 and the number of affected products if the category is still used; strategy=reassign\u0026to={id} moves its products and sub-categories to another category; strategy=detach removes the category from its products and moves sub-categories up one level.",
                 "produces": [
                     "application/json"
                 ],
@@ -192,6 +233,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reject, reassign or detach",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target category ID for strategy=reassign",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -199,19 +252,89 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or Category not found",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Invalid ID or Category not found",
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Category still used by products or sub-categories",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/produk": {
+            "get": {
+                "description": "Get a page of products in a category (sorted by name), by default including its sub-categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get products of a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include products of sub-categories (default true)",
+                        "name": "include_subcategories",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or Category not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid request or Category not found",
                         "schema": {
                             "type": "string"
                         }
@@ -221,7 +344,7 @@ const docTemplate = `{
         },
         "/checkout": {
             "post": {
-                "description": "Create a new transaction with multiple items",
+                "description": "Create a new transaction with multiple items. Wholesale price tiers (and the price list of customer_group_id, if given) are applied per item; each detail shows unit_price and the applied price_tier. customer_id attributes the sale to a registered customer; without customer_group_id, that customer's group price list is used.",
                 "consumes": [
                     "application/json"
                 ],
@@ -265,38 +388,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/produk": {
-            "get": {
-                "description": "Get list of all products. Can filter by name using query parameter.",
+        "/checkout/quote": {
+            "post": {
+                "description": "Price a cart exactly like checkout (units, wholesale tiers and customer group price list) without creating a transaction or deducting stock",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "transactions"
                 ],
-                "summary": "Get all products",
+                "summary": "Quote checkout prices",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter products by name (case-insensitive)",
-                        "name": "name",
-                        "in": "query"
+                        "description": "Checkout items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
+                }
+            }
+        },
+        "/customer-groups": {
+            "get": {
+                "description": "Get list of all customer groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customer-groups"
                 ],
+                "summary": "Get all customer groups",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Product"
+                                "$ref": "#/definitions/models.CustomerGroup"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new product",
+                "description": "Create a new customer group",
                 "consumes": [
                     "application/json"
                 ],
@@ -304,17 +459,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "customer-groups"
                 ],
-                "summary": "Create a new product",
+                "summary": "Create a new customer group",
                 "parameters": [
                     {
-                        "description": "New product data",
-                        "name": "product",
+                        "description": "New customer group data",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.CustomerGroup"
                         }
                     }
                 ],
@@ -322,7 +477,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.CustomerGroup"
                         }
                     },
                     "400": {
@@ -334,20 +489,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/produk/{id}": {
+        "/customer-groups/{id}": {
             "get": {
-                "description": "Get details of a single product",
+                "description": "Get details of a single customer group",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "customer-groups"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get customer group by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Customer group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -357,17 +512,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.CustomerGroup"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or Product not found",
+                        "description": "Invalid ID or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Invalid ID or Product not found",
+                        "description": "Invalid ID or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
@@ -375,7 +530,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing product",
+                "description": "Update an existing customer group",
                 "consumes": [
                     "application/json"
                 ],
@@ -383,24 +538,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "customer-groups"
                 ],
-                "summary": "Update product by ID",
+                "summary": "Update customer group by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Customer group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated product data",
-                        "name": "product",
+                        "description": "Updated customer group data",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.CustomerGroup"
                         }
                     }
                 ],
@@ -408,17 +563,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.CustomerGroup"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or Product not found",
+                        "description": "Invalid request or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Invalid request or Product not found",
+                        "description": "Invalid request or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
@@ -426,18 +581,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a product",
+                "description": "Delete a customer group (its price tiers are removed)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "customer-groups"
                 ],
-                "summary": "Delete product by ID",
+                "summary": "Delete customer group by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Customer group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -454,13 +609,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or Product not found",
+                        "description": "Invalid ID or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Invalid ID or Product not found",
+                        "description": "Invalid ID or Customer group not found",
                         "schema": {
                             "type": "string"
                         }
@@ -468,27 +623,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/report": {
+        "/customers": {
             "get": {
-                "description": "Get sales summary for a specific date range. If no dates provided, returns today's report.",
+                "description": "Get list of customers, optionally filtered by name or phone number",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "customers"
                 ],
-                "summary": "Get sales report by date range",
+                "summary": "Get all customers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "end_date",
+                        "description": "Part of the name or phone number",
+                        "name": "q",
                         "in": "query"
                     }
                 ],
@@ -496,17 +645,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SalesReport"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Customer"
+                            }
                         }
-                    },
-                    "400": {
-                        "description": "Invalid date format",
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new customer. The phone number is stored as digits with the 62 country code (0812... becomes 62812...) and must be unique. customer_group_id sets the default price list at checkout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Create a new customer",
+                "parameters": [
+                    {
+                        "description": "New customer data",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "string"
                         }
@@ -514,127 +692,4373 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/hari-ini": {
+        "/customers/{id}": {
             "get": {
-                "description": "Get sales summary for today including total revenue, total transactions, and best selling product",
+                "description": "Get details of a single customer",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reports"
+                    "customers"
+                ],
+                "summary": "Get customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get today's sales report",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SalesReport"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Invalid ID or Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Customer not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.BestSellingProduct": {
-            "type": "object",
-            "properties": {
-                "nama": {
-                    "type": "string"
-                },
-                "qty_terjual": {
+            },
+            "put": {
+                "description": "Update an existing customer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Update customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated customer data",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid request or Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a customer. Their transactions are kept as walk-in transactions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Delete customer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-schedules": {
+            "get": {
+                "description": "Get scheduled price changes across all products, earliest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all price schedules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, applied, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceSchedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk": {
+            "get": {
+                "description": "Get list of all products. Can filter by name using query parameter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter products by name (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by category, including all its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "New product data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in price history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/search": {
+            "get": {
+                "description": "Typo-tolerant search across product name, SKU, barcode and category name, ranked by relevance. Supports prefix matching for as-you-type search.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Search products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing search term",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}": {
+            "get": {
+                "description": "Get details of a single product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated product data",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User making the change, recorded in price history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid request or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/frequently-bought-with": {
+            "get": {
+                "description": "Products bought in the same transaction as this product, from the latest market basket analysis (recomputed daily over the last BASKET_WINDOW_DAYS full days). Support is the percentage of all transactions containing both products, confidence the percentage of this product's transactions that also contain the other product, and lift the confidence divided by the other product's overall purchase rate (above 1 means bought together more often than by chance). Sorted by confidence, then lift. analysis is null until the first analysis has run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get products frequently bought with a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of products (default 10, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum transactions containing both products (default 2)",
+                        "name": "min_transactions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FrequentlyBoughtWith"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/images": {
+            "get": {
+                "description": "Get all images of a product with original and thumbnail URLs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product images",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductImage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload a JPEG, PNG or GIF image (multipart field \"image\"). The file type is detected from its content and a thumbnail is generated.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Upload product image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Image too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/images/{imageId}": {
+            "delete": {
+                "description": "Delete an image and its thumbnail",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete product image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Image not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Image not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/lots": {
+            "get": {
+                "description": "Get lots of a lot-tracked product, earliest expiry first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get stock lots of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockLot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Receive stock for a lot-tracked product into a lot (created if it does not exist). Stock increases through the stock ledger.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Receive stock into a lot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lot data",
+                        "name": "lot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiveLotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockLot"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/price-history": {
+            "get": {
+                "description": "Get every change to the product's price (old, new, who, when, manual or scheduled), newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get price history of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceHistory"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/price-schedules": {
+            "get": {
+                "description": "Get scheduled price changes of a product, earliest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get price schedules of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, applied, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceSchedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid request or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedule a future price change. The in-process scheduler applies it once effective_at (RFC 3339, e.g. 2026-11-01T00:00:00+07:00) has passed and records it in the price history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Schedule a price change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New price and effective time",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceScheduleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User scheduling the change, recorded in price history",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/price-schedules/{scheduleId}": {
+            "delete": {
+                "description": "Cancel a pending scheduled price change",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Cancel a price schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Schedule is not pending",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Schedule not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produk/{id}/stock-movements": {
+            "get": {
+                "description": "Get the stock ledger (purchases, sales and adjustments) of a product, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get stock movements of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Product not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "description": "Get list of purchase orders. Can filter by status and supplier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Get all purchase orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (draft, sent, partially_received, received, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PurchaseOrder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid supplier_id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft purchase order with line items",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Create a new purchase order",
+                "parameters": [
+                    {
+                        "description": "New purchase order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "description": "Get a purchase order with its line items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Get purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Purchase order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Purchase order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace supplier, notes and line items of a draft purchase order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Update purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated purchase order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or purchase order is not a draft",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/cancel": {
+            "post": {
+                "description": "Cancel a draft or sent purchase order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Cancel purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Purchase order cannot be cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receive": {
+            "post": {
+                "description": "Receive some or all ordered items. Increments product stock through the stock ledger and updates cost price (weighted average).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Receive goods for a purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or purchase order cannot be received",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/send": {
+            "post": {
+                "description": "Mark a draft purchase order as sent to the supplier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase-orders"
+                ],
+                "summary": "Send purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Purchase order cannot be sent",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report": {
+            "get": {
+                "description": "Get sales summary for a specific date range. Days are counted in the store timezone (STORE_TIMEZONE). If no dates provided, returns today's report. With compare, the response includes a comparison with the previous period of the same length or the same dates last year: absolute and percentage change of revenue, transaction count, average basket and quantity sold per product.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get sales report by date range",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only count items of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "previous or last_year",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/abc": {
+            "get": {
+                "description": "Classify products into A, B and C by cumulative contribution to revenue or profit over a date range (store timezone). Products are sorted by contribution; a product is A while the cumulative share of the products before it is below cutoff_a, B while below cutoff_b, and C otherwise. Products with no positive contribution, including unsold ones, are C.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get ABC (Pareto) analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "revenue (default) or profit",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Cumulative percentage for class A (default 80)",
+                        "name": "cutoff_a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Cumulative percentage for class B (default 95)",
+                        "name": "cutoff_b",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ABCReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/bundle-components": {
+            "get": {
+                "description": "Get quantity and revenue of bundle sales attributed to each component product. Revenue is allocated proportionally to the components' regular prices. If no dates provided, returns today's data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get bundle revenue attributed to components",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BundleComponentSales"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/dead-stock": {
+            "get": {
+                "description": "Products with stock on hand that did not sell in the last N days (dead) or sold with a sell-through below slow_threshold percent (slow), sorted by stock value at cost. Sell-through is units sold / (units sold + current stock) over the period. Sales are taken from the stock ledger, so components sold inside bundles count as sold. Days are counted in the store timezone (STORE_TIMEZONE), including today.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get dead stock and slow movers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Period length in days (default 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sell-through percentage below which a product counts as slow (default 10, 0 lists dead stock only)",
+                        "name": "slow_threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeadStockReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/eod": {
+            "get": {
+                "description": "List archived end-of-day (Z) reports by business date, newest first, with the delivery status per channel. Without dates, returns the last 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "List archived end-of-day reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start business date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End business date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EODReportSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/eod/{date}": {
+            "get": {
+                "description": "Get the archived end-of-day (Z) report of a business date: sales totals, sales per category, top products and stock alerts at closing time, plus the delivery status per channel. The report never changes after it is generated.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get an archived end-of-day report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Business date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EODReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/expiring": {
+            "get": {
+                "description": "Get lots with remaining stock that expire within the given number of days, including lots that have already expired (which cannot be sold)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get expiring stock lots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Look-ahead window in days (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpiringLot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid days",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/hari-ini": {
+            "get": {
+                "description": "Get sales summary for today including total revenue, total transactions, and best selling product. Today follows the store timezone (STORE_TIMEZONE).",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get today's sales report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only count items of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/inventory": {
+            "get": {
+                "description": "Stock quantity and value at selling price and at average cost per product, per category and in total. With as_of, stock is reconstructed from the stock movement ledger and valued with the selling price and cost in effect at the end of that date (store timezone). Bundles are excluded because they have no stock of their own.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get inventory valuation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Value inventory at the end of this date (YYYY-MM-DD); default is now",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryValuation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/product-pairs": {
+            "get": {
+                "description": "Product pairs most often bought in the same transaction, from the latest market basket analysis (recomputed daily over the last BASKET_WINDOW_DAYS full days). Sorted by support (transactions containing both) or by lift. Confidence is given in both directions: confidence_a_to_b is the percentage of transactions with product A that also contain product B. analysis is null until the first analysis has run.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get top product pairs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "support (default) or lift",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pairs (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum transactions containing both products (default 2)",
+                        "name": "min_transactions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include pairs with at least one product in this category or its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPairsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/reorder-suggestions": {
+            "get": {
+                "description": "Forecast daily demand per product from its average sales per weekday over the last lookback_weeks full weeks (today excluded), estimate when the current stock runs out, and suggest an order quantity for products whose stock plus open purchase orders is at or below the reorder point. The reorder point is the forecast demand during the supplier lead time plus safety stock (1.65 x standard deviation of daily demand x square root of the lead time); the suggested quantity, rounded up to whole units, covers the lead time plus coverage_days. A product's supplier is the supplier of its latest non-cancelled purchase order, using that supplier's lead_time_days; products without purchase orders use default_lead_time_days. Sales are taken from the stock ledger, so components sold inside bundles count. Sorted by days until stockout.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get reorder suggestions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Weeks of sales history (default 8, max 52)",
+                        "name": "lookback_weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of demand to cover after the lead time (default 14)",
+                        "name": "coverage_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lead time for products without a supplier (default 7)",
+                        "name": "default_lead_time_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products whose latest supplier is this supplier",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorderReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Compute the reorder suggestions with the same parameters as GET and create a draft purchase order for supplier_id with every suggested product whose latest supplier is supplier_id, at the suggested quantity and the product's cost price. The draft can be edited before it is sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Create a draft purchase order from reorder suggestions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Weeks of sales history (default 8, max 52)",
+                        "name": "lookback_weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of demand to cover after the lead time (default 14)",
+                        "name": "coverage_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or nothing to reorder",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/rfm": {
+            "get": {
+                "description": "Score registered customers who bought in the last days days (today included, store timezone) on recency (days since last purchase), frequency (number of transactions) and monetary (total spent), each from 1 to 5 by percentile rank among the customers in the report; tied values get the same score. Customers are then grouped by R and the average of F and M (FM): champions (R\u003e=4, FM\u003e=4), loyal (R\u003e=3, FM\u003e=3), new (R\u003e=4), at_risk (R\u003c=2, FM\u003e=3), lost (R=1) and need_attention (the rest). Segment totals always cover all customers; segment only filters the customer list, e.g. segment=at_risk\u0026format=csv for a re-engagement list with WhatsApp-ready phone numbers. Walk-in transactions without customer_id are not included.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get RFM customer segmentation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days of history (default 365, max 3650)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include customers of this customer group",
+                        "name": "customer_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "champions, loyal, new, at_risk, need_attention or lost",
+                        "name": "segment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RFMReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/sales-by-category": {
+            "get": {
+                "description": "Group revenue, quantity sold (base unit) and transaction count by each product's own category over a date range in the store timezone, with each row's share of the period total in percent. Products without a category are grouped as Uncategorized (category_id null). If no dates provided, returns today's data.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get sales by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategorySalesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/sales-by-product": {
+            "get": {
+                "description": "Group revenue, quantity sold (base unit) and transaction count by product over a date range in the store timezone, with each row's share of the period total in percent. If no dates provided, returns today's data.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get sales by product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSalesReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/timeseries": {
+            "get": {
+                "description": "Get revenue, transaction count, items sold and average basket per hour, day, week (starting Monday) or month. Buckets follow the store timezone (STORE_TIMEZONE) and empty buckets are returned with zeros. If no dates provided, returns today's data.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get sales time-series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "hour, day (default), week or month",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesTimeSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/top-products": {
+            "get": {
+                "description": "Rank products by quantity sold (base unit), revenue or profit for a date range in the store timezone. order=bottom lists slow movers, including products without any sales. Ties are ordered by product name. Profit uses the cost price recorded at checkout. If no dates provided, returns today's data.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get top or bottom selling products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of products (default 20, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "qty (default), revenue or profit",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "top (default) or bottom",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only include products of this category and its sub-categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv, xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductRankingReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers": {
+            "get": {
+                "description": "Get list of all suppliers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Get all suppliers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Supplier"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Create a new supplier",
+                "parameters": [
+                    {
+                        "description": "New supplier data",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers/{id}": {
+            "get": {
+                "description": "Get details of a single supplier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Get supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Update supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated supplier data",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid request or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a supplier. Suppliers that still have purchase orders cannot be deleted and return 409 with the number of purchase orders.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppliers"
+                ],
+                "summary": "Delete supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid ID or Supplier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Supplier still used by purchase orders",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ABCClassSummary": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "type": "string"
+                },
+                "product_count": {
+                    "type": "integer"
+                },
+                "product_share": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "integer"
+                },
+                "value_share": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ABCProduct": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "class": {
+                    "type": "string"
+                },
+                "cumulative_share": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "profit": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "share": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ABCReport": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ABCClassSummary"
+                    }
+                },
+                "cutoff_a": {
+                    "type": "number"
+                },
+                "cutoff_b": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ABCProduct"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BasketAnalysis": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "integer"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BestSellingProduct": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "qty_terjual": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BundleComponent": {
+            "type": "object",
+            "properties": {
+                "component_id": {
+                    "type": "integer"
+                },
+                "component_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BundleComponentSales": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "hanya diisi di /categories/tree",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "dari root sampai kategori ini",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryBreadcrumb"
+                    }
+                },
+                "stats": {
+                    "$ref": "#/definitions/models.CategoryStats"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryBreadcrumb": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategorySales": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "quantity_share": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "revenue_share": {
+                    "type": "number"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategorySalesReport": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySales"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.SalesBreakdownTotals"
+                }
+            }
+        },
+        "models.CategoryStats": {
+            "type": "object",
+            "properties": {
+                "product_count": {
+                    "type": "integer"
+                },
+                "stock_cost_value": {
+                    "description": "stok x harga pokok",
+                    "type": "integer"
+                },
+                "stock_units": {
+                    "type": "number"
+                },
+                "stock_value": {
+                    "description": "stok x harga jual",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckoutItem": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit": {
+                    "description": "kosong berarti unit dasar produk",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "customer_group_id": {
+                    "description": "untuk daftar harga kelompok pelanggan",
+                    "type": "integer"
+                },
+                "customer_id": {
+                    "description": "pelanggan terdaftar; kelompoknya dipakai jika customer_group_id kosong",
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckoutItem"
+                    }
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_group_id": {
+                    "description": "daftar harga default saat checkout",
+                    "type": "integer"
+                },
+                "customer_group_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CustomerGroup": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeadStockItem": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cost_value": {
+                    "type": "integer"
+                },
+                "days_since_last_sale": {
+                    "type": "integer"
+                },
+                "last_sale_at": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "retail_value": {
+                    "type": "integer"
+                },
+                "sell_through": {
+                    "type": "number"
+                },
+                "sold_quantity": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeadStockReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStockItem"
+                    }
+                },
+                "slow_threshold": {
+                    "type": "number"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.DeadStockTotals"
+                }
+            }
+        },
+        "models.DeadStockTotals": {
+            "type": "object",
+            "properties": {
+                "cost_value": {
+                    "type": "integer"
+                },
+                "dead_products": {
+                    "type": "integer"
+                },
+                "retail_value": {
+                    "type": "integer"
+                },
+                "slow_products": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EODDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EODReport": {
+            "type": "object",
+            "properties": {
+                "business_date": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySales"
+                    }
+                },
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EODDelivery"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "sales": {
+                    "$ref": "#/definitions/models.EODSales"
+                },
+                "stock_alerts": {
+                    "$ref": "#/definitions/models.EODStockAlerts"
+                },
+                "store_name": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "top_products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductSalesRanked"
+                    }
+                }
+            }
+        },
+        "models.EODReportSummary": {
+            "type": "object",
+            "properties": {
+                "business_date": {
+                    "type": "string"
+                },
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EODDelivery"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EODSales": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "integer"
+                },
+                "first_transaction_at": {
+                    "type": "string"
+                },
+                "gross_profit": {
+                    "type": "integer"
+                },
+                "items_sold": {
+                    "type": "number"
+                },
+                "last_transaction_at": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EODStockAlerts": {
+            "type": "object",
+            "properties": {
+                "expiring_lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringLot"
+                    }
+                },
+                "out_of_stock": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OutOfStockProduct"
+                    }
+                }
+            }
+        },
+        "models.ExpiringLot": {
+            "type": "object",
+            "properties": {
+                "cost_value": {
+                    "type": "integer"
+                },
+                "days_until_expiry": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "initial_quantity": {
+                    "type": "number"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "received_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FrequentlyBoughtWith": {
+            "type": "object",
+            "properties": {
+                "analysis": {
+                    "$ref": "#/definitions/models.BasketAnalysis"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductAssociation"
+                    }
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "transaksi dalam jendela yang memuat produk ini",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryCategoryValue": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cost_value": {
+                    "type": "integer"
+                },
+                "product_count": {
+                    "type": "integer"
+                },
+                "retail_value": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.InventoryProductValue": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cost_price": {
+                    "type": "integer"
+                },
+                "cost_value": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "retail_value": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryTotals": {
+            "type": "object",
+            "properties": {
+                "cost_value": {
+                    "type": "integer"
+                },
+                "product_count": {
+                    "type": "integer"
+                },
+                "retail_value": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.InventoryValuation": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryCategoryValue"
+                    }
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryProductValue"
+                    }
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.InventoryTotals"
+                }
+            }
+        },
+        "models.MetricChange": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "integer"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "current": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OutOfStockProduct": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceHistory": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "old_price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceSchedule": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "effective_at": {
+                    "type": "string"
+                },
+                "new_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PriceTier": {
+            "type": "object",
+            "properties": {
+                "customer_group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "category_path": {
+                    "description": "breadcrumb dari kategori root",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryBreadcrumb"
+                    }
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleComponent"
+                    }
+                },
+                "cost_price": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImage"
+                    }
+                },
+                "is_bundle": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceTier"
+                    }
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "track_lots": {
+                    "type": "boolean"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductUnit"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductAssociation": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "lift": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "support": {
+                    "type": "number"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImage": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductPair": {
+            "type": "object",
+            "properties": {
+                "confidence_a_to_b": {
+                    "type": "number"
+                },
+                "confidence_b_to_a": {
+                    "type": "number"
+                },
+                "lift": {
+                    "type": "number"
+                },
+                "product_a_id": {
+                    "type": "integer"
+                },
+                "product_a_name": {
+                    "type": "string"
+                },
+                "product_b_id": {
+                    "type": "integer"
+                },
+                "product_b_name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "support": {
+                    "type": "number"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductPairsReport": {
+            "type": "object",
+            "properties": {
+                "analysis": {
+                    "$ref": "#/definitions/models.BasketAnalysis"
+                },
+                "by": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductPair"
+                    }
+                },
+                "min_transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductQuantityChange": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "current_quantity": {
+                    "type": "number"
+                },
+                "previous_quantity": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductRankingReport": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductSalesRanked"
+                    }
+                },
+                "order": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductSales": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "quantity_share": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "revenue_share": {
+                    "type": "number"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductSalesRanked": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "profit": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductSalesReport": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductSales"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.SalesBreakdownTotals"
+                }
+            }
+        },
+        "models.ProductSearchResult": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "category_path": {
+                    "description": "breadcrumb dari kategori root",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryBreadcrumb"
+                    }
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleComponent"
+                    }
+                },
+                "cost_price": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImage"
+                    }
+                },
+                "is_bundle": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceTier"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "track_lots": {
+                    "type": "boolean"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductUnit"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductUnit": {
+            "type": "object",
+            "properties": {
+                "factor": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderItem"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "supplier_name": {
+                    "type": "string"
+                },
+                "total_cost": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurchaseOrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "received_quantity": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RFMCustomer": {
+            "type": "object",
+            "properties": {
+                "customer_group_id": {
+                    "type": "integer"
+                },
+                "customer_group_name": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "integer"
+                },
+                "frequency_score": {
+                    "type": "integer"
+                },
+                "last_purchase_at": {
+                    "type": "string"
+                },
+                "monetary": {
+                    "type": "integer"
+                },
+                "monetary_score": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "recency_days": {
+                    "type": "integer"
+                },
+                "recency_score": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "string"
+                },
+                "segment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RFMReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "customer_group_id": {
+                    "type": "integer"
+                },
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RFMCustomer"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "segment": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RFMSegmentSummary"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RFMSegmentSummary": {
+            "type": "object",
+            "properties": {
+                "customer_share": {
+                    "type": "number"
+                },
+                "customers": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "revenue_share": {
+                    "type": "number"
+                },
+                "segment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReceiveItem": {
+            "type": "object",
+            "properties": {
+                "expiry_date": {
+                    "description": "YYYY-MM-DD, untuk produk dengan track_lots",
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit_cost": {
                     "type": "integer"
                 }
             }
         },
-        "models.Category": {
+        "models.ReceiveLotRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "expiry_date": {
+                    "description": "YYYY-MM-DD, kosong untuk barang tanpa kedaluwarsa",
                     "type": "string"
                 },
-                "description": {
+                "lot_number": {
                     "type": "string"
                 },
-                "id": {
+                "quantity": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReceiveRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiveItem"
+                    }
+                }
+            }
+        },
+        "models.ReorderReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "category_id": {
                     "type": "integer"
                 },
-                "name": {
+                "coverage_days": {
+                    "type": "integer"
+                },
+                "default_lead_time_days": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReorderSuggestion"
+                    }
+                },
+                "lookback_weeks": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.ReorderTotals"
+                }
+            }
+        },
+        "models.ReorderSuggestion": {
+            "type": "object",
+            "properties": {
+                "average_daily_demand": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "days_until_stockout": {
+                    "type": "integer"
+                },
+                "estimated_cost": {
+                    "type": "integer"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "on_order": {
+                    "description": "belum diterima dari PO sent/partially_received",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "reorder_point": {
+                    "type": "number"
+                },
+                "safety_stock": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "stockout_date": {
+                    "type": "string"
+                },
+                "suggested_quantity": {
+                    "type": "number"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "supplier_name": {
+                    "type": "string"
+                },
+                "unit": {
                     "type": "string"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                },
+                "weekday_demand": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
                 }
             }
         },
-        "models.CheckoutItem": {
+        "models.ReorderTotals": {
             "type": "object",
             "properties": {
-                "product_id": {
+                "estimated_cost": {
                     "type": "integer"
                 },
+                "products": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesBreakdownTotals": {
+            "type": "object",
+            "properties": {
                 "quantity": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "transactions": {
                     "type": "integer"
                 }
             }
         },
-        "models.CheckoutRequest": {
+        "models.SalesBucket": {
             "type": "object",
             "properties": {
-                "items": {
+                "average_basket": {
+                    "description": "revenue / transactions, dibulatkan ke Rupiah",
+                    "type": "integer"
+                },
+                "items_sold": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesComparison": {
+            "type": "object",
+            "properties": {
+                "average_basket": {
+                    "$ref": "#/definitions/models.MetricChange"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "previous_end_date": {
+                    "type": "string"
+                },
+                "previous_start_date": {
+                    "type": "string"
+                },
+                "products": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.CheckoutItem"
+                        "$ref": "#/definitions/models.ProductQuantityChange"
                     }
+                },
+                "revenue": {
+                    "$ref": "#/definitions/models.MetricChange"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "$ref": "#/definitions/models.MetricChange"
                 }
             }
         },
-        "models.Product": {
+        "models.SalesReport": {
             "type": "object",
             "properties": {
-                "category_id": {
+                "comparison": {
+                    "$ref": "#/definitions/models.SalesComparison"
+                },
+                "produk_terlaris": {
+                    "$ref": "#/definitions/models.BestSellingProduct"
+                },
+                "total_revenue": {
                     "type": "integer"
                 },
-                "category_name": {
+                "total_transaksi": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesTimeSeries": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalesBucket"
+                    }
+                },
+                "end_date": {
                     "type": "string"
                 },
-                "created_at": {
+                "granularity": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockLot": {
+            "type": "object",
+            "properties": {
+                "expiry_date": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "initial_quantity": {
+                    "type": "number"
+                },
+                "lot_number": {
                     "type": "string"
                 },
-                "price": {
+                "product_id": {
                     "type": "integer"
                 },
-                "stock": {
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "quantity": {
+                    "type": "number"
+                },
+                "received_at": {
                     "type": "string"
                 }
             }
         },
-        "models.SalesReport": {
+        "models.StockMovement": {
             "type": "object",
             "properties": {
-                "produk_terlaris": {
-                    "$ref": "#/definitions/models.BestSellingProduct"
+                "created_at": {
+                    "type": "string"
                 },
-                "total_revenue": {
+                "id": {
                     "type": "integer"
                 },
-                "total_transaksi": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reference_id": {
+                    "type": "integer"
+                },
+                "reference_type": {
+                    "type": "string"
+                },
+                "stock_after": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Supplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "contact_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lead_time_days": {
+                    "description": "hari dari PO dikirim sampai diterima; kosong = 7 saat create, tidak diubah saat update",
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
@@ -644,6 +5068,12 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "customer_group_id": {
+                    "type": "integer"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
                 "details": {
                     "type": "array",
                     "items": {
@@ -661,9 +5091,32 @@ const docTemplate = `{
         "models.TransactionDetail": {
             "type": "object",
             "properties": {
+                "base_quantity": {
+                    "type": "number"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionDetailComponent"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionDetailLot"
+                    }
+                },
+                "price_tier": {
+                    "description": "tier grosir yang dipakai, kosong jika harga normal",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PriceTier"
+                        }
+                    ]
+                },
                 "product_id": {
                     "type": "integer"
                 },
@@ -671,13 +5124,56 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "quantity": {
-                    "type": "integer"
+                    "type": "number"
                 },
                 "subtotal": {
                     "type": "integer"
                 },
                 "transaction_id": {
                     "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransactionDetailComponent": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransactionDetailLot": {
+            "type": "object",
+            "properties": {
+                "expiry_date": {
+                    "type": "string"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
                 }
             }
         }
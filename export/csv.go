@@ -0,0 +1,48 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// WriteCSV menulis report sebagai CSV. Header laporan ditulis sebagai baris
+// label,nilai di atas tabel. File diawali BOM UTF-8 supaya Excel membaca
+// karakter non-ASCII dengan benar.
+func WriteCSV(w io.Writer, report *Report) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	records := [][]string{{escapeFormula(report.Title)}}
+	for _, f := range report.Fields {
+		records = append(records, []string{escapeFormula(f.Label), escapeFormula(f.Value)})
+	}
+	records = append(records, []string{}, report.Columns)
+	for _, row := range report.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = cell.String()
+			if cell.Kind == KindText {
+				record[i] = escapeFormula(record[i])
+			}
+		}
+		records = append(records, record)
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// escapeFormula memberi awalan ' pada teks yang akan dibaca spreadsheet
+// sebagai formula (CSV injection), misalnya nama produk "=HYPERLINK(...)".
+// Hanya untuk teks bebas; angka dan Rupiah negatif tidak boleh diubah.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	report := &Report{
+		Title: "Laporan Penjualan",
+		Fields: []Field{
+			{Label: "Toko", Value: "Toko Maju, Cabang \"Pusat\""},
+			{Label: "Periode", Value: "01-10-2026 s/d 07-10-2026"},
+		},
+		Columns: []string{"Produk", "Qty", "Revenue", "Margin"},
+		Rows: [][]Cell{
+			{Text("Kopi, Susu"), Number(1.5, "1.5"), Money(1250000), Percent(12.5)},
+			{Text("Baris\nbaru"), Int(2), Money(-3500), Percent(-4)},
+			{Text("=HYPERLINK(\"http://x\")"), Int(-1), Money(0), Percent(0)},
+			{Text("+62 812"), Int(0), Money(1), Percent(100)},
+			{Text("-diskon"), Int(0), Money(0), Percent(0)},
+			{Text("@SUM(A1)"), Int(0), Money(0), Percent(0)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\xEF\xBB\xBF")) {
+		t.Fatalf("CSV does not start with a UTF-8 BOM: % x", buf.Bytes()[:3])
+	}
+
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\uFEFF")))
+	r.FieldsPerRecord = -1
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// encoding/csv melewati baris kosong pemisah header dan tabel
+	want := [][]string{
+		{"Laporan Penjualan"},
+		{"Toko", "Toko Maju, Cabang \"Pusat\""},
+		{"Periode", "01-10-2026 s/d 07-10-2026"},
+		{"Produk", "Qty", "Revenue", "Margin"},
+		{"Kopi, Susu", "1.5", "Rp 1.250.000", "12.50%"},
+		{"Baris\nbaru", "2", "-Rp 3.500", "-4.00%"},
+		{"'=HYPERLINK(\"http://x\")", "-1", "Rp 0", "0.00%"},
+		{"'+62 812", "0", "Rp 1", "100.00%"},
+		{"'-diskon", "0", "Rp 0", "0.00%"},
+		{"'@SUM(A1)", "0", "Rp 0", "0.00%"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("records =\n%q\nwant\n%q", got, want)
+	}
+	if !strings.Contains(buf.String(), "Periode,01-10-2026 s/d 07-10-2026\n\nProduk,") {
+		t.Errorf("header block is not separated from the table by an empty line:\n%s", buf.String())
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Indomie", "Indomie"},
+		{"=1+1", "'=1+1"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@A1", "'@A1"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+		{"'=1", "'=1"},
+	}
+	for _, tt := range tests {
+		if got := escapeFormula(tt.in); got != tt.want {
+			t.Errorf("escapeFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
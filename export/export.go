@@ -0,0 +1,140 @@
+// Package export menulis laporan tabular ke file CSV, XLSX dan PDF tanpa
+// dependency eksternal.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format file yang didukung.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// CellKind menentukan cara sebuah sel diformat dan diratakan.
+type CellKind int
+
+const (
+	KindText CellKind = iota
+	KindNumber
+	KindMoney   // Rupiah tanpa desimal
+	KindPercent // nilai sudah dalam persen, misalnya 12.5 berarti 12,5%
+)
+
+type Cell struct {
+	Kind  CellKind
+	Text  string
+	Value float64
+}
+
+func Text(s string) Cell {
+	return Cell{Kind: KindText, Text: s}
+}
+
+func Int(n int) Cell {
+	return Cell{Kind: KindNumber, Value: float64(n)}
+}
+
+// Number untuk nilai pecahan seperti quantity; text adalah representasi yang
+// ditampilkan di CSV dan PDF.
+func Number(value float64, text string) Cell {
+	return Cell{Kind: KindNumber, Value: value, Text: text}
+}
+
+func Money(n int) Cell {
+	return Cell{Kind: KindMoney, Value: float64(n)}
+}
+
+func Percent(p float64) Cell {
+	return Cell{Kind: KindPercent, Value: p}
+}
+
+// String mengembalikan teks sel seperti yang ditampilkan di CSV dan PDF.
+func (c Cell) String() string {
+	switch c.Kind {
+	case KindMoney:
+		return FormatRupiah(int64(c.Value))
+	case KindPercent:
+		return strconv.FormatFloat(c.Value, 'f', 2, 64) + "%"
+	case KindNumber:
+		if c.Text != "" {
+			return c.Text
+		}
+		return strconv.FormatFloat(c.Value, 'f', -1, 64)
+	default:
+		return c.Text
+	}
+}
+
+// Field adalah satu baris header laporan, misalnya periode atau nama toko.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Report adalah laporan yang siap diekspor: judul, blok header, lalu tabel.
+type Report struct {
+	Title   string
+	Fields  []Field
+	Columns []string
+	Rows    [][]Cell
+}
+
+// Supported melaporkan apakah format bisa diekspor.
+func Supported(format string) bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatPDF:
+		return true
+	}
+	return false
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Write menulis report dalam format yang diminta.
+func Write(w io.Writer, format string, report *Report) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, report)
+	case FormatXLSX:
+		return WriteXLSX(w, report)
+	case FormatPDF:
+		return WritePDF(w, report)
+	}
+	return fmt.Errorf("format %q tidak didukung", format)
+}
+
+// FormatRupiah memformat n sebagai Rupiah dengan pemisah ribuan titik,
+// misalnya "Rp 1.250.000".
+func FormatRupiah(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	digits := strconv.FormatInt(n, 10)
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "Rp " + b.String()
+}
@@ -0,0 +1,31 @@
+package export
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "Rp 0"},
+		{5, "Rp 5"},
+		{999, "Rp 999"},
+		{1000, "Rp 1.000"},
+		{12345, "Rp 12.345"},
+		{100000, "Rp 100.000"},
+		{1250000, "Rp 1.250.000"},
+		{1000000000, "Rp 1.000.000.000"},
+		{-1, "-Rp 1"},
+		{-1000, "-Rp 1.000"},
+		{-1250000, "-Rp 1.250.000"},
+		{math.MaxInt64, "Rp 9.223.372.036.854.775.807"},
+	}
+	for _, tt := range tests {
+		if got := FormatRupiah(tt.n); got != tt.want {
+			t.Errorf("FormatRupiah(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
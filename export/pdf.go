@@ -0,0 +1,235 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Ukuran halaman A4 landscape dalam point.
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 36.0
+	pdfFontSize   = 9.0
+	pdfLineHeight = 14.0
+	pdfCellPad    = 4.0
+)
+
+// helveticaWidths adalah lebar glyph Helvetica (per 1000 unit em) untuk
+// karakter ASCII 32-126, diambil dari metrik AFM standar.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth memperkirakan lebar teks dalam point. Karakter di luar ASCII
+// dihitung selebar huruf rata-rata; teks tebal sedikit lebih lebar.
+func textWidth(s string, size float64, bold bool) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * size / 1000
+	if bold {
+		w *= 1.06
+	}
+	return w
+}
+
+// pdfText mengubah teks menjadi string literal PDF dengan encoding
+// WinAnsi. Karakter yang tidak bisa direpresentasikan diganti "?".
+func pdfText(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r <= 126:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// truncateText memotong s dengan "..." supaya muat di lebar width.
+func truncateText(s string, width float64, bold bool) string {
+	if textWidth(s, pdfFontSize, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		t := string(runes) + "..."
+		if textWidth(t, pdfFontSize, bold) <= width {
+			return t
+		}
+	}
+	return ""
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+	y       float64
+}
+
+func (p *pdfPage) text(x, y float64, s string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfText(s))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// WritePDF menulis report sebagai PDF A4 landscape dengan font Helvetica
+// bawaan. Tabel dipecah ke beberapa halaman dan header kolom diulang di
+// setiap halaman.
+func WritePDF(w io.Writer, report *Report) error {
+	widths := pdfColumnWidths(report)
+	tableWidth := 0.0
+	for _, cw := range widths {
+		tableWidth += cw
+	}
+
+	var pages []*pdfPage
+	var page *pdfPage
+	newPage := func() {
+		page = &pdfPage{y: pdfPageHeight - pdfMargin}
+		pages = append(pages, page)
+	}
+	tableHeader := func() {
+		x := pdfMargin
+		for i, col := range report.Columns {
+			page.text(x+pdfCellPad, page.y-pdfFontSize, truncateText(col, widths[i]-2*pdfCellPad, true), pdfFontSize, true)
+			x += widths[i]
+		}
+		page.y -= pdfLineHeight
+		page.line(pdfMargin, page.y+3, pdfMargin+tableWidth, page.y+3)
+	}
+
+	newPage()
+	page.text(pdfMargin, page.y-14, report.Title, 14, true)
+	page.y -= 24
+	for _, f := range report.Fields {
+		page.text(pdfMargin, page.y-pdfFontSize, f.Label, pdfFontSize, true)
+		page.text(pdfMargin+100, page.y-pdfFontSize, f.Value, pdfFontSize, false)
+		page.y -= pdfLineHeight
+	}
+	page.y -= pdfLineHeight
+	tableHeader()
+
+	for _, row := range report.Rows {
+		if page.y-pdfLineHeight < pdfMargin+pdfLineHeight {
+			newPage()
+			tableHeader()
+		}
+		x := pdfMargin
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			s := truncateText(cell.String(), widths[i]-2*pdfCellPad, false)
+			tx := x + pdfCellPad
+			if cell.Kind != KindText {
+				tx = x + widths[i] - pdfCellPad - textWidth(s, pdfFontSize, false)
+			}
+			page.text(tx, page.y-pdfFontSize, s, pdfFontSize, false)
+			x += widths[i]
+		}
+		page.y -= pdfLineHeight
+	}
+
+	for i, p := range pages {
+		footer := fmt.Sprintf("Halaman %d dari %d", i+1, len(pages))
+		p.text(pdfPageWidth-pdfMargin-textWidth(footer, 8, false), pdfMargin/2, footer, 8, false)
+	}
+
+	return writePDFDocument(w, pages)
+}
+
+// pdfColumnWidths membagi lebar halaman ke kolom sesuai panjang isi
+// terpanjang masing-masing kolom.
+func pdfColumnWidths(report *Report) []float64 {
+	widths := make([]float64, len(report.Columns))
+	for i, col := range report.Columns {
+		widths[i] = textWidth(col, pdfFontSize, true)
+	}
+	for _, row := range report.Rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], textWidth(cell.String(), pdfFontSize, false))
+			}
+		}
+	}
+
+	available := pdfPageWidth - 2*pdfMargin
+	total := 0.0
+	for i := range widths {
+		widths[i] += 2 * pdfCellPad
+		total += widths[i]
+	}
+	if total > available {
+		// Kolom diperkecil proporsional; teks yang tidak muat dipotong
+		for i := range widths {
+			widths[i] = widths[i] * available / total
+		}
+	}
+	return widths
+}
+
+// writePDFDocument menyusun objek PDF: catalog, pages, dua font, lalu
+// pasangan page dan content stream untuk setiap halaman.
+func writePDFDocument(w io.Writer, pages []*pdfPage) error {
+	var buf bytes.Buffer
+	var offsets []int
+	addObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	addObject("<< /Type /Catalog /Pages 2 0 R >>")
+	addObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	addObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	addObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range pages {
+		addObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		addObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Index style di styles.xml.
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleBold
+	xlsxStyleMoney
+	xlsxStylePercent
+	xlsxStyleTitle
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Laporan" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+// Urutan cellXfs harus sama dengan konstanta xlsxStyle*. Uang disimpan
+// sebagai angka dengan format Rupiah supaya tetap bisa dijumlahkan di Excel.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2">
+<numFmt numFmtId="164" formatCode="&quot;Rp &quot;#,##0"/>
+<numFmt numFmtId="165" formatCode="0.00&quot;%&quot;"/>
+</numFmts>
+<fonts count="3">
+<font><sz val="11"/><name val="Calibri"/></font>
+<font><b/><sz val="11"/><name val="Calibri"/></font>
+<font><b/><sz val="14"/><name val="Calibri"/></font>
+</fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="5">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+// WriteXLSX menulis report sebagai workbook Excel dengan satu sheet. File
+// dibangun langsung dari XML SpreadsheetML minimal.
+func WriteXLSX(w io.Writer, report *Report) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name, body string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", xlsxSheet(report)},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func xlsxSheet(report *Report) string {
+	var rows strings.Builder
+	rowNum := 0
+	widths := make([]int, len(report.Columns))
+	if len(widths) < 2 {
+		widths = append(widths, make([]int, 2-len(widths))...)
+	}
+	track := func(col int, s string) {
+		if col < len(widths) {
+			widths[col] = max(widths[col], utf8.RuneCountInString(s))
+		}
+	}
+
+	writeRow := func(cells []string) {
+		rowNum++
+		fmt.Fprintf(&rows, `<row r="%d">%s</row>`, rowNum, strings.Join(cells, ""))
+	}
+	textCell := func(col int, s string, style int) string {
+		return fmt.Sprintf(`<c r="%s%d" t="inlineStr" s="%d"><is><t xml:space="preserve">%s</t></is></c>`,
+			xlsxColumn(col), rowNum+1, style, xmlEscape(s))
+	}
+
+	writeRow([]string{textCell(0, report.Title, xlsxStyleTitle)})
+	for _, f := range report.Fields {
+		track(0, f.Label)
+		writeRow([]string{textCell(0, f.Label, xlsxStyleBold), textCell(1, f.Value, xlsxStyleDefault)})
+	}
+	rowNum++ // baris kosong pemisah header dan tabel
+
+	header := make([]string, len(report.Columns))
+	for i, col := range report.Columns {
+		track(i, col)
+		header[i] = textCell(i, col, xlsxStyleBold)
+	}
+	writeRow(header)
+
+	for _, row := range report.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			track(i, cell.String())
+			switch cell.Kind {
+			case KindText:
+				cells[i] = textCell(i, cell.Text, xlsxStyleDefault)
+			default:
+				style := xlsxStyleDefault
+				if cell.Kind == KindMoney {
+					style = xlsxStyleMoney
+				} else if cell.Kind == KindPercent {
+					style = xlsxStylePercent
+				}
+				cells[i] = fmt.Sprintf(`<c r="%s%d" s="%d"><v>%s</v></c>`,
+					xlsxColumn(i), rowNum+1, style, strconv.FormatFloat(cell.Value, 'f', -1, 64))
+			}
+		}
+		writeRow(cells)
+	}
+
+	var cols strings.Builder
+	for i, width := range widths {
+		fmt.Fprintf(&cols, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, min(max(width+2, 10), 60))
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<cols>` + cols.String() + `</cols>
+<sheetData>` + rows.String() + `</sheetData>
+</worksheet>`
+}
+
+// xlsxColumn mengubah index kolom (mulai 0) menjadi huruf kolom Excel:
+// 0 -> A, 25 -> Z, 26 -> AA.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"kasir-api/export"
+	"kasir-api/repositories"
+	"kasir-api/services"
+)
+
+type BasketHandler struct {
+	service *services.BasketService
+	reports *ReportHandler
+}
+
+// NewBasketHandler membuat handler analisis keranjang. reports dipakai untuk
+// menulis laporan pasangan produk dalam format ekspor yang sama dengan
+// laporan lain.
+func NewBasketHandler(service *services.BasketService, reports *ReportHandler) *BasketHandler {
+	return &BasketHandler{service: service, reports: reports}
+}
+
+// HandleFrequentlyBoughtWith godoc
+// @Summary Get products frequently bought with a product
+// @Description Products bought in the same transaction as this product, from the latest market basket analysis (recomputed daily over the last BASKET_WINDOW_DAYS full days). Support is the percentage of all transactions containing both products, confidence the percentage of this product's transactions that also contain the other product, and lift the confidence divided by the other product's overall purchase rate (above 1 means bought together more often than by chance). Sorted by confidence, then lift. analysis is null until the first analysis has run.
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of products (default 10, max 500)"
+// @Param min_transactions query int false "Minimum transactions containing both products (default 2)"
+// @Success 200 {object} models.FrequentlyBoughtWith
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Product not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /produk/{id}/frequently-bought-with [get]
+func (h *BasketHandler) HandleFrequentlyBoughtWith(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/frequently-bought-with")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+	limit, minTransactions, err := parseBasketParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.GetFrequentlyBoughtWith(id, limit, minTransactions)
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleProductPairs godoc
+// @Summary Get top product pairs
+// @Description Product pairs most often bought in the same transaction, from the latest market basket analysis (recomputed daily over the last BASKET_WINDOW_DAYS full days). Sorted by support (transactions containing both) or by lift. Confidence is given in both directions: confidence_a_to_b is the percentage of transactions with product A that also contain product B. analysis is null until the first analysis has run.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param by query string false "support (default) or lift"
+// @Param limit query int false "Number of pairs (default 50, max 500)"
+// @Param min_transactions query int false "Minimum transactions containing both products (default 2)"
+// @Param category_id query int false "Only include pairs with at least one product in this category or its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.ProductPairsReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/product-pairs [get]
+func (h *BasketHandler) HandleProductPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, minTransactions, err := parseBasketParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetProductPairs(categoryID, r.URL.Query().Get("by"), limit, minTransactions)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	// Periode file ekspor adalah jendela analisis dalam tanggal lokal toko
+	startDate := h.reports.reportService.Today()
+	endDate := startDate
+	if report.Analysis != nil {
+		location := h.reports.reportService.Location()
+		y, m, d := report.Analysis.WindowStart.In(location).Date()
+		startDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		y, m, d = report.Analysis.WindowEnd.In(location).AddDate(0, 0, -1).Date()
+		endDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	h.reports.writeReport(w, r, report, reportExport{
+		name: "pasangan-produk", title: "Pasangan Produk yang Sering Dibeli Bersama", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return productPairsExportRows(report) },
+	})
+}
+
+// parseBasketParams membaca query limit dan min_transactions; kosong berarti
+// 0 (default service).
+func parseBasketParams(r *http.Request) (int, int, error) {
+	limit, minTransactions := 0, 0
+	var err error
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			return 0, 0, errors.New("Invalid limit")
+		}
+	}
+	if s := r.URL.Query().Get("min_transactions"); s != "" {
+		if minTransactions, err = strconv.Atoi(s); err != nil {
+			return 0, 0, errors.New("Invalid min_transactions")
+		}
+	}
+	return limit, minTransactions, nil
+}
@@ -2,24 +2,28 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"kasir-api/models"
+	"kasir-api/repositories"
 	"kasir-api/services"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 )
 
 type CategoryHandler struct {
-	service *services.CategoryService
+	service        *services.CategoryService
+	productService *services.ProductService
 }
 
-func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
-	return &CategoryHandler{service: service}
+func NewCategoryHandler(service *services.CategoryService, productService *services.ProductService) *CategoryHandler {
+	return &CategoryHandler{service: service, productService: productService}
 }
 
 // HandleCategories - GET /api/categories, POST /api/categories
 // @Summary Get all categories
-// @Description Get list of all categories
+// @Description Get list of all categories with product count, units in stock and stock value (at price and at cost), including sub-categories
 // @Tags categories
 // @Produce json
 // @Success 200 {array} models.Category
@@ -54,6 +58,7 @@ func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Param category body models.Category true "New category data"
 // @Success 201 {object} models.Category
 // @Failure 400 {string} string "Invalid request body"
+// @Failure 409 {string} string "Category name already exists"
 // @Router /categories [post]
 func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var category models.Category
@@ -64,6 +69,10 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.service.Create(&category)
+	if errors.Is(err, repositories.ErrCategoryNameTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -74,8 +83,37 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(category)
 }
 
+// HandleTree godoc
+// @Summary Get category tree
+// @Description Get all categories as a tree: root categories with nested children, sorted by name
+// @Tags categories
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 500 {string} string "Internal server error"
+// @Router /categories/tree [get]
+func (h *CategoryHandler) HandleTree(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tree, err := h.service.GetTree()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
 // HandleCategoryByID - GET/PUT/DELETE /api/categories/{id}
 func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/produk") {
+		h.HandleCategoryProducts(w, r)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.GetByID(w, r)
@@ -89,7 +127,7 @@ func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Requ
 }
 
 // @Summary Get category by ID
-// @Description Get details of a single category
+// @Description Get details of a single category, including its path from the root category
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
@@ -115,7 +153,7 @@ func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Update category by ID
-// @Description Update an existing category
+// @Description Update an existing category. parent_id may not be the category itself or one of its descendants.
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -123,6 +161,7 @@ func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Param category body models.Category true "Updated category data"
 // @Success 200 {object} models.Category
 // @Failure 400,404 {string} string "Invalid request or Category not found"
+// @Failure 409 {string} string "Category name already exists"
 // @Router /categories/{id} [put]
 func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/categories/")
@@ -141,6 +180,14 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	category.ID = id
 	err = h.service.Update(&category)
+	if errors.Is(err, repositories.ErrCategoryNameTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, repositories.ErrCategoryNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -151,12 +198,17 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete category by ID
-// @Description Delete a category
+// @Description Delete a category in one transaction. strategy=reject (default) fails with 409 and the number of affected products if the category is still used; strategy=reassign&to={id} moves its products and sub-categories to another category; strategy=detach removes the category from its products and moves sub-categories up one level.
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
-// @Success 200 {object} map[string]string
-// @Failure 400,404 {string} string "Invalid ID or Category not found"
+// @Param strategy query string false "reject, reassign or detach"
+// @Param to query int false "Target category ID for strategy=reassign"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Category not found"
+// @Failure 409 {object} map[string]interface{} "Category still used by products or sub-categories"
+// @Failure 500 {string} string "Internal server error"
 // @Router /categories/{id} [delete]
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/categories/")
@@ -166,14 +218,104 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Delete(id)
+	targetID := 0
+	if s := r.URL.Query().Get("to"); s != "" {
+		if targetID, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "Invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+
+	affected, err := h.service.Delete(id, r.URL.Query().Get("strategy"), targetID)
+	var inUse *repositories.CategoryInUseError
+	var invalid *services.ValidationError
+	switch {
+	case errors.As(err, &inUse):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":           inUse.Error(),
+			"product_count":     inUse.ProductCount,
+			"subcategory_count": inUse.SubcategoryCount,
+		})
+		return
+	case errors.Is(err, repositories.ErrCategoryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.As(err, &invalid):
+		http.Error(w, invalid.Message, http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Printf("category %d delete: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":           "Category deleted successfully",
+		"affected_products": affected,
+	})
+}
+
+// HandleCategoryProducts godoc
+// @Summary Get products of a category
+// @Description Get a page of products in a category (sorted by name), by default including its sub-categories
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Param include_subcategories query bool false "Include products of sub-categories (default true)"
+// @Success 200 {object} models.ProductPage
+// @Failure 400,404 {string} string "Invalid request or Category not found"
+// @Router /categories/{id}/produk [get]
+func (h *CategoryHandler) HandleCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/categories/"), "/produk")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	page, pageSize := 1, 20
+	if s := query.Get("page"); s != "" {
+		if page, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "Invalid page", http.StatusBadRequest)
+			return
+		}
+	}
+	if s := query.Get("page_size"); s != "" {
+		if pageSize, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "Invalid page_size", http.StatusBadRequest)
+			return
+		}
+	}
+	includeSub := true
+	if s := query.Get("include_subcategories"); s != "" {
+		if includeSub, err = strconv.ParseBool(s); err != nil {
+			http.Error(w, "Invalid include_subcategories", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := h.service.GetByID(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	result, err := h.productService.GetByCategory(id, includeSub, page, pageSize)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Category deleted successfully",
-	})
+	json.NewEncoder(w).Encode(result)
 }
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerGroupHandler struct {
+	service *services.CustomerGroupService
+}
+
+func NewCustomerGroupHandler(service *services.CustomerGroupService) *CustomerGroupHandler {
+	return &CustomerGroupHandler{service: service}
+}
+
+// HandleCustomerGroups - GET /api/customer-groups, POST /api/customer-groups
+// @Summary Get all customer groups
+// @Description Get list of all customer groups
+// @Tags customer-groups
+// @Produce json
+// @Success 200 {array} models.CustomerGroup
+// @Router /customer-groups [get]
+func (h *CustomerGroupHandler) HandleCustomerGroups(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CustomerGroupHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// @Summary Create a new customer group
+// @Description Create a new customer group
+// @Tags customer-groups
+// @Accept json
+// @Produce json
+// @Param group body models.CustomerGroup true "New customer group data"
+// @Success 201 {object} models.CustomerGroup
+// @Failure 400 {string} string "Invalid request body"
+// @Router /customer-groups [post]
+func (h *CustomerGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var group models.CustomerGroup
+	err := json.NewDecoder(r.Body).Decode(&group)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&group)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// HandleCustomerGroupByID - GET/PUT/DELETE /api/customer-groups/{id}
+func (h *CustomerGroupHandler) HandleCustomerGroupByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// @Summary Get customer group by ID
+// @Description Get details of a single customer group
+// @Tags customer-groups
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Success 200 {object} models.CustomerGroup
+// @Failure 400,404 {string} string "Invalid ID or Customer group not found"
+// @Router /customer-groups/{id} [get]
+func (h *CustomerGroupHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/customer-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid customer group ID", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// @Summary Update customer group by ID
+// @Description Update an existing customer group
+// @Tags customer-groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Param group body models.CustomerGroup true "Updated customer group data"
+// @Success 200 {object} models.CustomerGroup
+// @Failure 400,404 {string} string "Invalid request or Customer group not found"
+// @Router /customer-groups/{id} [put]
+func (h *CustomerGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/customer-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid customer group ID", http.StatusBadRequest)
+		return
+	}
+
+	var group models.CustomerGroup
+	err = json.NewDecoder(r.Body).Decode(&group)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group.ID = id
+	err = h.service.Update(&group)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// @Summary Delete customer group by ID
+// @Description Delete a customer group (its price tiers are removed)
+// @Tags customer-groups
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,404 {string} string "Invalid ID or Customer group not found"
+// @Router /customer-groups/{id} [delete]
+func (h *CustomerGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/customer-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid customer group ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Customer group deleted successfully",
+	})
+}
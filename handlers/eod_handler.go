@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"kasir-api/export"
+	"kasir-api/repositories"
+	"kasir-api/services"
+)
+
+// eodDefaultListDays adalah rentang default daftar arsip laporan tutup hari.
+const eodDefaultListDays = 30
+
+type EODHandler struct {
+	service *services.EODService
+}
+
+func NewEODHandler(service *services.EODService) *EODHandler {
+	return &EODHandler{service: service}
+}
+
+// HandleEODReports godoc
+// @Summary List archived end-of-day reports
+// @Description List archived end-of-day (Z) reports by business date, newest first, with the delivery status per channel. Without dates, returns the last 30 days.
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Start business date (YYYY-MM-DD)"
+// @Param end_date query string false "End business date (YYYY-MM-DD)"
+// @Success 200 {array} models.EODReportSummary
+// @Failure 400 {string} string "Invalid date format"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/eod [get]
+func (h *EODHandler) HandleEODReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	endDate := h.service.Today()
+	startDate := endDate.AddDate(0, 0, -(eodDefaultListDays - 1))
+	if s := r.URL.Query().Get("start_date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		startDate = d
+	}
+	if s := r.URL.Query().Get("end_date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid end_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		endDate = d
+	}
+
+	reports, err := h.service.GetReports(startDate, endDate)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// HandleEODReportByDate godoc
+// @Summary Get an archived end-of-day report
+// @Description Get the archived end-of-day (Z) report of a business date: sales totals, sales per category, top products and stock alerts at closing time, plus the delivery status per channel. The report never changes after it is generated.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param date path string true "Business date (YYYY-MM-DD)"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.EODReport
+// @Failure 400 {string} string "Invalid date format"
+// @Failure 404 {string} string "Report not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/eod/{date} [get]
+func (h *EODHandler) HandleEODReportByDate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", strings.TrimPrefix(r.URL.Path, "/api/report/eod/"))
+	if err != nil {
+		http.Error(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetReport(date)
+	if errors.Is(err, repositories.ErrEODReportNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" || format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+	if !export.Supported(format) {
+		http.Error(w, "Invalid format. Use json, csv, xlsx or pdf", http.StatusBadRequest)
+		return
+	}
+	writeExportFile(w, format, "laporan-tutup-hari-"+report.BusinessDate, services.EODExportReport(report))
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/services"
+)
+
+type PriceHandler struct {
+	service *services.PriceService
+}
+
+func NewPriceHandler(service *services.PriceService) *PriceHandler {
+	return &PriceHandler{service: service}
+}
+
+// HandlePriceHistory godoc
+// @Summary Get price history of a product
+// @Description Get every change to the product's price (old, new, who, when, manual or scheduled), newest first
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.PriceHistory
+// @Failure 400,404 {string} string "Invalid ID or Product not found"
+// @Router /produk/{id}/price-history [get]
+func (h *PriceHandler) HandlePriceHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/price-history")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetHistory(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// HandleProductPriceSchedules - GET/POST /api/produk/{id}/price-schedules, DELETE /api/produk/{id}/price-schedules/{scheduleId}
+func (h *PriceHandler) HandleProductPriceSchedules(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/produk/")
+	idStr, rest, _ := strings.Cut(rest, "/price-schedules")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetProductSchedules(w, r, id)
+		case http.MethodPost:
+			h.Schedule(w, r, id)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	scheduleID, err := strconv.Atoi(rest)
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.CancelSchedule(w, r, id, scheduleID)
+}
+
+// @Summary Get price schedules of a product
+// @Description Get scheduled price changes of a product, earliest first
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status query string false "Filter by status (pending, applied, cancelled)"
+// @Success 200 {array} models.PriceSchedule
+// @Failure 400,404 {string} string "Invalid request or Product not found"
+// @Router /produk/{id}/price-schedules [get]
+func (h *PriceHandler) GetProductSchedules(w http.ResponseWriter, r *http.Request, id int) {
+	schedules, err := h.service.GetSchedules(id, r.URL.Query().Get("status"))
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// @Summary Schedule a price change
+// @Description Schedule a future price change. The in-process scheduler applies it once effective_at (RFC 3339, e.g. 2026-11-01T00:00:00+07:00) has passed and records it in the price history.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body models.PriceScheduleRequest true "New price and effective time"
+// @Param X-User header string false "User scheduling the change, recorded in price history"
+// @Success 201 {object} models.PriceSchedule
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Product not found"
+// @Router /produk/{id}/price-schedules [post]
+func (h *PriceHandler) Schedule(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.PriceScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.service.Schedule(id, req, requestUser(r))
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// @Summary Cancel a price schedule
+// @Description Cancel a pending scheduled price change
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string "Schedule is not pending"
+// @Failure 404 {string} string "Schedule not found"
+// @Router /produk/{id}/price-schedules/{scheduleId} [delete]
+func (h *PriceHandler) CancelSchedule(w http.ResponseWriter, r *http.Request, id, scheduleID int) {
+	err := h.service.CancelSchedule(id, scheduleID)
+	if errors.Is(err, repositories.ErrPriceScheduleNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Price schedule cancelled successfully",
+	})
+}
+
+// HandlePriceSchedules godoc
+// @Summary Get all price schedules
+// @Description Get scheduled price changes across all products, earliest first
+// @Tags products
+// @Produce json
+// @Param status query string false "Filter by status (pending, applied, cancelled)"
+// @Success 200 {array} models.PriceSchedule
+// @Failure 400 {string} string "Invalid status"
+// @Router /price-schedules [get]
+func (h *PriceHandler) HandlePriceSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	schedules, err := h.service.GetSchedules(0, r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// requestUser mengembalikan nama user dari header X-User. API belum punya
+// autentikasi, jadi header ini diisi oleh aplikasi POS.
+func requestUser(r *http.Request) string {
+	return r.Header.Get("X-User")
+}
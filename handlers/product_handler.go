@@ -10,11 +10,15 @@ import (
 )
 
 type ProductHandler struct {
-	service *services.ProductService
+	service       *services.ProductService
+	imageService  *services.ProductImageService
+	lotHandler    *StockLotHandler
+	priceHandler  *PriceHandler
+	basketHandler *BasketHandler
 }
 
-func NewProductHandler(service *services.ProductService) *ProductHandler {
-	return &ProductHandler{service: service}
+func NewProductHandler(service *services.ProductService, imageService *services.ProductImageService, lotHandler *StockLotHandler, priceHandler *PriceHandler, basketHandler *BasketHandler) *ProductHandler {
+	return &ProductHandler{service: service, imageService: imageService, lotHandler: lotHandler, priceHandler: priceHandler, basketHandler: basketHandler}
 }
 
 // HandleProducts - GET /api/produk, POST /api/produk
@@ -23,6 +27,7 @@ func NewProductHandler(service *services.ProductService) *ProductHandler {
 // @Tags products
 // @Produce json
 // @Param name query string false "Filter products by name (case-insensitive)"
+// @Param category_id query int false "Filter by category, including all its sub-categories"
 // @Success 200 {array} models.Product
 // @Router /produk [get]
 func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +44,13 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 
-	products, err := h.service.GetAll(name)
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.service.GetAll(name, categoryID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -49,12 +60,49 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(products)
 }
 
+// HandleSearch godoc
+// @Summary Search products
+// @Description Typo-tolerant search across product name, SKU, barcode and category name, ranked by relevance. Supports prefix matching for as-you-type search.
+// @Tags products
+// @Produce json
+// @Param q query string true "Search term"
+// @Param limit query int false "Maximum results (default 20, max 100)"
+// @Success 200 {array} models.ProductSearchResult
+// @Failure 400 {string} string "Missing search term"
+// @Router /produk/search [get]
+func (h *ProductHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	results, err := h.service.Search(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
 // @Summary Create a new product
 // @Description Create a new product
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param product body models.Product true "New product data"
+// @Param X-User header string false "User making the change, recorded in price history"
 // @Success 201 {object} models.Product
 // @Failure 400 {string} string "Invalid request body"
 // @Router /produk [post]
@@ -66,7 +114,7 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Create(&product)
+	err = h.service.Create(&product, requestUser(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -79,6 +127,31 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // HandleProductByID - GET/PUT/DELETE /api/produk/{id}
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/stock-movements") {
+		h.HandleStockMovements(w, r)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/images") {
+		h.HandleProductImages(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/lots") {
+		h.lotHandler.HandleProductLots(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/price-history") {
+		h.priceHandler.HandlePriceHistory(w, r)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/price-schedules") {
+		h.priceHandler.HandleProductPriceSchedules(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/frequently-bought-with") {
+		h.basketHandler.HandleFrequentlyBoughtWith(w, r)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.GetByID(w, r)
@@ -124,6 +197,7 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Product ID"
 // @Param product body models.Product true "Updated product data"
+// @Param X-User header string false "User making the change, recorded in price history"
 // @Success 200 {object} models.Product
 // @Failure 400,404 {string} string "Invalid request or Product not found"
 // @Router /produk/{id} [put]
@@ -143,7 +217,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	product.ID = id
-	err = h.service.Update(&product)
+	err = h.service.Update(&product, requestUser(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -180,3 +254,35 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		"message": "Product deleted successfully",
 	})
 }
+
+// HandleStockMovements godoc
+// @Summary Get stock movements of a product
+// @Description Get the stock ledger (purchases, sales and adjustments) of a product, newest first
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.StockMovement
+// @Failure 400,404 {string} string "Invalid ID or Product not found"
+// @Router /produk/{id}/stock-movements [get]
+func (h *ProductHandler) HandleStockMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/stock-movements")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	movements, err := h.service.GetStockMovements(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
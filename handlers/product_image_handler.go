@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"kasir-api/storage"
+)
+
+// HandleProductImages - GET/POST /api/produk/{id}/images, DELETE /api/produk/{id}/images/{imageId}
+func (h *ProductHandler) HandleProductImages(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/produk/")
+	idStr, rest, _ := strings.Cut(rest, "/images")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetImages(w, r, id)
+		case http.MethodPost:
+			h.UploadImage(w, r, id)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	imageID, err := strconv.Atoi(rest)
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.DeleteImage(w, r, id, imageID)
+}
+
+// @Summary Get product images
+// @Description Get all images of a product with original and thumbnail URLs
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductImage
+// @Failure 400,404 {string} string "Invalid ID or Product not found"
+// @Router /produk/{id}/images [get]
+func (h *ProductHandler) GetImages(w http.ResponseWriter, r *http.Request, id int) {
+	images, err := h.imageService.GetByProductID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(images)
+}
+
+// @Summary Upload product image
+// @Description Upload a JPEG, PNG or GIF image (multipart field "image"). The file type is detected from its content and a thumbnail is generated.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param image formData file true "Image file"
+// @Success 201 {object} models.ProductImage
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Product not found"
+// @Failure 413 {string} string "Image too large"
+// @Failure 415 {string} string "Unsupported image type"
+// @Router /produk/{id}/images [post]
+func (h *ProductHandler) UploadImage(w http.ResponseWriter, r *http.Request, id int) {
+	maxSize := h.imageService.MaxSize
+	// Sisakan ruang untuk boundary dan header multipart
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, services.ErrImageTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Field image wajib diisi (multipart/form-data)", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.imageService.Upload(r.Context(), id, data)
+	switch {
+	case errors.Is(err, services.ErrImageTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, services.ErrUnsupportedImageType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	case errors.Is(err, repositories.ErrProductNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(image)
+}
+
+// @Summary Delete product image
+// @Description Delete an image and its thumbnail
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,404 {string} string "Invalid ID or Image not found"
+// @Router /produk/{id}/images/{imageId} [delete]
+func (h *ProductHandler) DeleteImage(w http.ResponseWriter, r *http.Request, id, imageID int) {
+	err := h.imageService.Delete(r.Context(), id, imageID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Image deleted successfully",
+	})
+}
+
+// ImageHandler menyajikan file gambar dari storage di /images/{key}.
+type ImageHandler struct {
+	service *services.ProductImageService
+}
+
+func NewImageHandler(service *services.ProductImageService) *ImageHandler {
+	return &ImageHandler{service: service}
+}
+
+// ServeImage - GET /images/{key}
+// Key gambar selalu unik per upload sehingga file aman di-cache selamanya.
+func (h *ImageHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/images/")
+	f, err := h.service.Open(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("image %s: %v", key, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	// ETag baru dicek setelah object dipastikan masih ada, supaya gambar
+	// yang sudah dihapus tidak terus dianggap segar oleh cache
+	etag := `"` + key + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	contentType := "application/octet-stream"
+	switch path.Ext(key) {
+	case ".jpg":
+		contentType = "image/jpeg"
+	case ".png":
+		contentType = "image/png"
+	case ".gif":
+		contentType = "image/gif"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, f)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kasir-api/services"
+	"kasir-api/storage"
+)
+
+// brokenStorage mensimulasikan storage yang gagal dengan error internal.
+type brokenStorage struct{}
+
+func (brokenStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return errors.New("dial tcp 10.0.0.5:9000: connection refused")
+}
+
+func (brokenStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, errors.New("dial tcp 10.0.0.5:9000: connection refused")
+}
+
+func (brokenStorage) Delete(ctx context.Context, key string) error {
+	return errors.New("dial tcp 10.0.0.5:9000: connection refused")
+}
+
+func TestServeImage(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const key = "products/1/abc.jpg"
+	if err := store.Put(context.Background(), key, strings.NewReader("jpeg bytes"), 10, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	h := NewImageHandler(services.NewProductImageService(nil, nil, store, "/images", 1<<20))
+
+	serve := func(key, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/images/"+key, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeImage(w, r)
+		return w
+	}
+
+	w := serve(key, "")
+	if w.Code != http.StatusOK || w.Body.String() != "jpeg bytes" || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Fatalf("GET = %d %q %q, want 200 with image", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+
+	if w := serve(key, etag); w.Code != http.StatusNotModified {
+		t.Errorf("GET with matching ETag = %d, want 304", w.Code)
+	}
+
+	// Setelah dihapus, ETag lama tidak boleh lagi menghasilkan 304
+	if err := store.Delete(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(key, etag); w.Code != http.StatusNotFound {
+		t.Errorf("GET deleted image with old ETag = %d, want 404", w.Code)
+	}
+}
+
+func TestServeImageStorageError(t *testing.T) {
+	h := NewImageHandler(services.NewProductImageService(nil, nil, brokenStorage{}, "/images", 1<<20))
+	r := httptest.NewRequest(http.MethodGet, "/images/products/1/abc.jpg", nil)
+	w := httptest.NewRecorder()
+	h.ServeImage(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "Internal server error" {
+		t.Errorf("body = %q, want generic message", body)
+	}
+}
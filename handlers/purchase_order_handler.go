@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PurchaseOrderHandler struct {
+	service *services.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service *services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders - GET /api/purchase-orders, POST /api/purchase-orders
+// @Summary Get all purchase orders
+// @Description Get list of purchase orders. Can filter by status and supplier.
+// @Tags purchase-orders
+// @Produce json
+// @Param status query string false "Filter by status (draft, sent, partially_received, received, cancelled)"
+// @Param supplier_id query int false "Filter by supplier ID"
+// @Success 200 {array} models.PurchaseOrder
+// @Failure 400 {string} string "Invalid supplier_id"
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	supplierID := 0
+	if s := r.URL.Query().Get("supplier_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid supplier_id", http.StatusBadRequest)
+			return
+		}
+		supplierID = id
+	}
+
+	orders, err := h.service.GetAll(status, supplierID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// @Summary Create a new purchase order
+// @Description Create a draft purchase order with line items
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param order body models.PurchaseOrder true "New purchase order data"
+// @Success 201 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Invalid request body"
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var po models.PurchaseOrder
+	err := json.NewDecoder(r.Body).Decode(&po)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&po)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(po)
+}
+
+// HandlePurchaseOrderByID - GET/PUT /api/purchase-orders/{id},
+// POST /api/purchase-orders/{id}/send, /receive, /cancel
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/purchase-orders/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r, id)
+	case action == "send" && r.Method == http.MethodPost:
+		h.Send(w, r, id)
+	case action == "receive" && r.Method == http.MethodPost:
+		h.Receive(w, r, id)
+	case action == "cancel" && r.Method == http.MethodPost:
+		h.Cancel(w, r, id)
+	case action == "" || action == "send" || action == "receive" || action == "cancel":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// @Summary Get purchase order by ID
+// @Description Get a purchase order with its line items
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400,404 {string} string "Invalid ID or Purchase order not found"
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request, id int) {
+	po, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// @Summary Update purchase order by ID
+// @Description Replace supplier, notes and line items of a draft purchase order
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param order body models.PurchaseOrder true "Updated purchase order data"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Invalid request or purchase order is not a draft"
+// @Router /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request, id int) {
+	var po models.PurchaseOrder
+	err := json.NewDecoder(r.Body).Decode(&po)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	po.ID = id
+	err = h.service.Update(&po)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// @Summary Send purchase order
+// @Description Mark a draft purchase order as sent to the supplier
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Purchase order cannot be sent"
+// @Router /purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) Send(w http.ResponseWriter, r *http.Request, id int) {
+	po, err := h.service.Send(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// @Summary Receive goods for a purchase order
+// @Description Receive some or all ordered items. Increments product stock through the stock ledger and updates cost price (weighted average).
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param request body models.ReceiveRequest true "Received items"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Invalid request or purchase order cannot be received"
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.ReceiveRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	po, err := h.service.Receive(id, req.Items)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// @Summary Cancel purchase order
+// @Description Cancel a draft or sent purchase order
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Purchase order cannot be cancelled"
+// @Router /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, r *http.Request, id int) {
+	po, err := h.service.Cancel(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kasir-api/export"
+	"kasir-api/models"
+)
+
+// reportExport adalah metadata file ekspor sebuah laporan.
+type reportExport struct {
+	name       string // bagian nama file, misalnya "penjualan"
+	title      string
+	startDate  time.Time
+	endDate    time.Time
+	categoryID int
+	build      func() ([]string, [][]export.Cell)
+}
+
+// writeReport menulis data sebagai JSON, atau sebagai file jika query format
+// berisi csv, xlsx atau pdf.
+func (h *ReportHandler) writeReport(w http.ResponseWriter, r *http.Request, data any, e reportExport) {
+	format := r.URL.Query().Get("format")
+	if format == "" || format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(data)
+		return
+	}
+	if !export.Supported(format) {
+		http.Error(w, "Invalid format. Use json, csv, xlsx or pdf", http.StatusBadRequest)
+		return
+	}
+
+	location := h.reportService.Location()
+	period := e.startDate.Format("02-01-2006")
+	if !e.endDate.Equal(e.startDate) {
+		period += " s/d " + e.endDate.Format("02-01-2006")
+	}
+	report := &export.Report{
+		Title: e.title,
+		Fields: []export.Field{
+			{Label: "Toko", Value: h.storeName},
+			{Label: "Periode", Value: period},
+			{Label: "Zona waktu", Value: location.String()},
+		},
+	}
+	if e.categoryID != 0 {
+		report.Fields = append(report.Fields, export.Field{
+			Label: "Kategori",
+			Value: fmt.Sprintf("ID %d (termasuk sub-kategori)", e.categoryID),
+		})
+	}
+	report.Fields = append(report.Fields, export.Field{
+		Label: "Dibuat",
+		Value: time.Now().In(location).Format("02-01-2006 15:04 MST"),
+	})
+	report.Columns, report.Rows = e.build()
+
+	filename := fmt.Sprintf("laporan-%s-%s", e.name, e.startDate.Format("2006-01-02"))
+	if !e.endDate.Equal(e.startDate) {
+		filename += "_" + e.endDate.Format("2006-01-02")
+	}
+	writeExportFile(w, format, filename, report)
+}
+
+// writeExportFile menulis report sebagai file unduhan filename.format.
+func writeExportFile(w http.ResponseWriter, format, filename string, report *export.Report) {
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, report); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Write(buf.Bytes())
+}
+
+func quantityCell(q models.Quantity) export.Cell {
+	return export.Number(q.Float64(), q.String())
+}
+
+// totalShare adalah share baris total: 100% kecuali periode tanpa penjualan.
+func totalShare(total int) export.Cell {
+	if total == 0 {
+		return export.Percent(0)
+	}
+	return export.Percent(100)
+}
+
+func summaryExportRows(report *models.SalesReport) ([]string, [][]export.Cell) {
+	rows := [][]export.Cell{
+		{export.Text("Total Revenue"), export.Money(report.TotalRevenue)},
+		{export.Text("Total Transaksi"), export.Int(report.TotalTransaksi)},
+	}
+	if report.ProdukTerlaris != nil {
+		rows = append(rows,
+			[]export.Cell{export.Text("Produk Terlaris"), export.Text(report.ProdukTerlaris.Nama)},
+			[]export.Cell{export.Text("Qty Terjual"), quantityCell(report.ProdukTerlaris.QtyTerjual)},
+		)
+	}
+	if report.Comparison == nil {
+		return []string{"Keterangan", "Nilai"}, rows
+	}
+
+	// Dengan perbandingan, setiap metrik menampilkan nilai pembanding dan
+	// perubahannya
+	c := report.Comparison
+	metricRow := func(label string, m models.MetricChange, cell func(int) export.Cell) []export.Cell {
+		return []export.Cell{export.Text(label), cell(m.Current), cell(m.Previous), cell(m.Change), changeCell(m.ChangePercent)}
+	}
+	rows = [][]export.Cell{
+		metricRow("Total Revenue", c.Revenue, export.Money),
+		metricRow("Total Transaksi", c.Transactions, export.Int),
+		metricRow("Rata-rata Keranjang", c.AverageBasket, export.Money),
+	}
+	for _, p := range c.Products {
+		rows = append(rows, []export.Cell{
+			export.Text("Qty " + p.ProductName),
+			quantityCell(p.CurrentQuantity),
+			quantityCell(p.PreviousQuantity),
+			quantityCell(p.Change),
+			changeCell(p.ChangePercent),
+		})
+	}
+	previous := fmt.Sprintf("%s s/d %s", c.PreviousStartDate, c.PreviousEndDate)
+	return []string{"Keterangan", "Periode Ini", "Pembanding (" + previous + ")", "Selisih", "Perubahan"}, rows
+}
+
+// changeCell menampilkan persentase perubahan, atau "-" jika tidak bisa
+// dihitung karena nilai pembanding nol.
+func changeCell(p *float64) export.Cell {
+	if p == nil {
+		return export.Text("-")
+	}
+	return export.Percent(*p)
+}
+
+func timeSeriesExportRows(series *models.SalesTimeSeries) ([]string, [][]export.Cell) {
+	layout := "02-01-2006"
+	switch series.Granularity {
+	case models.GranularityHour:
+		layout = "02-01-2006 15:04"
+	case models.GranularityMonth:
+		layout = "01-2006"
+	}
+
+	rows := make([][]export.Cell, len(series.Buckets))
+	for i, b := range series.Buckets {
+		label := b.Start.Format(layout)
+		if series.Granularity == models.GranularityWeek {
+			label = "Minggu " + label
+		}
+		rows[i] = []export.Cell{
+			export.Text(label),
+			export.Money(b.Revenue),
+			export.Int(b.Transactions),
+			quantityCell(b.ItemsSold),
+			export.Money(b.AverageBasket),
+		}
+	}
+	return []string{"Periode", "Revenue", "Transaksi", "Item Terjual", "Rata-rata Keranjang"}, rows
+}
+
+func categorySalesExportRows(report *models.CategorySalesReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items)+1)
+	for _, item := range report.Items {
+		rows = append(rows, []export.Cell{
+			export.Text(item.CategoryName),
+			export.Money(item.Revenue),
+			export.Percent(item.RevenueShare),
+			quantityCell(item.Quantity),
+			export.Percent(item.QuantityShare),
+			export.Int(item.Transactions),
+		})
+	}
+	rows = append(rows, []export.Cell{
+		export.Text("Total"),
+		export.Money(report.Totals.Revenue),
+		totalShare(report.Totals.Revenue),
+		quantityCell(report.Totals.Quantity),
+		totalShare(int(report.Totals.Quantity)),
+		export.Int(report.Totals.Transactions),
+	})
+	return []string{"Kategori", "Revenue", "Share Revenue", "Qty", "Share Qty", "Transaksi"}, rows
+}
+
+func productSalesExportRows(report *models.ProductSalesReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items)+1)
+	for _, item := range report.Items {
+		rows = append(rows, []export.Cell{
+			export.Text(item.ProductName),
+			export.Text(item.CategoryName),
+			export.Money(item.Revenue),
+			export.Percent(item.RevenueShare),
+			quantityCell(item.Quantity),
+			export.Percent(item.QuantityShare),
+			export.Int(item.Transactions),
+		})
+	}
+	rows = append(rows, []export.Cell{
+		export.Text("Total"),
+		export.Text(""),
+		export.Money(report.Totals.Revenue),
+		totalShare(report.Totals.Revenue),
+		quantityCell(report.Totals.Quantity),
+		totalShare(int(report.Totals.Quantity)),
+		export.Int(report.Totals.Transactions),
+	})
+	return []string{"Produk", "Kategori", "Revenue", "Share Revenue", "Qty", "Share Qty", "Transaksi"}, rows
+}
+
+func productRankingExportRows(report *models.ProductRankingReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, len(report.Items))
+	for i, item := range report.Items {
+		category := models.UncategorizedName
+		if item.CategoryName != nil {
+			category = *item.CategoryName
+		}
+		rows[i] = []export.Cell{
+			export.Int(item.Rank),
+			export.Text(item.ProductName),
+			export.Text(category),
+			quantityCell(item.Quantity),
+			export.Money(item.Revenue),
+			export.Money(item.Cost),
+			export.Money(item.Profit),
+			export.Int(item.Transactions),
+		}
+	}
+	return []string{"Rank", "Produk", "Kategori", "Qty", "Revenue", "HPP", "Laba", "Transaksi"}, rows
+}
+
+func inventoryExportRows(valuation *models.InventoryValuation) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(valuation.Products)+len(valuation.Categories)+1)
+	for _, p := range valuation.Products {
+		rows = append(rows, []export.Cell{
+			export.Text(p.ProductName),
+			export.Text(p.CategoryName),
+			quantityCell(p.Stock),
+			export.Text(p.Unit),
+			export.Money(p.Price),
+			export.Money(p.CostPrice),
+			export.Money(p.RetailValue),
+			export.Money(p.CostValue),
+		})
+	}
+	for _, c := range valuation.Categories {
+		rows = append(rows, []export.Cell{
+			export.Text("Subtotal " + c.CategoryName),
+			export.Text(""),
+			quantityCell(c.Stock),
+			export.Text(""),
+			export.Text(""),
+			export.Text(""),
+			export.Money(c.RetailValue),
+			export.Money(c.CostValue),
+		})
+	}
+	rows = append(rows, []export.Cell{
+		export.Text("Total"),
+		export.Text(""),
+		quantityCell(valuation.Totals.Stock),
+		export.Text(""),
+		export.Text(""),
+		export.Text(""),
+		export.Money(valuation.Totals.RetailValue),
+		export.Money(valuation.Totals.CostValue),
+	})
+	return []string{"Produk", "Kategori", "Stok", "Unit", "Harga Jual", "HPP", "Nilai Jual", "Nilai HPP"}, rows
+}
+
+func deadStockExportRows(report *models.DeadStockReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items)+1)
+	for _, item := range report.Items {
+		lastSale, daysSince := export.Text("Belum pernah"), export.Text("-")
+		if item.LastSaleAt != nil {
+			lastSale = export.Text(item.LastSaleAt.Format("02-01-2006"))
+			daysSince = export.Int(*item.DaysSinceLastSale)
+		}
+		status := "Tidak terjual"
+		if item.Status == models.StockStatusSlow {
+			status = "Lambat"
+		}
+		rows = append(rows, []export.Cell{
+			export.Text(item.ProductName),
+			export.Text(item.CategoryName),
+			export.Text(status),
+			quantityCell(item.Stock),
+			export.Text(item.Unit),
+			quantityCell(item.SoldQuantity),
+			export.Percent(item.SellThrough),
+			lastSale,
+			daysSince,
+			export.Money(item.CostValue),
+			export.Money(item.RetailValue),
+		})
+	}
+	empty := export.Text("")
+	rows = append(rows, []export.Cell{
+		export.Text("Total"), empty, empty, empty, empty, empty, empty, empty, empty,
+		export.Money(report.Totals.CostValue),
+		export.Money(report.Totals.RetailValue),
+	})
+	return []string{"Produk", "Kategori", "Status", "Stok", "Unit", "Terjual", "Sell-through", "Terakhir Terjual", "Hari Sejak Terjual", "Nilai HPP", "Nilai Jual"}, rows
+}
+
+func abcExportRows(report *models.ABCReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items)+len(report.Classes))
+	for _, item := range report.Items {
+		category := models.UncategorizedName
+		if item.CategoryName != nil {
+			category = *item.CategoryName
+		}
+		rows = append(rows, []export.Cell{
+			export.Int(item.Rank),
+			export.Text(item.ProductName),
+			export.Text(category),
+			export.Text(item.Class),
+			quantityCell(item.Quantity),
+			export.Money(item.Revenue),
+			export.Money(item.Profit),
+			export.Percent(item.Share),
+			export.Percent(item.CumulativeShare),
+		})
+	}
+	empty := export.Text("")
+	for _, c := range report.Classes {
+		rows = append(rows, []export.Cell{
+			empty,
+			export.Text(fmt.Sprintf("Kelas %s (%d produk, %.2f%%)", c.Class, c.ProductCount, c.ProductShare)),
+			empty,
+			export.Text(c.Class),
+			empty,
+			empty,
+			empty,
+			export.Percent(c.ValueShare),
+			empty,
+		})
+	}
+	return []string{"Rank", "Produk", "Kategori", "Kelas", "Qty", "Revenue", "Laba", "Share", "Share Kumulatif"}, rows
+}
+
+func reorderExportRows(report *models.ReorderReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items)+1)
+	for _, item := range report.Items {
+		supplier, daysLeft, stockoutDate := export.Text("-"), export.Text("> 365"), export.Text("-")
+		if item.SupplierName != nil {
+			supplier = export.Text(*item.SupplierName)
+		}
+		if item.DaysUntilStockout != nil {
+			daysLeft = export.Int(*item.DaysUntilStockout)
+			date, _ := time.Parse("2006-01-02", *item.StockoutDate)
+			stockoutDate = export.Text(date.Format("02-01-2006"))
+		}
+		rows = append(rows, []export.Cell{
+			export.Text(item.ProductName),
+			export.Text(item.CategoryName),
+			supplier,
+			export.Int(item.LeadTimeDays),
+			quantityCell(item.Stock),
+			quantityCell(item.OnOrder),
+			export.Text(item.Unit),
+			quantityCell(item.AverageDailyDemand),
+			daysLeft,
+			stockoutDate,
+			quantityCell(item.ReorderPoint),
+			quantityCell(item.SuggestedQuantity),
+			export.Money(item.UnitCost),
+			export.Money(item.EstimatedCost),
+		})
+	}
+	empty := export.Text("")
+	rows = append(rows, []export.Cell{
+		export.Text("Total"), empty, empty, empty, empty, empty, empty, empty, empty, empty, empty, empty, empty,
+		export.Money(report.Totals.EstimatedCost),
+	})
+	return []string{"Produk", "Kategori", "Supplier", "Lead Time (hari)", "Stok", "Dipesan", "Unit", "Permintaan/Hari", "Hari Sampai Habis", "Tanggal Habis", "Reorder Point", "Saran Pesan", "HPP/Unit", "Estimasi Biaya"}, rows
+}
+
+func productPairsExportRows(report *models.ProductPairsReport) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Items))
+	for _, p := range report.Items {
+		rows = append(rows, []export.Cell{
+			export.Int(p.Rank),
+			export.Text(p.ProductAName),
+			export.Text(p.ProductBName),
+			export.Int(p.Transactions),
+			export.Percent(p.Support),
+			export.Percent(p.ConfidenceAToB),
+			export.Percent(p.ConfidenceBToA),
+			export.Number(p.Lift, strconv.FormatFloat(p.Lift, 'f', 2, 64)),
+		})
+	}
+	return []string{"No", "Produk A", "Produk B", "Transaksi Bersama", "Support", "Confidence A→B", "Confidence B→A", "Lift"}, rows
+}
+
+func rfmExportRows(report *models.RFMReport, location *time.Location) ([]string, [][]export.Cell) {
+	rows := make([][]export.Cell, 0, len(report.Customers)+len(report.Segments))
+	for _, c := range report.Customers {
+		phone, group := export.Text("-"), export.Text("-")
+		if c.Phone != "" {
+			phone = export.Text(c.Phone)
+		}
+		if c.CustomerGroupName != nil {
+			group = export.Text(*c.CustomerGroupName)
+		}
+		rows = append(rows, []export.Cell{
+			export.Text(c.CustomerName),
+			phone,
+			group,
+			export.Text(c.LastPurchaseAt.In(location).Format("02-01-2006")),
+			export.Int(c.RecencyDays),
+			export.Int(c.Frequency),
+			export.Money(c.Monetary),
+			export.Int(c.RecencyScore),
+			export.Int(c.FrequencyScore),
+			export.Int(c.MonetaryScore),
+			export.Text(c.Score),
+			export.Text(c.Segment),
+		})
+	}
+	empty := export.Text("")
+	for _, s := range report.Segments {
+		rows = append(rows, []export.Cell{
+			export.Text(fmt.Sprintf("Segmen %s (%d pelanggan, %.2f%%)", s.Segment, s.Customers, s.CustomerShare)),
+			empty, empty, empty, empty, empty,
+			export.Money(s.Revenue),
+			empty, empty, empty, empty,
+			export.Text(s.Segment),
+		})
+	}
+	return []string{"Pelanggan", "Telepon", "Kelompok", "Terakhir Belanja", "Hari Sejak Belanja", "Frekuensi", "Total Belanja", "R", "F", "M", "Skor", "Segmen"}, rows
+}
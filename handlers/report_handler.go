@@ -2,25 +2,40 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"kasir-api/export"
+	"kasir-api/models"
 	"kasir-api/services"
 )
 
 type ReportHandler struct {
-	service *services.TransactionService
+	service              *services.TransactionService
+	reportService        *services.ReportService
+	purchaseOrderService *services.PurchaseOrderService
+	storeName            string
 }
 
-func NewReportHandler(service *services.TransactionService) *ReportHandler {
-	return &ReportHandler{service: service}
+// NewReportHandler membuat handler laporan. storeName ditampilkan di header
+// file ekspor; purchaseOrderService dipakai untuk membuat draft PO dari saran
+// pemesanan ulang.
+func NewReportHandler(service *services.TransactionService, reportService *services.ReportService,
+	purchaseOrderService *services.PurchaseOrderService, storeName string) *ReportHandler {
+	return &ReportHandler{service: service, reportService: reportService, purchaseOrderService: purchaseOrderService, storeName: storeName}
 }
 
 // HandleTodayReport godoc
 // @Summary Get today's sales report
-// @Description Get sales summary for today including total revenue, total transactions, and best selling product
+// @Description Get sales summary for today including total revenue, total transactions, and best selling product. Today follows the store timezone (STORE_TIMEZONE).
 // @Tags reports
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param category_id query int false "Only count items of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
 // @Success 200 {object} models.SalesReport
 // @Failure 500 {string} string "Internal server error"
 // @Router /report/hari-ini [get]
@@ -30,23 +45,35 @@ func (h *ReportHandler) HandleTodayReport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	report, err := h.service.GetTodaySummary()
+	categoryID, err := parseCategoryID(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
+	report, err := h.service.GetTodaySummary(categoryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	today := h.reportService.Today()
+	h.writeReport(w, r, report, reportExport{
+		name: "penjualan", title: "Laporan Penjualan", startDate: today, endDate: today, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return summaryExportRows(report) },
+	})
 }
 
 // HandleReport godoc
 // @Summary Get sales report by date range
-// @Description Get sales summary for a specific date range. If no dates provided, returns today's report.
+// @Description Get sales summary for a specific date range. Days are counted in the store timezone (STORE_TIMEZONE). If no dates provided, returns today's report. With compare, the response includes a comparison with the previous period of the same length or the same dates last year: absolute and percentage change of revenue, transaction count, average basket and quantity sold per product.
 // @Tags reports
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param category_id query int false "Only count items of this category and its sub-categories"
+// @Param compare query string false "previous or last_year"
+// @Param format query string false "json (default), csv, xlsx or pdf"
 // @Success 200 {object} models.SalesReport
 // @Failure 400 {string} string "Invalid date format"
 // @Failure 500 {string} string "Internal server error"
@@ -57,40 +84,654 @@ func (h *ReportHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	startDateStr := r.URL.Query().Get("start_date")
-	endDateStr := r.URL.Query().Get("end_date")
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// If no date params, redirect to today's report
-	if startDateStr == "" || endDateStr == "" {
-		report, err := h.service.GetTodaySummary()
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetSummaryByDateRange(startDate, endDate, categoryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if compare := r.URL.Query().Get("compare"); compare != "" {
+		report.Comparison, err = h.reportService.GetComparison(startDate, endDate, categoryID, compare)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, err)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(report)
+	}
+
+	h.writeReport(w, r, report, reportExport{
+		name: "penjualan", title: "Laporan Penjualan", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return summaryExportRows(report) },
+	})
+}
+
+// HandleBundleComponentReport godoc
+// @Summary Get bundle revenue attributed to components
+// @Description Get quantity and revenue of bundle sales attributed to each component product. Revenue is allocated proportionally to the components' regular prices. If no dates provided, returns today's data.
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} models.BundleComponentSales
+// @Failure 400 {string} string "Invalid date format"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/bundle-components [get]
+func (h *ReportHandler) HandleBundleComponentReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Parse dates
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, endDate, err := h.parseDateRange(r)
 	if err != nil {
-		http.Error(w, "Invalid start_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	result, err := h.service.GetBundleComponentSales(startDate, endDate)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleTimeSeries godoc
+// @Summary Get sales time-series
+// @Description Get revenue, transaction count, items sold and average basket per hour, day, week (starting Monday) or month. Buckets follow the store timezone (STORE_TIMEZONE) and empty buckets are returned with zeros. If no dates provided, returns today's data.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param granularity query string false "hour, day (default), week or month"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.SalesTimeSeries
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/timeseries [get]
+func (h *ReportHandler) HandleTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := h.reportService.GetTimeSeries(startDate, endDate, r.URL.Query().Get("granularity"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, series, reportExport{
+		name: "penjualan-per-" + series.Granularity, title: "Laporan Penjualan per Periode", startDate: startDate, endDate: endDate,
+		build: func() ([]string, [][]export.Cell) { return timeSeriesExportRows(series) },
+	})
+}
+
+// HandleTopProducts godoc
+// @Summary Get top or bottom selling products
+// @Description Rank products by quantity sold (base unit), revenue or profit for a date range in the store timezone. order=bottom lists slow movers, including products without any sales. Ties are ordered by product name. Profit uses the cost price recorded at checkout. If no dates provided, returns today's data.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param limit query int false "Number of products (default 20, max 500)"
+// @Param by query string false "qty (default), revenue or profit"
+// @Param order query string false "top (default) or bottom"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.ProductRankingReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/top-products [get]
+func (h *ReportHandler) HandleTopProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	report, err := h.reportService.GetProductRanking(startDate, endDate, categoryID, query.Get("by"), query.Get("order"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, report, reportExport{
+		name: "produk-" + report.Order + "-" + report.By, title: "Laporan Produk Terlaris", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return productRankingExportRows(report) },
+	})
+}
+
+// HandleCategorySales godoc
+// @Summary Get sales by category
+// @Description Group revenue, quantity sold (base unit) and transaction count by each product's own category over a date range in the store timezone, with each row's share of the period total in percent. Products without a category are grouped as Uncategorized (category_id null). If no dates provided, returns today's data.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.CategorySalesReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/sales-by-category [get]
+func (h *ReportHandler) HandleCategorySales(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportService.GetCategorySales(startDate, endDate, categoryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, report, reportExport{
+		name: "penjualan-per-kategori", title: "Laporan Penjualan per Kategori", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return categorySalesExportRows(report) },
+	})
+}
+
+// HandleProductSales godoc
+// @Summary Get sales by product
+// @Description Group revenue, quantity sold (base unit) and transaction count by product over a date range in the store timezone, with each row's share of the period total in percent. If no dates provided, returns today's data.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.ProductSalesReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/sales-by-product [get]
+func (h *ReportHandler) HandleProductSales(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportService.GetProductSales(startDate, endDate, categoryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, report, reportExport{
+		name: "penjualan-per-produk", title: "Laporan Penjualan per Produk", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return productSalesExportRows(report) },
+	})
+}
+
+// HandleInventoryValuation godoc
+// @Summary Get inventory valuation
+// @Description Stock quantity and value at selling price and at average cost per product, per category and in total. With as_of, stock is reconstructed from the stock movement ledger and valued with the selling price and cost in effect at the end of that date (store timezone). Bundles are excluded because they have no stock of their own.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param as_of query string false "Value inventory at the end of this date (YYYY-MM-DD); default is now"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.InventoryValuation
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/inventory [get]
+func (h *ReportHandler) HandleInventoryValuation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var asOf *time.Time
+	date := h.reportService.Today()
+	if s := r.URL.Query().Get("as_of"); s != "" {
+		date, err = time.Parse("2006-01-02", s)
+		if err != nil {
+			http.Error(w, "Invalid as_of format. Use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		asOf = &date
+	}
+
+	valuation, err := h.reportService.GetInventoryValuation(asOf, categoryID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, valuation, reportExport{
+		name: "persediaan", title: "Laporan Nilai Persediaan", startDate: date, endDate: date, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return inventoryExportRows(valuation) },
+	})
+}
+
+// HandleDeadStock godoc
+// @Summary Get dead stock and slow movers
+// @Description Products with stock on hand that did not sell in the last N days (dead) or sold with a sell-through below slow_threshold percent (slow), sorted by stock value at cost. Sell-through is units sold / (units sold + current stock) over the period. Sales are taken from the stock ledger, so components sold inside bundles count as sold. Days are counted in the store timezone (STORE_TIMEZONE), including today.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param days query int false "Period length in days (default 90)"
+// @Param slow_threshold query number false "Sell-through percentage below which a product counts as slow (default 10, 0 lists dead stock only)"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.DeadStockReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/dead-stock [get]
+func (h *ReportHandler) HandleDeadStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	days := 0
+	if s := r.URL.Query().Get("days"); s != "" {
+		days, err = strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid days", http.StatusBadRequest)
+			return
+		}
+	}
+	slowThreshold, err := parseOptionalFloat(r, "slow_threshold")
 	if err != nil {
-		http.Error(w, "Invalid end_date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	report, err := h.service.GetSummaryByDateRange(startDate, endDate)
+	report, err := h.reportService.GetDeadStock(days, slowThreshold, categoryID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
+		return
+	}
+
+	today := h.reportService.Today()
+	h.writeReport(w, r, report, reportExport{
+		name: "dead-stock", title: "Laporan Dead Stock dan Slow Mover", startDate: today.AddDate(0, 0, -(report.Days - 1)), endDate: today, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return deadStockExportRows(report) },
+	})
+}
+
+// HandleABC godoc
+// @Summary Get ABC (Pareto) analysis
+// @Description Classify products into A, B and C by cumulative contribution to revenue or profit over a date range (store timezone). Products are sorted by contribution; a product is A while the cumulative share of the products before it is below cutoff_a, B while below cutoff_b, and C otherwise. Products with no positive contribution, including unsold ones, are C.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param by query string false "revenue (default) or profit"
+// @Param cutoff_a query number false "Cumulative percentage for class A (default 80)"
+// @Param cutoff_b query number false "Cumulative percentage for class B (default 95)"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.ABCReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/abc [get]
+func (h *ReportHandler) HandleABC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDate, endDate, err := h.parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cutoffA, err := parseOptionalFloat(r, "cutoff_a")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cutoffB, err := parseOptionalFloat(r, "cutoff_b")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportService.GetABC(startDate, endDate, categoryID, r.URL.Query().Get("by"), cutoffA, cutoffB)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.writeReport(w, r, report, reportExport{
+		name: "abc", title: "Analisis ABC", startDate: startDate, endDate: endDate, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return abcExportRows(report) },
+	})
+}
+
+// HandleRFM godoc
+// @Summary Get RFM customer segmentation
+// @Description Score registered customers who bought in the last days days (today included, store timezone) on recency (days since last purchase), frequency (number of transactions) and monetary (total spent), each from 1 to 5 by percentile rank among the customers in the report; tied values get the same score. Customers are then grouped by R and the average of F and M (FM): champions (R>=4, FM>=4), loyal (R>=3, FM>=3), new (R>=4), at_risk (R<=2, FM>=3), lost (R=1) and need_attention (the rest). Segment totals always cover all customers; segment only filters the customer list, e.g. segment=at_risk&format=csv for a re-engagement list with WhatsApp-ready phone numbers. Walk-in transactions without customer_id are not included.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param days query int false "Number of days of history (default 365, max 3650)"
+// @Param customer_group_id query int false "Only include customers of this customer group"
+// @Param segment query string false "champions, loyal, new, at_risk, need_attention or lost"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.RFMReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/rfm [get]
+func (h *ReportHandler) HandleRFM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var err error
+	days := 0
+	if s := r.URL.Query().Get("days"); s != "" {
+		days, err = strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid days", http.StatusBadRequest)
+			return
+		}
+	}
+	customerGroupID := 0
+	if s := r.URL.Query().Get("customer_group_id"); s != "" {
+		customerGroupID, err = strconv.Atoi(s)
+		if err != nil || customerGroupID <= 0 {
+			http.Error(w, "Invalid customer_group_id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.reportService.GetRFM(days, customerGroupID, r.URL.Query().Get("segment"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", report.StartDate)
+	endDate, _ := time.Parse("2006-01-02", report.AsOf)
+	h.writeReport(w, r, report, reportExport{
+		name: "rfm", title: "Segmentasi Pelanggan RFM", startDate: startDate, endDate: endDate,
+		build: func() ([]string, [][]export.Cell) { return rfmExportRows(report, h.reportService.Location()) },
+	})
+}
+
+// HandleReorderSuggestions godoc
+// @Summary Get reorder suggestions
+// @Description Forecast daily demand per product from its average sales per weekday over the last lookback_weeks full weeks (today excluded), estimate when the current stock runs out, and suggest an order quantity for products whose stock plus open purchase orders is at or below the reorder point. The reorder point is the forecast demand during the supplier lead time plus safety stock (1.65 x standard deviation of daily demand x square root of the lead time); the suggested quantity, rounded up to whole units, covers the lead time plus coverage_days. A product's supplier is the supplier of its latest non-cancelled purchase order, using that supplier's lead_time_days; products without purchase orders use default_lead_time_days. Sales are taken from the stock ledger, so components sold inside bundles count. Sorted by days until stockout.
+// @Tags reports
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param lookback_weeks query int false "Weeks of sales history (default 8, max 52)"
+// @Param coverage_days query int false "Days of demand to cover after the lead time (default 14)"
+// @Param default_lead_time_days query int false "Lead time for products without a supplier (default 7)"
+// @Param supplier_id query int false "Only include products whose latest supplier is this supplier"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Param format query string false "json (default), csv, xlsx or pdf"
+// @Success 200 {object} models.ReorderReport
+// @Failure 400 {string} string "Invalid request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/reorder-suggestions [get]
+func (h *ReportHandler) HandleReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetReorderSuggestions(w, r)
+	case http.MethodPost:
+		h.CreateReorderPurchaseOrder(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReportHandler) GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.reorderSuggestions(w, r)
+	if !ok {
+		return
+	}
+
+	categoryID := 0
+	if report.CategoryID != nil {
+		categoryID = *report.CategoryID
+	}
+	today := h.reportService.Today()
+	h.writeReport(w, r, report, reportExport{
+		name: "saran-pemesanan", title: "Saran Pemesanan Ulang", startDate: today, endDate: today, categoryID: categoryID,
+		build: func() ([]string, [][]export.Cell) { return reorderExportRows(report) },
+	})
+}
+
+// CreateReorderPurchaseOrder godoc
+// @Summary Create a draft purchase order from reorder suggestions
+// @Description Compute the reorder suggestions with the same parameters as GET and create a draft purchase order for supplier_id with every suggested product whose latest supplier is supplier_id, at the suggested quantity and the product's cost price. The draft can be edited before it is sent.
+// @Tags reports
+// @Produce json
+// @Param supplier_id query int true "Supplier ID"
+// @Param lookback_weeks query int false "Weeks of sales history (default 8, max 52)"
+// @Param coverage_days query int false "Days of demand to cover after the lead time (default 14)"
+// @Param category_id query int false "Only include products of this category and its sub-categories"
+// @Success 201 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Invalid request or nothing to reorder"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/reorder-suggestions [post]
+func (h *ReportHandler) CreateReorderPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("supplier_id") == "" {
+		http.Error(w, "supplier_id wajib diisi", http.StatusBadRequest)
+		return
+	}
+	report, ok := h.reorderSuggestions(w, r)
+	if !ok {
+		return
+	}
+
+	po, err := services.ReorderPurchaseOrder(report, *report.SupplierID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if err := h.purchaseOrderService.Create(po); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(po)
+}
+
+// reorderSuggestions membaca parameter saran pemesanan ulang dan menghitung
+// laporannya. Jika gagal, error sudah ditulis ke w.
+func (h *ReportHandler) reorderSuggestions(w http.ResponseWriter, r *http.Request) (*models.ReorderReport, bool) {
+	categoryID, err := parseCategoryID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	supplierID := 0
+	if s := r.URL.Query().Get("supplier_id"); s != "" {
+		supplierID, err = strconv.Atoi(s)
+		if err != nil || supplierID <= 0 {
+			http.Error(w, "Invalid supplier_id", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	lookbackWeeks := 0
+	if s := r.URL.Query().Get("lookback_weeks"); s != "" {
+		lookbackWeeks, err = strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid lookback_weeks", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	coverageDays, err := parseOptionalInt(r, "coverage_days")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	defaultLeadTime, err := parseOptionalInt(r, "default_lead_time_days")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	report, err := h.reportService.GetReorderSuggestions(lookbackWeeks, coverageDays, defaultLeadTime, categoryID, supplierID)
+	if err != nil {
+		writeServiceError(w, err)
+		return nil, false
+	}
+	return report, true
+}
+
+// parseDateRange membaca start_date dan end_date (YYYY-MM-DD). Jika salah
+// satu kosong, rentang default adalah hari ini di zona waktu toko.
+func (h *ReportHandler) parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	startDateStr := r.URL.Query().Get("start_date")
+	endDateStr := r.URL.Query().Get("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		today := h.reportService.Today()
+		return today, today, nil
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid start_date format. Use YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid end_date format. Use YYYY-MM-DD")
+	}
+
+	return startDate, endDate, nil
+}
+
+// writeServiceError menulis error dari service laporan. Hanya
+// services.ValidationError yang berupa kesalahan parameter (400); error lain
+// dicatat ke log dan dibalas 500 tanpa detail.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var invalid *services.ValidationError
+	if errors.As(err, &invalid) {
+		http.Error(w, invalid.Message, http.StatusBadRequest)
+		return
+	}
+	log.Printf("report: %v", err)
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
+// parseCategoryID membaca query category_id. Nilai 0 berarti tanpa filter
+// kategori.
+func parseCategoryID(r *http.Request) (int, error) {
+	s := r.URL.Query().Get("category_id")
+	if s == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.New("Invalid category_id")
+	}
+	return id, nil
+}
+
+// parseOptionalFloat membaca query name sebagai angka hingga; kosong berarti
+// nil. NaN dan Inf ditolak karena lolos dari perbandingan batas di service.
+func parseOptionalFloat(r *http.Request, name string) (*float64, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, errors.New("Invalid " + name)
+	}
+	return &v, nil
+}
+
+// parseOptionalInt membaca query name sebagai bilangan bulat; kosong berarti
+// nil.
+func parseOptionalInt(r *http.Request, name string) (*int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, errors.New("Invalid " + name)
+	}
+	return &v, nil
 }
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kasir-api/services"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"validation", &services.ValidationError{Message: "limit harus antara 1 dan 500"}, http.StatusBadRequest, "limit harus antara 1 dan 500"},
+		{"wrapped validation", fmt.Errorf("ranking: %w", &services.ValidationError{Message: "by harus qty, revenue atau profit"}), http.StatusBadRequest, "by harus qty, revenue atau profit"},
+		{"database", errors.New(`pq: relation "transactions" does not exist`), http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeServiceError(w, tt.err)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseOptionalFloat(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    *float64
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"slow_threshold=12.5", ptr(12.5), false},
+		{"slow_threshold=0", ptr(0.0), false},
+		{"slow_threshold=-3", ptr(-3.0), false},
+		{"slow_threshold=abc", nil, true},
+		{"slow_threshold=NaN", nil, true},
+		{"slow_threshold=nan", nil, true},
+		{"slow_threshold=Inf", nil, true},
+		{"slow_threshold=-Infinity", nil, true},
+		{"slow_threshold=1e400", nil, true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/report/dead-stock?"+tt.query, nil)
+		got, err := parseOptionalFloat(r, "slow_threshold")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: err = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/services"
+)
+
+type StockLotHandler struct {
+	service *services.StockLotService
+}
+
+func NewStockLotHandler(service *services.StockLotService) *StockLotHandler {
+	return &StockLotHandler{service: service}
+}
+
+// HandleProductLots - GET/POST /api/produk/{id}/lots
+func (h *StockLotHandler) HandleProductLots(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/lots")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByProductID(w, r, id)
+	case http.MethodPost:
+		h.Receive(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// @Summary Get stock lots of a product
+// @Description Get lots of a lot-tracked product, earliest expiry first
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.StockLot
+// @Failure 400,404 {string} string "Invalid ID or Product not found"
+// @Router /produk/{id}/lots [get]
+func (h *StockLotHandler) GetByProductID(w http.ResponseWriter, r *http.Request, id int) {
+	lots, err := h.service.GetByProductID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lots)
+}
+
+// @Summary Receive stock into a lot
+// @Description Receive stock for a lot-tracked product into a lot (created if it does not exist). Stock increases through the stock ledger.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param lot body models.ReceiveLotRequest true "Lot data"
+// @Success 201 {object} models.StockLot
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Product not found"
+// @Router /produk/{id}/lots [post]
+func (h *StockLotHandler) Receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req models.ReceiveLotRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lot, err := h.service.Receive(id, req)
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(lot)
+}
+
+// HandleExpiringReport godoc
+// @Summary Get expiring stock lots
+// @Description Get lots with remaining stock that expire within the given number of days, including lots that have already expired (which cannot be sold)
+// @Tags reports
+// @Produce json
+// @Param days query int false "Look-ahead window in days (default 30)"
+// @Success 200 {array} models.ExpiringLot
+// @Failure 400 {string} string "Invalid days"
+// @Failure 500 {string} string "Internal server error"
+// @Router /report/expiring [get]
+func (h *StockLotHandler) HandleExpiringReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := 30
+	if s := r.URL.Query().Get("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid days", http.StatusBadRequest)
+			return
+		}
+		days = d
+	}
+
+	lots, err := h.service.GetExpiring(days)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lots)
+}
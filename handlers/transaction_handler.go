@@ -27,7 +27,7 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 
 // Checkout godoc
 // @Summary Checkout transaction
-// @Description Create a new transaction with multiple items
+// @Description Create a new transaction with multiple items. Wholesale price tiers (and the price list of customer_group_id, if given) are applied per item; each detail shows unit_price and the applied price_tier. customer_id attributes the sale to a registered customer; without customer_group_id, that customer's group price list is used.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -49,7 +49,7 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	transaction, err := h.service.Checkout(req.Items, req.CustomerID, req.CustomerGroupID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -59,3 +59,41 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(transaction)
 }
+
+// HandleQuote godoc
+// @Summary Quote checkout prices
+// @Description Price a cart exactly like checkout (units, wholesale tiers and customer group price list) without creating a transaction or deducting stock
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param request body models.CheckoutRequest true "Checkout items"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {string} string "Invalid request"
+// @Router /checkout/quote [post]
+func (h *TransactionHandler) HandleQuote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CheckoutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		http.Error(w, "Items cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	quote, err := h.service.Quote(req.Items, req.CustomerID, req.CustomerGroupID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
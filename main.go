@@ -1,18 +1,23 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
+	_ "time/tzdata" // database zona waktu ikut di binary, container belum tentu punya /usr/share/zoneinfo
 
 	"kasir-api/database"
+	"kasir-api/delivery"
 	_ "kasir-api/docs"
 	"kasir-api/handlers"
 	"kasir-api/repositories"
 	"kasir-api/services"
+	"kasir-api/storage"
 
 	"github.com/spf13/viper"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -21,6 +26,36 @@ import (
 type Config struct {
 	Port   string `mapstructure:"PORT"`
 	DBConn string `mapstructure:"DB_CONN"`
+
+	StorageDriver   string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalDir string `mapstructure:"STORAGE_LOCAL_DIR"`
+	S3Endpoint      string `mapstructure:"S3_ENDPOINT"`
+	S3Region        string `mapstructure:"S3_REGION"`
+	S3Bucket        string `mapstructure:"S3_BUCKET"`
+	S3AccessKey     string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey     string `mapstructure:"S3_SECRET_KEY"`
+	S3PathStyle     bool   `mapstructure:"S3_PATH_STYLE"`
+	ImageMaxSize    int64  `mapstructure:"IMAGE_MAX_SIZE"`
+
+	PriceSchedulerInterval time.Duration `mapstructure:"PRICE_SCHEDULER_INTERVAL"`
+	StoreTimezone          string        `mapstructure:"STORE_TIMEZONE"`
+	StoreName              string        `mapstructure:"STORE_NAME"`
+
+	BasketWindowDays        int           `mapstructure:"BASKET_WINDOW_DAYS"`
+	BasketSchedulerInterval time.Duration `mapstructure:"BASKET_SCHEDULER_INTERVAL"`
+
+	EODClosingTime       string        `mapstructure:"EOD_CLOSING_TIME"`
+	EODSchedulerInterval time.Duration `mapstructure:"EOD_SCHEDULER_INTERVAL"`
+	EODDelivery          string        `mapstructure:"EOD_DELIVERY"`
+	EODFileDir           string        `mapstructure:"EOD_FILE_DIR"`
+	EODEmailTo           string        `mapstructure:"EOD_EMAIL_TO"`
+	EODWebhookURL        string        `mapstructure:"EOD_WEBHOOK_URL"`
+	EODWebhookSecret     string        `mapstructure:"EOD_WEBHOOK_SECRET"`
+	SMTPHost             string        `mapstructure:"SMTP_HOST"`
+	SMTPPort             int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom             string        `mapstructure:"SMTP_FROM"`
 }
 
 const homeHTML = `<!DOCTYPE html>
@@ -151,9 +186,42 @@ const homeHTML = `<!DOCTYPE html>
                 <h2>Product Service <span class="badge">CRUD</span></h2>
                 <p>Manage inventory items.</p>
                 <div class="endpoint"><span class="method get">GET</span> <a href="/api/produk">/api/produk</a></div>
+                <div class="endpoint"><span class="method get">GET</span> /api/produk/search?q=</div>
                 <div class="endpoint"><span class="method post">POST</span> /api/produk</div>
                 <div class="endpoint"><span class="method put">PUT</span> /api/produk/:id</div>
                 <div class="endpoint"><span class="method delete">DEL</span> /api/produk/:id</div>
+                <div class="endpoint"><span class="method post">POST</span> /api/produk/:id/images</div>
+                <div class="endpoint"><span class="method post">POST</span> /api/produk/:id/price-schedules</div>
+            </div>
+
+            <div class="card">
+                <h2>Supplier Service <span class="badge">CRUD</span></h2>
+                <p>Manage the supplier directory.</p>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/suppliers">/api/suppliers</a></div>
+                <div class="endpoint"><span class="method post">POST</span> /api/suppliers</div>
+                <div class="endpoint"><span class="method put">PUT</span> /api/suppliers/:id</div>
+                <div class="endpoint"><span class="method delete">DEL</span> /api/suppliers/:id</div>
+            </div>
+
+            <div class="card">
+                <h2>Purchase Orders <span class="badge">Purchasing</span></h2>
+                <p>Order from suppliers and receive goods into stock.</p>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/purchase-orders">/api/purchase-orders</a></div>
+                <div class="endpoint"><span class="method post">POST</span> /api/purchase-orders</div>
+                <div class="endpoint"><span class="method post">POST</span> /api/purchase-orders/:id/send</div>
+                <div class="endpoint"><span class="method post">POST</span> /api/purchase-orders/:id/receive</div>
+                <div class="endpoint"><span class="method post">POST</span> /api/purchase-orders/:id/cancel</div>
+            </div>
+
+            <div class="card">
+                <h2>Reports <span class="badge">Analytics</span></h2>
+                <p>Sales summaries and trends.</p>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/hari-ini">/api/report/hari-ini</a></div>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/timeseries">/api/report/timeseries</a></div>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/top-products">/api/report/top-products</a></div>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/sales-by-category">/api/report/sales-by-category</a></div>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/inventory">/api/report/inventory</a></div>
+                <div class="endpoint"><span class="method get">GET</span> <a href="/api/report/eod">/api/report/eod</a></div>
             </div>
         </div>
 
@@ -197,56 +265,224 @@ func main() {
 		_ = viper.ReadInConfig()
 	}
 
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "uploads")
+	viper.SetDefault("S3_PATH_STYLE", true)
+	viper.SetDefault("IMAGE_MAX_SIZE", 5<<20)
+	viper.SetDefault("PRICE_SCHEDULER_INTERVAL", "1m")
+	viper.SetDefault("STORE_TIMEZONE", "Asia/Jakarta")
+	viper.SetDefault("STORE_NAME", "Kasir API")
+	viper.SetDefault("BASKET_WINDOW_DAYS", 90)
+	viper.SetDefault("BASKET_SCHEDULER_INTERVAL", "1h")
+	viper.SetDefault("EOD_CLOSING_TIME", "00:00")
+	viper.SetDefault("EOD_SCHEDULER_INTERVAL", "1m")
+	viper.SetDefault("EOD_FILE_DIR", "eod-reports")
+	viper.SetDefault("SMTP_PORT", 587)
+
 	config := Config{
 		Port:   viper.GetString("PORT"),
 		DBConn: viper.GetString("DB_CONN"),
+
+		StorageDriver:   viper.GetString("STORAGE_DRIVER"),
+		StorageLocalDir: viper.GetString("STORAGE_LOCAL_DIR"),
+		S3Endpoint:      viper.GetString("S3_ENDPOINT"),
+		S3Region:        viper.GetString("S3_REGION"),
+		S3Bucket:        viper.GetString("S3_BUCKET"),
+		S3AccessKey:     viper.GetString("S3_ACCESS_KEY"),
+		S3SecretKey:     viper.GetString("S3_SECRET_KEY"),
+		S3PathStyle:     viper.GetBool("S3_PATH_STYLE"),
+		ImageMaxSize:    viper.GetInt64("IMAGE_MAX_SIZE"),
+
+		PriceSchedulerInterval: viper.GetDuration("PRICE_SCHEDULER_INTERVAL"),
+		StoreTimezone:          viper.GetString("STORE_TIMEZONE"),
+		StoreName:              viper.GetString("STORE_NAME"),
+
+		BasketWindowDays:        viper.GetInt("BASKET_WINDOW_DAYS"),
+		BasketSchedulerInterval: viper.GetDuration("BASKET_SCHEDULER_INTERVAL"),
+
+		EODClosingTime:       viper.GetString("EOD_CLOSING_TIME"),
+		EODSchedulerInterval: viper.GetDuration("EOD_SCHEDULER_INTERVAL"),
+		EODDelivery:          viper.GetString("EOD_DELIVERY"),
+		EODFileDir:           viper.GetString("EOD_FILE_DIR"),
+		EODEmailTo:           viper.GetString("EOD_EMAIL_TO"),
+		EODWebhookURL:        viper.GetString("EOD_WEBHOOK_URL"),
+		EODWebhookSecret:     viper.GetString("EOD_WEBHOOK_SECRET"),
+		SMTPHost:             viper.GetString("SMTP_HOST"),
+		SMTPPort:             viper.GetInt("SMTP_PORT"),
+		SMTPUsername:         viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:         viper.GetString("SMTP_PASSWORD"),
+		SMTPFrom:             viper.GetString("SMTP_FROM"),
 	}
 
 	// Default port jika tidak di-set
 	if config.Port == "" {
 		config.Port = "8080"
 	}
+	if config.PriceSchedulerInterval <= 0 {
+		log.Fatal("PRICE_SCHEDULER_INTERVAL harus lebih dari 0")
+	}
+	if config.EODSchedulerInterval <= 0 {
+		log.Fatal("EOD_SCHEDULER_INTERVAL harus lebih dari 0")
+	}
+	if config.BasketSchedulerInterval <= 0 {
+		log.Fatal("BASKET_SCHEDULER_INTERVAL harus lebih dari 0")
+	}
+
+	// Zona waktu toko untuk batas hari laporan, tanggal kedaluwarsa lot dan
+	// offset waktu di respons API
+	storeLocation, err := time.LoadLocation(config.StoreTimezone)
+	if err != nil {
+		log.Fatal("Invalid STORE_TIMEZONE:", err)
+	}
 
 	// Setup database
-	db, err := database.InitDB(config.DBConn)
+	db, err := database.InitDB(config.DBConn, storeLocation)
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer db.Close()
 
+	// Jalankan migrasi schema
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	// Perintah maintenance, misalnya "kasir-api rollup-rebuild"
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:], db, storeLocation); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Setup storage untuk gambar produk
+	store, err := newStorage(config)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+
 	// Dependency Injection
 	// Product
 	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
-	productHandler := handlers.NewProductHandler(productService)
+	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	productImageRepo := repositories.NewProductImageRepository(db)
+	productImageService := services.NewProductImageService(productImageRepo, productRepo, store, "/images", config.ImageMaxSize)
+	productService := services.NewProductService(productRepo, stockMovementRepo, productImageService)
+	stockLotRepo := repositories.NewStockLotRepository(db)
+	stockLotService := services.NewStockLotService(stockLotRepo, productRepo, storeLocation)
+	stockLotHandler := handlers.NewStockLotHandler(stockLotService)
+	priceRepo := repositories.NewPriceRepository(db)
+	priceService := services.NewPriceService(priceRepo, productRepo)
+	priceHandler := handlers.NewPriceHandler(priceService)
+	imageHandler := handlers.NewImageHandler(productImageService)
 
 	// Category
 	categoryRepo := repositories.NewCategoryRepository(db)
 	categoryService := services.NewCategoryService(categoryRepo)
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, productService)
+
+	// Customer Group
+	customerGroupRepo := repositories.NewCustomerGroupRepository(db)
+	customerGroupService := services.NewCustomerGroupService(customerGroupRepo)
+	customerGroupHandler := handlers.NewCustomerGroupHandler(customerGroupService)
+
+	// Customer
+	customerRepo := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepo)
+	customerHandler := handlers.NewCustomerHandler(customerService)
 
 	// Transaction
 	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
+	transactionService := services.NewTransactionService(transactionRepo, storeLocation)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 
+	// Supplier
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	// Purchase Order
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
 	// Report
-	reportHandler := handlers.NewReportHandler(transactionService)
+	reportRepo := repositories.NewReportRepository(db)
+	reportService := services.NewReportService(reportRepo, storeLocation)
+	reportHandler := handlers.NewReportHandler(transactionService, reportService, purchaseOrderService, config.StoreName)
+
+	// Analisis keranjang; handler produk dibuat setelahnya karena
+	// /api/produk/{id}/frequently-bought-with dilayani basketHandler
+	basketService, err := services.NewBasketService(repositories.NewBasketRepository(db), storeLocation, config.BasketWindowDays)
+	if err != nil {
+		log.Fatal("Invalid BASKET_WINDOW_DAYS:", err)
+	}
+	basketHandler := handlers.NewBasketHandler(basketService, reportHandler)
+	productHandler := handlers.NewProductHandler(productService, productImageService, stockLotHandler, priceHandler, basketHandler)
+
+	// End-of-day report
+	eodChannels, err := newEODChannels(config)
+	if err != nil {
+		log.Fatal("Failed to initialize EOD delivery:", err)
+	}
+	eodService, err := services.NewEODService(repositories.NewEODReportRepository(db), reportRepo, stockLotRepo,
+		eodChannels, storeLocation, config.StoreName, config.EODClosingTime)
+	if err != nil {
+		log.Fatal("Invalid EOD_CLOSING_TIME:", err)
+	}
+	eodHandler := handlers.NewEODHandler(eodService)
 
 	// Setup routes - Products
 	http.HandleFunc("/api/produk", productHandler.HandleProducts)
 	http.HandleFunc("/api/produk/", productHandler.HandleProductByID)
+	http.HandleFunc("/api/produk/search", productHandler.HandleSearch)
+	http.HandleFunc("/api/price-schedules", priceHandler.HandlePriceSchedules)
 
 	// Setup routes - Categories
 	http.HandleFunc("/api/categories", categoryHandler.HandleCategories)
 	http.HandleFunc("/api/categories/", categoryHandler.HandleCategoryByID)
+	http.HandleFunc("/api/categories/tree", categoryHandler.HandleTree)
 
 	// Setup routes - Checkout
 	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
+	http.HandleFunc("/api/checkout/quote", transactionHandler.HandleQuote)
+
+	// Setup routes - Customer Groups
+	http.HandleFunc("/api/customer-groups", customerGroupHandler.HandleCustomerGroups)
+	http.HandleFunc("/api/customer-groups/", customerGroupHandler.HandleCustomerGroupByID)
+
+	// Setup routes - Customers
+	http.HandleFunc("/api/customers", customerHandler.HandleCustomers)
+	http.HandleFunc("/api/customers/", customerHandler.HandleCustomerByID)
 
 	// Setup routes - Report
 	http.HandleFunc("/api/report/hari-ini", reportHandler.HandleTodayReport)
 	http.HandleFunc("/api/report", reportHandler.HandleReport)
+	http.HandleFunc("/api/report/bundle-components", reportHandler.HandleBundleComponentReport)
+	http.HandleFunc("/api/report/timeseries", reportHandler.HandleTimeSeries)
+	http.HandleFunc("/api/report/top-products", reportHandler.HandleTopProducts)
+	http.HandleFunc("/api/report/sales-by-category", reportHandler.HandleCategorySales)
+	http.HandleFunc("/api/report/sales-by-product", reportHandler.HandleProductSales)
+	http.HandleFunc("/api/report/inventory", reportHandler.HandleInventoryValuation)
+	http.HandleFunc("/api/report/dead-stock", reportHandler.HandleDeadStock)
+	http.HandleFunc("/api/report/abc", reportHandler.HandleABC)
+	http.HandleFunc("/api/report/reorder-suggestions", reportHandler.HandleReorderSuggestions)
+	http.HandleFunc("/api/report/rfm", reportHandler.HandleRFM)
+	http.HandleFunc("/api/report/product-pairs", basketHandler.HandleProductPairs)
+	http.HandleFunc("/api/report/expiring", stockLotHandler.HandleExpiringReport)
+	http.HandleFunc("/api/report/eod", eodHandler.HandleEODReports)
+	http.HandleFunc("/api/report/eod/", eodHandler.HandleEODReportByDate)
+
+	// Setup routes - Suppliers
+	http.HandleFunc("/api/suppliers", supplierHandler.HandleSuppliers)
+	http.HandleFunc("/api/suppliers/", supplierHandler.HandleSupplierByID)
+
+	// Setup routes - Purchase Orders
+	http.HandleFunc("/api/purchase-orders", purchaseOrderHandler.HandlePurchaseOrders)
+	http.HandleFunc("/api/purchase-orders/", purchaseOrderHandler.HandlePurchaseOrderByID)
+
+	// Setup routes - Images
+	http.HandleFunc("/images/", imageHandler.ServeImage)
 
 	// Health check
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -267,6 +503,15 @@ func main() {
 	// Swagger UI
 	http.Handle("/swagger/", httpSwagger.WrapHandler)
 
+	// Scheduler perubahan harga
+	go priceService.RunScheduler(context.Background(), config.PriceSchedulerInterval)
+
+	// Scheduler laporan tutup hari
+	go eodService.RunScheduler(context.Background(), config.EODSchedulerInterval)
+
+	// Analisis keranjang harian
+	go basketService.RunScheduler(context.Background(), config.BasketSchedulerInterval)
+
 	// Start server
 	addr := "0.0.0.0:" + config.Port
 	fmt.Println("Server running di", addr)
@@ -276,3 +521,60 @@ func main() {
 		fmt.Println("gagal running server", err)
 	}
 }
+
+func newStorage(config Config) (storage.Storage, error) {
+	switch config.StorageDriver {
+	case "local":
+		return storage.NewLocalStorage(config.StorageLocalDir)
+	case "s3":
+		return storage.NewS3Storage(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKey, config.S3SecretKey, config.S3PathStyle)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q (use local or s3)", config.StorageDriver)
+	}
+}
+
+// newEODChannels membuat channel pengiriman laporan tutup hari dari
+// EOD_DELIVERY, daftar dipisah koma berisi file, smtp dan/atau webhook.
+// Kosong berarti laporan hanya diarsipkan.
+func newEODChannels(config Config) ([]delivery.Channel, error) {
+	channels := make([]delivery.Channel, 0)
+	for _, name := range splitList(config.EODDelivery) {
+		var ch delivery.Channel
+		switch name {
+		case "file":
+			store, err := storage.NewLocalStorage(config.EODFileDir)
+			if err != nil {
+				return nil, err
+			}
+			ch = delivery.NewFileChannel(store)
+		case "smtp":
+			smtpChannel, err := delivery.NewSMTPChannel(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword,
+				config.SMTPFrom, splitList(config.EODEmailTo))
+			if err != nil {
+				return nil, err
+			}
+			ch = smtpChannel
+		case "webhook":
+			webhookChannel, err := delivery.NewWebhookChannel(config.EODWebhookURL, config.EODWebhookSecret)
+			if err != nil {
+				return nil, err
+			}
+			ch = webhookChannel
+		default:
+			return nil, fmt.Errorf("unknown EOD_DELIVERY channel %q (use file, smtp or webhook)", name)
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
+
+// splitList memecah daftar dipisah koma dan membuang item kosong.
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Metrik pengurutan laporan pasangan produk.
+const (
+	BasketBySupport = "support"
+	BasketByLift    = "lift"
+)
+
+// BasketAnalysis adalah informasi hasil analisis keranjang terakhir, yaitu
+// transaksi pada [WindowStart, WindowEnd).
+type BasketAnalysis struct {
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+	Transactions int       `json:"transactions"`
+	GeneratedAt  time.Time `json:"generated_at"`
+}
+
+// FrequentlyBoughtWith adalah produk yang sering dibeli bersama satu produk.
+// Analysis kosong jika analisis keranjang belum pernah dihitung.
+type FrequentlyBoughtWith struct {
+	ProductID    int                  `json:"product_id"`
+	ProductName  string               `json:"product_name"`
+	Transactions int                  `json:"transactions"` // transaksi dalam jendela yang memuat produk ini
+	Analysis     *BasketAnalysis      `json:"analysis"`
+	Items        []ProductAssociation `json:"items"`
+}
+
+// ProductAssociation adalah keterkaitan produk X (yang ditanya) dengan produk
+// ini. Support adalah persen transaksi yang memuat keduanya, Confidence
+// persen transaksi berisi X yang juga memuat produk ini, dan Lift
+// perbandingan confidence dengan peluang produk ini dibeli secara umum (di
+// atas 1 berarti lebih sering dibeli bersama daripada kebetulan).
+type ProductAssociation struct {
+	ProductID    int     `json:"product_id"`
+	ProductName  string  `json:"product_name"`
+	Transactions int     `json:"transactions"`
+	Support      float64 `json:"support"`
+	Confidence   float64 `json:"confidence"`
+	Lift         float64 `json:"lift"`
+}
+
+type ProductPairsReport struct {
+	By              string          `json:"by"`
+	MinTransactions int             `json:"min_transactions"`
+	CategoryID      *int            `json:"category_id,omitempty"`
+	Analysis        *BasketAnalysis `json:"analysis"`
+	Items           []ProductPair   `json:"items"`
+}
+
+// ProductPair adalah dua produk yang dibeli dalam transaksi yang sama.
+// ConfidenceAToB adalah persen transaksi berisi produk A yang juga memuat
+// produk B, dan sebaliknya.
+type ProductPair struct {
+	Rank           int     `json:"rank"`
+	ProductAID     int     `json:"product_a_id"`
+	ProductAName   string  `json:"product_a_name"`
+	ProductBID     int     `json:"product_b_id"`
+	ProductBName   string  `json:"product_b_name"`
+	Transactions   int     `json:"transactions"`
+	Support        float64 `json:"support"`
+	ConfidenceAToB float64 `json:"confidence_a_to_b"`
+	ConfidenceBToA float64 `json:"confidence_b_to_a"`
+	Lift           float64 `json:"lift"`
+}
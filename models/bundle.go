@@ -0,0 +1,23 @@
+package models
+
+type BundleComponent struct {
+	ComponentID   int      `json:"component_id"`
+	ComponentName string   `json:"component_name,omitempty"`
+	Quantity      Quantity `json:"quantity" swaggertype:"number"`
+}
+
+// TransactionDetailComponent adalah bagian dari penjualan bundle yang
+// diatribusikan ke satu komponen.
+type TransactionDetailComponent struct {
+	ProductID   int      `json:"product_id"`
+	ProductName string   `json:"product_name,omitempty"`
+	Quantity    Quantity `json:"quantity" swaggertype:"number"`
+	Revenue     int      `json:"revenue"`
+}
+
+type BundleComponentSales struct {
+	ProductID   int      `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	Quantity    Quantity `json:"quantity" swaggertype:"number"`
+	Revenue     int      `json:"revenue"`
+}
@@ -2,10 +2,38 @@ package models
 
 import "time"
 
+// Strategi penghapusan kategori yang masih dipakai produk atau sub-kategori.
+const (
+	CategoryDeleteReject   = "reject"   // tolak jika masih dipakai
+	CategoryDeleteReassign = "reassign" // pindahkan produk dan sub-kategori ke kategori lain
+	CategoryDeleteDetach   = "detach"   // lepas kategori dari produk, sub-kategori naik satu level
+)
+
 type Category struct {
-	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	ID          int                  `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	ParentID    *int                 `json:"parent_id,omitempty"`
+	Path        []CategoryBreadcrumb `json:"path,omitempty"` // dari root sampai kategori ini
+	Stats       *CategoryStats       `json:"stats,omitempty"`
+	Children    []Category           `json:"children,omitempty"` // hanya diisi di /categories/tree
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   *time.Time           `json:"updated_at,omitempty"`
+}
+
+// CategoryBreadcrumb adalah satu langkah pada jalur kategori, misalnya
+// Minuman > Kopi > Kopi Susu.
+type CategoryBreadcrumb struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryStats adalah ringkasan produk dalam kategori beserta seluruh
+// sub-kategorinya. Bundle ikut dihitung di ProductCount tapi tidak di stok,
+// karena stoknya adalah stok komponen.
+type CategoryStats struct {
+	ProductCount   int      `json:"product_count"`
+	StockUnits     Quantity `json:"stock_units" swaggertype:"number"`
+	StockValue     int      `json:"stock_value"`      // stok x harga jual
+	StockCostValue int      `json:"stock_cost_value"` // stok x harga pokok
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type Customer struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Phone             string     `json:"phone"`
+	Email             string     `json:"email"`
+	CustomerGroupID   *int       `json:"customer_group_id"` // daftar harga default saat checkout
+	CustomerGroupName *string    `json:"customer_group_name,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Status pengiriman laporan tutup hari.
+const (
+	DeliveryStatusSent   = "sent"
+	DeliveryStatusFailed = "failed"
+)
+
+// EODReport adalah laporan tutup hari (Z-report) untuk satu tanggal bisnis,
+// mencakup transaksi pada [PeriodStart, PeriodEnd). Setelah diarsipkan isinya
+// tidak berubah.
+type EODReport struct {
+	ID           int                  `json:"id"`
+	BusinessDate string               `json:"business_date"`
+	PeriodStart  time.Time            `json:"period_start"`
+	PeriodEnd    time.Time            `json:"period_end"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+	Timezone     string               `json:"timezone"`
+	StoreName    string               `json:"store_name"`
+	Sales        EODSales             `json:"sales"`
+	Categories   []CategorySales      `json:"categories"`
+	TopProducts  []ProductSalesRanked `json:"top_products"`
+	StockAlerts  EODStockAlerts       `json:"stock_alerts"`
+	Deliveries   []EODDelivery        `json:"deliveries,omitempty"`
+}
+
+type EODSales struct {
+	Revenue            int        `json:"revenue"`
+	Transactions       int        `json:"transactions"`
+	AverageBasket      int        `json:"average_basket"`
+	ItemsSold          Quantity   `json:"items_sold" swaggertype:"number"`
+	Cost               int        `json:"cost"`
+	GrossProfit        int        `json:"gross_profit"`
+	FirstTransactionAt *time.Time `json:"first_transaction_at"`
+	LastTransactionAt  *time.Time `json:"last_transaction_at"`
+}
+
+// EODStockAlerts adalah kondisi stok saat laporan dibuat.
+type EODStockAlerts struct {
+	OutOfStock   []OutOfStockProduct `json:"out_of_stock"`
+	ExpiringLots []ExpiringLot       `json:"expiring_lots"`
+}
+
+type OutOfStockProduct struct {
+	ProductID   int      `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	Stock       Quantity `json:"stock" swaggertype:"number"`
+	Unit        string   `json:"unit"`
+}
+
+// EODDelivery adalah status pengiriman laporan ke satu channel.
+type EODDelivery struct {
+	Channel       string    `json:"channel"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	Error         *string   `json:"error,omitempty"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// EODReportSummary adalah satu baris daftar arsip laporan tutup hari.
+type EODReportSummary struct {
+	ID           int           `json:"id"`
+	BusinessDate string        `json:"business_date"`
+	PeriodStart  time.Time     `json:"period_start"`
+	PeriodEnd    time.Time     `json:"period_end"`
+	GeneratedAt  time.Time     `json:"generated_at"`
+	Revenue      int           `json:"revenue"`
+	Transactions int           `json:"transactions"`
+	Deliveries   []EODDelivery `json:"deliveries"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	PriceChangeManual    = "manual"
+	PriceChangeScheduled = "scheduled"
+)
+
+const (
+	PriceSchedulePending   = "pending"
+	PriceScheduleApplied   = "applied"
+	PriceScheduleCancelled = "cancelled"
+)
+
+// PriceHistory mencatat satu perubahan products.price. OldPrice kosong untuk
+// harga awal saat produk dibuat.
+type PriceHistory struct {
+	ID         int       `json:"id"`
+	ProductID  int       `json:"product_id"`
+	OldPrice   *int      `json:"old_price"`
+	NewPrice   int       `json:"new_price"`
+	Source     string    `json:"source"`
+	ScheduleID *int      `json:"schedule_id,omitempty"`
+	ChangedBy  *string   `json:"changed_by,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// PriceSchedule adalah perubahan harga yang diterapkan otomatis oleh
+// scheduler saat EffectiveAt tercapai.
+type PriceSchedule struct {
+	ID          int        `json:"id"`
+	ProductID   int        `json:"product_id"`
+	ProductName string     `json:"product_name,omitempty"`
+	NewPrice    int        `json:"new_price"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	Status      string     `json:"status"`
+	CreatedBy   *string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+type PriceScheduleRequest struct {
+	NewPrice    int       `json:"new_price"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// PriceTier adalah harga grosir per unit dasar yang berlaku jika jumlah
+// pembelian (dalam unit dasar) mencapai MinQuantity. CustomerGroupID kosong
+// berarti berlaku untuk semua pembeli; jika diisi, tier menjadi bagian dari
+// daftar harga kelompok pelanggan tersebut.
+type PriceTier struct {
+	ID              int      `json:"id,omitempty"`
+	CustomerGroupID *int     `json:"customer_group_id,omitempty"`
+	MinQuantity     Quantity `json:"min_quantity" swaggertype:"number"`
+	Price           int      `json:"price"`
+}
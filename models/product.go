@@ -3,12 +3,37 @@ package models
 import "time"
 
 type Product struct {
-	ID           int        `json:"id"`
-	Name         string     `json:"name"`
-	Price        int        `json:"price"`
-	Stock        int        `json:"stock"`
-	CategoryID   *int       `json:"category_id,omitempty"`
-	CategoryName *string    `json:"category_name,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	ID           int                  `json:"id"`
+	Name         string               `json:"name"`
+	SKU          *string              `json:"sku,omitempty"`
+	Barcode      *string              `json:"barcode,omitempty"`
+	Price        int                  `json:"price"`
+	CostPrice    int                  `json:"cost_price"`
+	Stock        Quantity             `json:"stock" swaggertype:"number"`
+	Unit         string               `json:"unit"`
+	Units        []ProductUnit        `json:"units,omitempty"`
+	PriceTiers   []PriceTier          `json:"price_tiers,omitempty"`
+	CategoryID   *int                 `json:"category_id,omitempty"`
+	CategoryName *string              `json:"category_name,omitempty"`
+	CategoryPath []CategoryBreadcrumb `json:"category_path,omitempty"` // breadcrumb dari kategori root
+	IsBundle     bool                 `json:"is_bundle"`
+	TrackLots    bool                 `json:"track_lots"`
+	Components   []BundleComponent    `json:"components,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    *time.Time           `json:"updated_at,omitempty"`
+	Images       []ProductImage       `json:"images,omitempty"`
+}
+
+type ProductSearchResult struct {
+	Product
+	Score float64 `json:"score"`
+}
+
+// ProductPage adalah satu halaman daftar produk.
+type ProductPage struct {
+	Items      []Product `json:"items"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	Total      int       `json:"total"`
+	TotalPages int       `json:"total_pages"`
 }
@@ -0,0 +1,14 @@
+package models
+
+// DefaultUnit adalah unit dasar produk jika tidak diisi.
+const DefaultUnit = "pcs"
+
+// ProductUnit adalah unit jual alternatif, misalnya "box" berisi 12 pcs.
+// Factor adalah jumlah unit dasar per satu unit ini; Price kosong berarti
+// harga produk x Factor.
+type ProductUnit struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Factor Quantity `json:"factor" swaggertype:"number"`
+	Price  *int     `json:"price,omitempty"`
+}
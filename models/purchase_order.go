@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	Status       string              `json:"status"`
+	Notes        string              `json:"notes"`
+	TotalCost    int                 `json:"total_cost"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    *time.Time          `json:"updated_at,omitempty"`
+	SentAt       *time.Time          `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	Items        []PurchaseOrderItem `json:"items,omitempty"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int      `json:"id"`
+	PurchaseOrderID  int      `json:"purchase_order_id"`
+	ProductID        int      `json:"product_id"`
+	ProductName      string   `json:"product_name,omitempty"`
+	Quantity         Quantity `json:"quantity" swaggertype:"number"`
+	ReceivedQuantity Quantity `json:"received_quantity" swaggertype:"number"`
+	UnitCost         int      `json:"unit_cost"`
+}
+
+type ReceiveItem struct {
+	ItemID     int      `json:"item_id"`
+	Quantity   Quantity `json:"quantity" swaggertype:"number"`
+	UnitCost   *int     `json:"unit_cost,omitempty"`
+	LotNumber  string   `json:"lot_number,omitempty"`
+	ExpiryDate *string  `json:"expiry_date,omitempty"` // YYYY-MM-DD, untuk produk dengan track_lots
+}
+
+type ReceiveRequest struct {
+	Items []ReceiveItem `json:"items"`
+}
@@ -0,0 +1,263 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// QuantityScale adalah jumlah pecahan per satu unit. Quantity punya presisi
+// 3 angka desimal (misalnya 0.75 kg atau 1.125 liter).
+const QuantityScale = 1000
+
+// MaxQuantity adalah quantity terbesar yang muat di kolom NUMERIC(14,3)
+// (99999999999.999). Input dan hasil perkalian di atas batas ini ditolak
+// sebelum sampai ke database.
+const MaxQuantity Quantity = 1e14 - 1
+
+// ErrQuantityOverflow dikembalikan Mul dan MulPrice jika hasilnya tidak muat.
+var ErrQuantityOverflow = errors.New("quantity is out of range")
+
+// Quantity adalah jumlah barang dalam bentuk fixed-point (seperseribu unit)
+// supaya perhitungan stok tidak terkena error pembulatan float. Di JSON
+// ditulis sebagai angka biasa sehingga client lama yang mengirim bilangan
+// bulat tetap bekerja. Input dengan lebih dari 3 angka desimal dibulatkan
+// half-up (menjauhi nol).
+type Quantity int64
+
+func NewQuantity(n int) Quantity {
+	return Quantity(int64(n) * QuantityScale)
+}
+
+// ParseQuantity membaca angka desimal biasa seperti "2", "0.75", ".5" atau
+// "-1.5". Notasi eksponen tidak diterima, dan angka yang melebihi
+// MaxQuantity ditolak supaya tidak overflow diam-diam.
+func ParseQuantity(s string) (Quantity, error) {
+	q, err := parseDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+	if q > MaxQuantity || q < -MaxQuantity {
+		return 0, fmt.Errorf("quantity %q is out of range", s)
+	}
+	return q, nil
+}
+
+// parseDecimal adalah ParseQuantity tanpa batas MaxQuantity, hanya dibatasi
+// int64. Dipakai Scan karena hasil agregat seperti SUM boleh melebihi
+// presisi kolom.
+func parseDecimal(s string) (Quantity, error) {
+	input := s
+	s = strings.TrimSpace(s)
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid quantity %q", input)
+	}
+
+	var whole int64
+	if intPart != "" {
+		var err error
+		whole, err = strconv.ParseInt(intPart, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("quantity %q is out of range", input)
+		}
+	}
+
+	var frac int64
+	for i, c := range fracPart {
+		switch {
+		case i < 3:
+			frac = frac*10 + int64(c-'0')
+		case i == 3 && c >= '5':
+			// Dibulatkan half-up dari angka desimal keempat
+			frac++
+		}
+	}
+	for i := len(fracPart); i < 3; i++ {
+		frac *= 10
+	}
+
+	if whole > (math.MaxInt64-frac)/QuantityScale {
+		return 0, fmt.Errorf("quantity %q is out of range", input)
+	}
+	q := Quantity(whole*QuantityScale + frac)
+	if neg {
+		q = -q
+	}
+	return q, nil
+}
+
+// isDigits bernilai true jika s hanya berisi angka 0-9 (atau kosong).
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String menulis quantity tanpa nol di belakang koma: 2, 0.75, 1.125.
+func (q Quantity) String() string {
+	sign := ""
+	v := int64(q)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	whole := v / QuantityScale
+	frac := v % QuantityScale
+	if frac == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	return sign + strconv.FormatInt(whole, 10) + "." + strings.TrimRight(fmt.Sprintf("%03d", frac), "0")
+}
+
+func (q Quantity) Float64() float64 {
+	return float64(q) / QuantityScale
+}
+
+// IsWhole bernilai true jika quantity tidak punya bagian pecahan.
+func (q Quantity) IsWhole() bool {
+	return q%QuantityScale == 0
+}
+
+// Mul mengalikan dua quantity (misalnya jumlah x faktor konversi unit),
+// dibulatkan half-up ke 3 angka desimal. Hasil yang melebihi MaxQuantity
+// mengembalikan ErrQuantityOverflow.
+func (q Quantity) Mul(other Quantity) (Quantity, error) {
+	v, ok := mulRoundDiv(int64(q), int64(other), QuantityScale)
+	if !ok || Quantity(v) > MaxQuantity || Quantity(v) < -MaxQuantity {
+		return 0, ErrQuantityOverflow
+	}
+	return Quantity(v), nil
+}
+
+// Div membagi quantity dengan quantity lain, dibulatkan ke bawah ke bilangan
+// bulat. Dipakai untuk menghitung berapa bundle yang bisa dibuat dari stok.
+func (q Quantity) Div(other Quantity) int {
+	if other == 0 {
+		return 0
+	}
+	return int(int64(q) / int64(other))
+}
+
+// MulPrice menghitung harga untuk quantity ini dalam Rupiah, dibulatkan
+// half-up ke Rupiah terdekat. Hasil yang tidak muat di int64 mengembalikan
+// ErrQuantityOverflow.
+func (q Quantity) MulPrice(price int) (int, error) {
+	v, ok := mulRoundDiv(int64(q), int64(price), QuantityScale)
+	if !ok {
+		return 0, ErrQuantityOverflow
+	}
+	return int(v), nil
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	// Terima juga angka dalam string, misalnya "0.75"
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		s = str
+	}
+
+	v, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*q = v
+	return nil
+}
+
+// Scan membaca kolom NUMERIC dari database.
+func (q *Quantity) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*q = 0
+		return nil
+	case int64:
+		if v > math.MaxInt64/QuantityScale || v < math.MinInt64/QuantityScale {
+			return fmt.Errorf("quantity %d is out of range", v)
+		}
+		*q = Quantity(v * QuantityScale)
+		return nil
+	case float64:
+		if math.IsNaN(v) || math.Abs(v) > math.MaxInt64/QuantityScale {
+			return fmt.Errorf("quantity %v is out of range", v)
+		}
+		*q = Quantity(math.Round(v * QuantityScale))
+		return nil
+	case string:
+		parsed, err := parseDecimal(v)
+		if err != nil {
+			return err
+		}
+		*q = parsed
+		return nil
+	case []byte:
+		parsed, err := parseDecimal(string(v))
+		if err != nil {
+			return err
+		}
+		*q = parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Quantity", src)
+	}
+}
+
+// Value menulis quantity sebagai string desimal untuk kolom NUMERIC.
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
+
+// mulRoundDiv menghitung a*b/d (d > 0) dengan pembulatan half-up menjauhi
+// nol. Perkalian dilakukan dalam 128 bit sehingga overflow terdeteksi; ok
+// bernilai false jika hasilnya tidak muat di int64.
+func mulRoundDiv(a, b, d int64) (int64, bool) {
+	neg := (a < 0) != (b < 0)
+	hi, lo := bits.Mul64(absUint64(a), absUint64(b))
+	if hi >= uint64(d) {
+		return 0, false
+	}
+	quo, rem := bits.Div64(hi, lo, uint64(d))
+	if rem >= uint64(d)-uint64(d)/2 {
+		quo++
+	}
+	if quo > math.MaxInt64 {
+		return 0, false
+	}
+	if neg {
+		return -int64(quo), true
+	}
+	return int64(quo), true
+}
+
+// absUint64 mengembalikan nilai absolut v, termasuk untuk math.MinInt64.
+func absUint64(v int64) uint64 {
+	if v < 0 {
+		return -uint64(v)
+	}
+	return uint64(v)
+}
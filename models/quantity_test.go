@@ -0,0 +1,307 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Quantity
+		wantErr bool
+	}{
+		{in: "2", want: 2000},
+		{in: "0.75", want: 750},
+		{in: "1.125", want: 1125},
+		{in: "-1.5", want: -1500},
+		{in: "+3", want: 3000},
+		{in: ".5", want: 500},
+		{in: "5.", want: 5000},
+		{in: " 7 ", want: 7000},
+		{in: "007.010", want: 7010},
+		{in: "1.0004", want: 1000},
+		{in: "1.0005", want: 1001},
+		{in: "1.99951", want: 2000},
+		{in: "-0.0005", want: -1},
+		{in: "99999999999.999", want: MaxQuantity},
+		{in: "-99999999999.999", want: -MaxQuantity},
+		{in: "99999999999.9994", want: MaxQuantity},
+
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "+", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "-.", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "1,5", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "-+1", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "1.5e2", wantErr: true},
+		{in: "1 000", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "Inf", wantErr: true},
+		{in: "18446744073709552", wantErr: true},
+		{in: "9223372036854776", wantErr: true},
+		{in: "9223372036854775.808", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+		// Batas NUMERIC(14,3): 11 digit sebelum koma
+		{in: "100000000000", wantErr: true},
+		{in: "-100000000000", wantErr: true},
+		{in: "99999999999.9995", wantErr: true},
+		{in: "9000000000000", wantErr: true},
+		{in: "18446744073709.551", wantErr: true},
+		{in: "9223372036854775.807", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuantity(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseQuantity(%q) = %d, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseQuantity(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	tests := []struct {
+		q    Quantity
+		want string
+	}{
+		{0, "0"},
+		{2000, "2"},
+		{750, "0.75"},
+		{1125, "1.125"},
+		{5, "0.005"},
+		{-1500, "-1.5"},
+		{-5, "-0.005"},
+	}
+	for _, tt := range tests {
+		if got := tt.q.String(); got != tt.want {
+			t.Errorf("Quantity(%d).String() = %q, want %q", int64(tt.q), got, tt.want)
+		}
+	}
+}
+
+func TestQuantityMul(t *testing.T) {
+	tests := []struct {
+		a, b Quantity
+		want Quantity
+	}{
+		{2000, 12000, 24000},  // 2 x 12
+		{750, 1500, 1125},     // 0.75 x 1.5
+		{333, 333, 111},       // 0.333 x 0.333 = 0.110889
+		{5, 100, 1},           // 0.005 x 0.1 = 0.0005, half-up
+		{4, 100, 0},           // 0.004 x 0.1 = 0.0004
+		{-5, 100, -1},         // menjauhi nol
+		{-4, 100, 0},          // -0.0004
+		{1500, -2000, -3000},  // 1.5 x -2
+		{1000, 1000, 1000},    // 1 x 1
+		{12345, 0, 0},         // x 0
+		{1001, 1001, 1002},    // 1.001^2 = 1.002001
+		{2500, 2500, 6250},    // 2.5^2
+		{999, 999, 998},       // 0.998001
+		{-999, 999, -998},     // -0.998001
+		{1, 500, 1},           // 0.0005
+		{-1, 500, -1},         // -0.0005
+		{1, 499, 0},           // 0.000499
+		{3000, 333, 999},      // 3 x 0.333
+		{7, 71, 0},            // 0.000497
+		{100000, 100000, 1e7}, // 100 x 100
+		{MaxQuantity, 1000, MaxQuantity},
+		{-MaxQuantity, 1000, -MaxQuantity},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Mul(tt.b)
+		if err != nil {
+			t.Errorf("%s.Mul(%s): %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s.Mul(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// Overflow int64 di tengah perkalian atau hasil di atas MaxQuantity
+	overflow := []struct{ a, b Quantity }{
+		{9000000000000 * QuantityScale, 12000}, // 9000000000000 x 12
+		{18446744073709551, 12000},             // 18446744073709.551 x 12
+		{math.MaxInt64, math.MaxInt64},
+		{math.MinInt64, 1000},
+		{MaxQuantity, 1001},
+		{10000000000000, 10000}, // 1e10 x 10
+		{-10000000000000, 10000},
+	}
+	for _, tt := range overflow {
+		if got, err := tt.a.Mul(tt.b); err != ErrQuantityOverflow {
+			t.Errorf("%d.Mul(%d) = %d, %v; want ErrQuantityOverflow", int64(tt.a), int64(tt.b), int64(got), err)
+		}
+	}
+}
+
+func TestQuantityMulPrice(t *testing.T) {
+	tests := []struct {
+		q     Quantity
+		price int
+		want  int
+	}{
+		{2000, 3500, 7000},    // 2 x 3500
+		{750, 12000, 9000},    // 0.75 kg x 12000
+		{333, 1000, 333},      // 0.333 x 1000
+		{1, 500, 1},           // 0.5 Rupiah dibulatkan ke atas
+		{1, 499, 0},           // 0.499 Rupiah dibulatkan ke bawah
+		{-1, 500, -1},         // menjauhi nol
+		{1500, 999, 1499},     // 1498.5
+		{1250, 1001, 1251},    // 1251.25
+		{3, 0, 0},             // harga nol
+		{125, 15999, 2000},    // 1999.875
+		{-1500, 999, -1499},   // -1498.5
+		{1000, 1, 1},          // 1 x 1
+		{10000, 25000, 250e3}, // 10 x 25000
+		{MaxQuantity, 90000, 8999999999999910},
+		{math.MaxInt64, 2, 18446744073709552}, // perkalian antara melebihi int64
+		{math.MinInt64, 1, -9223372036854776},
+	}
+	for _, tt := range tests {
+		got, err := tt.q.MulPrice(tt.price)
+		if err != nil {
+			t.Errorf("%s.MulPrice(%d): %v", tt.q, tt.price, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s.MulPrice(%d) = %d, want %d", tt.q, tt.price, got, tt.want)
+		}
+	}
+
+	overflow := []struct {
+		q     Quantity
+		price int
+	}{
+		{MaxQuantity, math.MaxInt32},
+		{math.MaxInt64, 1001},
+		{math.MinInt64, 1001},
+		{-MaxQuantity, math.MaxInt32},
+	}
+	for _, tt := range overflow {
+		if got, err := tt.q.MulPrice(tt.price); err != ErrQuantityOverflow {
+			t.Errorf("%d.MulPrice(%d) = %d, %v; want ErrQuantityOverflow", int64(tt.q), tt.price, got, err)
+		}
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	type item struct {
+		Quantity Quantity `json:"quantity"`
+	}
+
+	for _, q := range []Quantity{0, 2000, 750, 1125, -1500, 5, MaxQuantity, -MaxQuantity} {
+		data, err := json.Marshal(item{Quantity: q})
+		if err != nil {
+			t.Fatalf("Marshal(%d): %v", int64(q), err)
+		}
+		var got item
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got.Quantity != q {
+			t.Errorf("round trip of %d via %s = %d", int64(q), data, int64(got.Quantity))
+		}
+	}
+
+	unmarshal := []struct {
+		in      string
+		want    Quantity
+		wantErr bool
+	}{
+		{in: `{"quantity": 3}`, want: 3000},
+		{in: `{"quantity": 0.75}`, want: 750},
+		{in: `{"quantity": "0.75"}`, want: 750},
+		{in: `{"quantity": null}`, want: 1234}, // null tidak mengubah nilai
+		{in: `{"quantity": 1e3}`, wantErr: true},
+		{in: `{"quantity": "."}`, wantErr: true},
+		{in: `{"quantity": 18446744073709552}`, wantErr: true},
+		{in: `{"quantity": 100000000000}`, wantErr: true},
+		{in: `{"quantity": true}`, wantErr: true},
+	}
+	for _, tt := range unmarshal {
+		got := item{Quantity: 1234}
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %d, want error", tt.in, int64(got.Quantity))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if got.Quantity != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, int64(got.Quantity), int64(tt.want))
+		}
+	}
+}
+
+func TestQuantityScanValue(t *testing.T) {
+	tests := []struct {
+		src     any
+		want    Quantity
+		wantErr bool
+	}{
+		{src: nil, want: 0},
+		{src: int64(12), want: 12000},
+		{src: float64(0.75), want: 750},
+		{src: float64(1.0005), want: 1001},
+		{src: "1.125", want: 1125},
+		{src: []byte("-2.500"), want: -2500},
+		{src: "12.000", want: 12000},
+		{src: int64(math.MaxInt64), wantErr: true},
+		{src: math.NaN(), wantErr: true},
+		{src: math.Inf(1), wantErr: true},
+		{src: "1e3", wantErr: true},
+		{src: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		var got Quantity
+		err := got.Scan(tt.src)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Scan(%v) = %d, want error", tt.src, int64(got))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Scan(%v): %v", tt.src, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.src, int64(got), int64(tt.want))
+		}
+	}
+
+	// Value menulis string desimal yang bisa di-Scan kembali
+	for _, q := range []Quantity{0, 1, 750, -1500, 123456789, math.MaxInt64, -math.MaxInt64} {
+		v, err := q.Value()
+		if err != nil {
+			t.Fatalf("Value(%d): %v", int64(q), err)
+		}
+		var got Quantity
+		if err := got.Scan(v); err != nil {
+			t.Fatalf("Scan(Value(%d)) = Scan(%v): %v", int64(q), v, err)
+		}
+		if got != q {
+			t.Errorf("Scan(Value(%d)) = %d", int64(q), int64(got))
+		}
+	}
+}
@@ -1,12 +1,414 @@
 package models
 
+import "time"
+
 type BestSellingProduct struct {
-	Nama       string `json:"nama"`
-	QtyTerjual int    `json:"qty_terjual"`
+	Nama       string   `json:"nama"`
+	QtyTerjual Quantity `json:"qty_terjual" swaggertype:"number"`
 }
 
 type SalesReport struct {
 	TotalRevenue   int                 `json:"total_revenue"`
 	TotalTransaksi int                 `json:"total_transaksi"`
 	ProdukTerlaris *BestSellingProduct `json:"produk_terlaris"`
+	Comparison     *SalesComparison    `json:"comparison,omitempty"`
+}
+
+// Mode perbandingan laporan penjualan.
+const (
+	ComparePreviousPeriod = "previous"  // periode dengan panjang sama tepat sebelumnya
+	CompareLastYear       = "last_year" // tanggal yang sama tahun lalu
+)
+
+// SalesComparison membandingkan periode laporan dengan periode pembanding.
+type SalesComparison struct {
+	Mode              string                  `json:"mode"`
+	StartDate         string                  `json:"start_date"`
+	EndDate           string                  `json:"end_date"`
+	PreviousStartDate string                  `json:"previous_start_date"`
+	PreviousEndDate   string                  `json:"previous_end_date"`
+	Revenue           MetricChange            `json:"revenue"`
+	Transactions      MetricChange            `json:"transactions"`
+	AverageBasket     MetricChange            `json:"average_basket"`
+	Products          []ProductQuantityChange `json:"products"`
+}
+
+// MetricChange adalah nilai periode ini dan pembanding. ChangePercent kosong
+// jika nilai pembanding nol.
+type MetricChange struct {
+	Current       int      `json:"current"`
+	Previous      int      `json:"previous"`
+	Change        int      `json:"change"`
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+// ProductQuantityChange adalah perubahan jumlah terjual (unit dasar) satu
+// produk antara dua periode.
+type ProductQuantityChange struct {
+	ProductID        int      `json:"product_id"`
+	ProductName      string   `json:"product_name"`
+	CurrentQuantity  Quantity `json:"current_quantity" swaggertype:"number"`
+	PreviousQuantity Quantity `json:"previous_quantity" swaggertype:"number"`
+	Change           Quantity `json:"change" swaggertype:"number"`
+	ChangePercent    *float64 `json:"change_percent"`
+}
+
+// Granularity time-series laporan penjualan.
+const (
+	GranularityHour  = "hour"
+	GranularityDay   = "day"
+	GranularityWeek  = "week" // minggu dimulai hari Senin
+	GranularityMonth = "month"
+)
+
+// SalesTimeSeries adalah penjualan per bucket waktu dalam zona waktu toko.
+// Bucket tanpa transaksi tetap dikembalikan dengan nilai nol.
+type SalesTimeSeries struct {
+	StartDate   string        `json:"start_date"`
+	EndDate     string        `json:"end_date"`
+	Granularity string        `json:"granularity"`
+	Timezone    string        `json:"timezone"`
+	Buckets     []SalesBucket `json:"buckets"`
+}
+
+type SalesBucket struct {
+	Start         time.Time `json:"start"`
+	Revenue       int       `json:"revenue"`
+	Transactions  int       `json:"transactions"`
+	ItemsSold     Quantity  `json:"items_sold" swaggertype:"number"`
+	AverageBasket int       `json:"average_basket"` // revenue / transactions, dibulatkan ke Rupiah
+}
+
+// Urutan dan metrik laporan produk terlaris.
+const (
+	ProductRankByQty     = "qty"
+	ProductRankByRevenue = "revenue"
+	ProductRankByProfit  = "profit"
+
+	ProductRankTop    = "top"
+	ProductRankBottom = "bottom"
+)
+
+// ProductRankingReport adalah daftar N produk teratas (atau terbawah) dalam
+// suatu periode.
+type ProductRankingReport struct {
+	StartDate  string               `json:"start_date"`
+	EndDate    string               `json:"end_date"`
+	By         string               `json:"by"`
+	Order      string               `json:"order"`
+	CategoryID *int                 `json:"category_id,omitempty"`
+	Items      []ProductSalesRanked `json:"items"`
+}
+
+// ProductSalesRanked adalah penjualan satu produk. Quantity dalam unit dasar
+// produk; Profit = Revenue - Cost dengan Cost berupa HPP saat checkout.
+type ProductSalesRanked struct {
+	Rank         int      `json:"rank"`
+	ProductID    int      `json:"product_id"`
+	ProductName  string   `json:"product_name"`
+	CategoryID   *int     `json:"category_id"`
+	CategoryName *string  `json:"category_name"`
+	Quantity     Quantity `json:"quantity" swaggertype:"number"`
+	Revenue      int      `json:"revenue"`
+	Cost         int      `json:"cost"`
+	Profit       int      `json:"profit"`
+	Transactions int      `json:"transactions"`
+}
+
+// UncategorizedName adalah nama kelompok produk tanpa kategori di laporan.
+const UncategorizedName = "Uncategorized"
+
+// SalesBreakdownTotals adalah total penjualan periode, dasar perhitungan
+// persentase share pada laporan breakdown.
+type SalesBreakdownTotals struct {
+	Revenue      int      `json:"revenue"`
+	Quantity     Quantity `json:"quantity" swaggertype:"number"`
+	Transactions int      `json:"transactions"`
+}
+
+// CategorySalesReport adalah penjualan per kategori dalam suatu periode.
+type CategorySalesReport struct {
+	StartDate  string               `json:"start_date"`
+	EndDate    string               `json:"end_date"`
+	CategoryID *int                 `json:"category_id,omitempty"`
+	Totals     SalesBreakdownTotals `json:"totals"`
+	Items      []CategorySales      `json:"items"`
+}
+
+// CategorySales adalah penjualan produk yang langsung berada di satu
+// kategori. CategoryID kosong untuk produk tanpa kategori. Share dalam
+// persen terhadap total periode.
+type CategorySales struct {
+	CategoryID    *int     `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	Revenue       int      `json:"revenue"`
+	Quantity      Quantity `json:"quantity" swaggertype:"number"`
+	Transactions  int      `json:"transactions"`
+	RevenueShare  float64  `json:"revenue_share"`
+	QuantityShare float64  `json:"quantity_share"`
+}
+
+// ProductSalesReport adalah penjualan per produk dalam suatu periode.
+type ProductSalesReport struct {
+	StartDate  string               `json:"start_date"`
+	EndDate    string               `json:"end_date"`
+	CategoryID *int                 `json:"category_id,omitempty"`
+	Totals     SalesBreakdownTotals `json:"totals"`
+	Items      []ProductSales       `json:"items"`
+}
+
+type ProductSales struct {
+	ProductID     int      `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	CategoryID    *int     `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	Revenue       int      `json:"revenue"`
+	Quantity      Quantity `json:"quantity" swaggertype:"number"`
+	Transactions  int      `json:"transactions"`
+	RevenueShare  float64  `json:"revenue_share"`
+	QuantityShare float64  `json:"quantity_share"`
+}
+
+// InventoryValuation adalah nilai persediaan saat ini, atau pada akhir
+// tanggal AsOf (zona waktu toko) jika diisi. Nilai jual memakai harga jual,
+// nilai pokok memakai HPP rata-rata; bundle tidak punya stok sendiri
+// sehingga tidak dihitung.
+type InventoryValuation struct {
+	AsOf       *string                  `json:"as_of,omitempty"`
+	CategoryID *int                     `json:"category_id,omitempty"`
+	Totals     InventoryTotals          `json:"totals"`
+	Categories []InventoryCategoryValue `json:"categories"`
+	Products   []InventoryProductValue  `json:"products"`
+}
+
+type InventoryTotals struct {
+	ProductCount int      `json:"product_count"`
+	Stock        Quantity `json:"stock" swaggertype:"number"`
+	RetailValue  int      `json:"retail_value"`
+	CostValue    int      `json:"cost_value"`
+}
+
+// InventoryCategoryValue adalah nilai persediaan produk yang langsung berada
+// di satu kategori. CategoryID kosong untuk produk tanpa kategori.
+type InventoryCategoryValue struct {
+	CategoryID   *int     `json:"category_id"`
+	CategoryName string   `json:"category_name"`
+	ProductCount int      `json:"product_count"`
+	Stock        Quantity `json:"stock" swaggertype:"number"`
+	RetailValue  int      `json:"retail_value"`
+	CostValue    int      `json:"cost_value"`
+}
+
+type InventoryProductValue struct {
+	ProductID    int      `json:"product_id"`
+	ProductName  string   `json:"product_name"`
+	CategoryID   *int     `json:"category_id"`
+	CategoryName string   `json:"category_name"`
+	Unit         string   `json:"unit"`
+	Stock        Quantity `json:"stock" swaggertype:"number"`
+	Price        int      `json:"price"`
+	CostPrice    int      `json:"cost_price"`
+	RetailValue  int      `json:"retail_value"`
+	CostValue    int      `json:"cost_value"`
+}
+
+// Status produk di laporan dead stock.
+const (
+	StockStatusDead = "dead" // tidak terjual sama sekali dalam periode
+	StockStatusSlow = "slow" // terjual, tapi sell-through di bawah ambang
+)
+
+// DeadStockReport adalah produk yang masih punya stok tapi tidak terjual
+// (dead) atau lambat terjual (slow) dalam Days hari terakhir.
+type DeadStockReport struct {
+	AsOf          string          `json:"as_of"`
+	Days          int             `json:"days"`
+	SlowThreshold float64         `json:"slow_threshold"`
+	CategoryID    *int            `json:"category_id,omitempty"`
+	Totals        DeadStockTotals `json:"totals"`
+	Items         []DeadStockItem `json:"items"`
+}
+
+type DeadStockTotals struct {
+	DeadProducts int `json:"dead_products"`
+	SlowProducts int `json:"slow_products"`
+	CostValue    int `json:"cost_value"`
+	RetailValue  int `json:"retail_value"`
+}
+
+// DeadStockItem adalah satu produk di laporan dead stock. SellThrough adalah
+// persen terjual dari stok yang tersedia selama periode, yaitu terjual /
+// (terjual + stok sekarang) x 100. LastSaleAt kosong jika produk belum pernah
+// terjual.
+type DeadStockItem struct {
+	ProductID         int        `json:"product_id"`
+	ProductName       string     `json:"product_name"`
+	CategoryID        *int       `json:"category_id"`
+	CategoryName      string     `json:"category_name"`
+	Status            string     `json:"status"`
+	Stock             Quantity   `json:"stock" swaggertype:"number"`
+	Unit              string     `json:"unit"`
+	SoldQuantity      Quantity   `json:"sold_quantity" swaggertype:"number"`
+	SellThrough       float64    `json:"sell_through"`
+	LastSaleAt        *time.Time `json:"last_sale_at"`
+	DaysSinceLastSale *int       `json:"days_since_last_sale"`
+	CostValue         int        `json:"cost_value"`
+	RetailValue       int        `json:"retail_value"`
+}
+
+// Kelas ABC dan metrik yang bisa dipakai untuk analisis ABC.
+const (
+	ABCClassA = "A"
+	ABCClassB = "B"
+	ABCClassC = "C"
+
+	ABCByRevenue = "revenue"
+	ABCByProfit  = "profit"
+)
+
+// ABCReport mengelompokkan produk ke kelas A, B dan C berdasarkan kontribusi
+// kumulatif terhadap total revenue atau profit periode. Produk masuk kelas A
+// selama kontribusi kumulatif produk-produk sebelumnya masih di bawah CutoffA
+// persen, kelas B di bawah CutoffB, sisanya kelas C.
+type ABCReport struct {
+	StartDate  string            `json:"start_date"`
+	EndDate    string            `json:"end_date"`
+	By         string            `json:"by"`
+	CutoffA    float64           `json:"cutoff_a"`
+	CutoffB    float64           `json:"cutoff_b"`
+	CategoryID *int              `json:"category_id,omitempty"`
+	Total      int               `json:"total"`
+	Classes    []ABCClassSummary `json:"classes"`
+	Items      []ABCProduct      `json:"items"`
+}
+
+type ABCClassSummary struct {
+	Class        string  `json:"class"`
+	ProductCount int     `json:"product_count"`
+	ProductShare float64 `json:"product_share"`
+	Value        int     `json:"value"`
+	ValueShare   float64 `json:"value_share"`
+}
+
+// ABCProduct adalah kelas satu produk. Share dan CumulativeShare dihitung
+// dari metrik by terhadap total positif periode.
+type ABCProduct struct {
+	Rank            int      `json:"rank"`
+	ProductID       int      `json:"product_id"`
+	ProductName     string   `json:"product_name"`
+	CategoryID      *int     `json:"category_id"`
+	CategoryName    *string  `json:"category_name"`
+	Class           string   `json:"class"`
+	Quantity        Quantity `json:"quantity" swaggertype:"number"`
+	Revenue         int      `json:"revenue"`
+	Profit          int      `json:"profit"`
+	Share           float64  `json:"share"`
+	CumulativeShare float64  `json:"cumulative_share"`
+}
+
+// ReorderReport adalah saran pemesanan ulang per AsOf. Permintaan harian
+// diramal dari rata-rata penjualan per hari dalam seminggu selama
+// LookbackWeeks minggu terakhir; jumlah pesanan menutup lead time supplier
+// ditambah CoverageDays hari.
+type ReorderReport struct {
+	AsOf                string              `json:"as_of"`
+	LookbackWeeks       int                 `json:"lookback_weeks"`
+	CoverageDays        int                 `json:"coverage_days"`
+	DefaultLeadTimeDays int                 `json:"default_lead_time_days"`
+	CategoryID          *int                `json:"category_id,omitempty"`
+	SupplierID          *int                `json:"supplier_id,omitempty"`
+	Totals              ReorderTotals       `json:"totals"`
+	Items               []ReorderSuggestion `json:"items"`
+}
+
+type ReorderTotals struct {
+	Products      int `json:"products"`
+	EstimatedCost int `json:"estimated_cost"`
+}
+
+// ReorderSuggestion adalah saran pemesanan ulang satu produk. Supplier adalah
+// supplier purchase order terakhir yang memuat produk ini; tanpa riwayat PO,
+// LeadTimeDays memakai default laporan. WeekdayDemand berisi ramalan
+// permintaan per hari Senin sampai Minggu. DaysUntilStockout kosong jika stok
+// cukup lebih dari setahun.
+type ReorderSuggestion struct {
+	ProductID          int        `json:"product_id"`
+	ProductName        string     `json:"product_name"`
+	CategoryID         *int       `json:"category_id"`
+	CategoryName       string     `json:"category_name"`
+	SupplierID         *int       `json:"supplier_id"`
+	SupplierName       *string    `json:"supplier_name"`
+	LeadTimeDays       int        `json:"lead_time_days"`
+	Unit               string     `json:"unit"`
+	Stock              Quantity   `json:"stock" swaggertype:"number"`
+	OnOrder            Quantity   `json:"on_order" swaggertype:"number"` // belum diterima dari PO sent/partially_received
+	AverageDailyDemand Quantity   `json:"average_daily_demand" swaggertype:"number"`
+	WeekdayDemand      []Quantity `json:"weekday_demand" swaggertype:"array,number"`
+	DaysUntilStockout  *int       `json:"days_until_stockout"`
+	StockoutDate       *string    `json:"stockout_date"`
+	SafetyStock        Quantity   `json:"safety_stock" swaggertype:"number"`
+	ReorderPoint       Quantity   `json:"reorder_point" swaggertype:"number"`
+	SuggestedQuantity  Quantity   `json:"suggested_quantity" swaggertype:"number"`
+	UnitCost           int        `json:"unit_cost"`
+	EstimatedCost      int        `json:"estimated_cost"`
+}
+
+// Segmen pelanggan laporan RFM, urut dari yang paling bernilai.
+const (
+	RFMSegmentChampions     = "champions"
+	RFMSegmentLoyal         = "loyal"
+	RFMSegmentNew           = "new"
+	RFMSegmentAtRisk        = "at_risk"
+	RFMSegmentNeedAttention = "need_attention"
+	RFMSegmentLost          = "lost"
+)
+
+// RFMSegments adalah semua segmen RFM sesuai urutan laporan.
+var RFMSegments = []string{
+	RFMSegmentChampions, RFMSegmentLoyal, RFMSegmentNew,
+	RFMSegmentAtRisk, RFMSegmentNeedAttention, RFMSegmentLost,
+}
+
+// RFMReport menilai pelanggan yang bertransaksi dalam Days hari terakhir
+// (sampai AsOf) dari recency, frequency dan monetary. Skor 1 sampai 5
+// relatif terhadap pelanggan lain dalam laporan yang sama.
+type RFMReport struct {
+	AsOf            string              `json:"as_of"`
+	StartDate       string              `json:"start_date"`
+	Days            int                 `json:"days"`
+	CustomerGroupID *int                `json:"customer_group_id,omitempty"`
+	Segment         string              `json:"segment,omitempty"`
+	Segments        []RFMSegmentSummary `json:"segments"`
+	Customers       []RFMCustomer       `json:"customers"`
+}
+
+// RFMSegmentSummary dihitung dari semua pelanggan dalam laporan, tidak
+// terpengaruh filter segment.
+type RFMSegmentSummary struct {
+	Segment       string  `json:"segment"`
+	Customers     int     `json:"customers"`
+	CustomerShare float64 `json:"customer_share"`
+	Revenue       int     `json:"revenue"`
+	RevenueShare  float64 `json:"revenue_share"`
+}
+
+// RFMCustomer adalah nilai RFM satu pelanggan. RecencyDays adalah jumlah hari
+// sejak transaksi terakhir, Frequency jumlah transaksi dan Monetary total
+// belanja dalam periode. Score adalah gabungan skor R, F dan M, misalnya
+// "545".
+type RFMCustomer struct {
+	CustomerID        int       `json:"customer_id"`
+	CustomerName      string    `json:"customer_name"`
+	Phone             string    `json:"phone"`
+	CustomerGroupID   *int      `json:"customer_group_id"`
+	CustomerGroupName *string   `json:"customer_group_name"`
+	LastPurchaseAt    time.Time `json:"last_purchase_at"`
+	RecencyDays       int       `json:"recency_days"`
+	Frequency         int       `json:"frequency"`
+	Monetary          int       `json:"monetary"`
+	RecencyScore      int       `json:"recency_score"`
+	FrequencyScore    int       `json:"frequency_score"`
+	MonetaryScore     int       `json:"monetary_score"`
+	Score             string    `json:"score"`
+	Segment           string    `json:"segment"`
 }
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type StockLot struct {
+	ID              int       `json:"id"`
+	ProductID       int       `json:"product_id"`
+	ProductName     string    `json:"product_name,omitempty"`
+	LotNumber       string    `json:"lot_number"`
+	ExpiryDate      *string   `json:"expiry_date,omitempty"`
+	Quantity        Quantity  `json:"quantity" swaggertype:"number"`
+	InitialQuantity Quantity  `json:"initial_quantity" swaggertype:"number"`
+	PurchaseOrderID *int      `json:"purchase_order_id,omitempty"`
+	ReceivedAt      time.Time `json:"received_at"`
+}
+
+type ReceiveLotRequest struct {
+	LotNumber  string   `json:"lot_number"`
+	ExpiryDate *string  `json:"expiry_date,omitempty"` // YYYY-MM-DD, kosong untuk barang tanpa kedaluwarsa
+	Quantity   Quantity `json:"quantity" swaggertype:"number"`
+	UnitCost   *int     `json:"unit_cost,omitempty"`
+}
+
+// TransactionDetailLot mencatat lot mana yang dipakai oleh satu baris transaksi.
+type TransactionDetailLot struct {
+	ProductID  int      `json:"product_id"`
+	LotID      int      `json:"lot_id"`
+	LotNumber  string   `json:"lot_number"`
+	ExpiryDate *string  `json:"expiry_date,omitempty"`
+	Quantity   Quantity `json:"quantity" swaggertype:"number"`
+}
+
+type ExpiringLot struct {
+	StockLot
+	DaysUntilExpiry int  `json:"days_until_expiry"`
+	Expired         bool `json:"expired"`
+	CostValue       int  `json:"cost_value"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	StockMovementPurchase   = "purchase"
+	StockMovementSale       = "sale"
+	StockMovementAdjustment = "adjustment"
+	StockMovementReceipt    = "receipt"
+)
+
+type StockMovement struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	Quantity      Quantity  `json:"quantity" swaggertype:"number"`
+	Type          string    `json:"type"`
+	ReferenceType *string   `json:"reference_type,omitempty"`
+	ReferenceID   *int      `json:"reference_id,omitempty"`
+	UnitCost      *int      `json:"unit_cost,omitempty"`
+	StockAfter    Quantity  `json:"stock_after" swaggertype:"number"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Supplier struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	ContactName  string     `json:"contact_name"`
+	Phone        string     `json:"phone"`
+	Email        string     `json:"email"`
+	Address      string     `json:"address"`
+	LeadTimeDays *int       `json:"lead_time_days"` // hari dari PO dikirim sampai diterima; kosong = 7 saat create, tidak diubah saat update
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
@@ -3,26 +3,38 @@ package models
 import "time"
 
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID              int                 `json:"id"`
+	TotalAmount     int                 `json:"total_amount"`
+	CustomerID      *int                `json:"customer_id,omitempty"`
+	CustomerGroupID *int                `json:"customer_group_id,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Details         []TransactionDetail `json:"details"`
 }
 
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID            int                          `json:"id"`
+	TransactionID int                          `json:"transaction_id"`
+	ProductID     int                          `json:"product_id"`
+	ProductName   string                       `json:"product_name,omitempty"`
+	Quantity      Quantity                     `json:"quantity" swaggertype:"number"`
+	Unit          string                       `json:"unit"`
+	BaseQuantity  Quantity                     `json:"base_quantity" swaggertype:"number"`
+	UnitPrice     int                          `json:"unit_price"`
+	PriceTier     *PriceTier                   `json:"price_tier,omitempty"` // tier grosir yang dipakai, kosong jika harga normal
+	Subtotal      int                          `json:"subtotal"`
+	CostAmount    int                          `json:"-"` // HPP saat checkout, hanya untuk laporan laba
+	Components    []TransactionDetailComponent `json:"components,omitempty"`
+	Lots          []TransactionDetailLot       `json:"lots,omitempty"`
 }
 
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int      `json:"product_id"`
+	Quantity  Quantity `json:"quantity" swaggertype:"number"`
+	Unit      string   `json:"unit,omitempty"` // kosong berarti unit dasar produk
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items           []CheckoutItem `json:"items"`
+	CustomerID      *int           `json:"customer_id,omitempty"`       // pelanggan terdaftar; kelompoknya dipakai jika customer_group_id kosong
+	CustomerGroupID *int           `json:"customer_group_id,omitempty"` // untuk daftar harga kelompok pelanggan
 }
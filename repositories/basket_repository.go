@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"kasir-api/models"
+)
+
+type BasketRepository struct {
+	db *sql.DB
+}
+
+func NewBasketRepository(db *sql.DB) *BasketRepository {
+	return &BasketRepository{db: db}
+}
+
+// Rebuild menghitung ulang analisis keranjang dari transaksi pada
+// [start, end) dan mengganti hasil sebelumnya. Sebuah produk dihitung sekali
+// per transaksi walaupun muncul di beberapa baris. Pembaca tetap melihat
+// hasil lama sampai rebuild selesai.
+func (repo *BasketRepository) Rebuild(start, end time.Time) (*models.BasketAnalysis, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Rebuild yang berjalan bersamaan menunggu giliran
+	if _, err := tx.Exec("LOCK TABLE basket_analysis IN EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+	for _, table := range []string{"basket_pairs", "basket_products", "basket_analysis"} {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return nil, err
+		}
+	}
+
+	const items = `
+		SELECT DISTINCT td.transaction_id, td.product_id
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+	`
+	_, err = tx.Exec(`
+		INSERT INTO basket_products (product_id, transactions)
+		SELECT product_id, COUNT(*)
+		FROM (`+items+`) i
+		GROUP BY product_id
+	`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`
+		WITH items AS (`+items+`)
+		INSERT INTO basket_pairs (product_a, product_b, transactions)
+		SELECT a.product_id, b.product_id, COUNT(*)
+		FROM items a
+		JOIN items b ON b.transaction_id = a.transaction_id AND b.product_id > a.product_id
+		GROUP BY a.product_id, b.product_id
+	`, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := models.BasketAnalysis{WindowStart: start, WindowEnd: end}
+	err = tx.QueryRow(`
+		INSERT INTO basket_analysis (window_start, window_end, transactions)
+		SELECT $1, $2, COUNT(*) FROM transactions WHERE created_at >= $1 AND created_at < $2
+		RETURNING transactions, generated_at
+	`, start, end).Scan(&analysis.Transactions, &analysis.GeneratedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// GetAnalysis mengembalikan informasi hasil analisis terakhir, atau nil jika
+// belum pernah dihitung.
+func (repo *BasketRepository) GetAnalysis() (*models.BasketAnalysis, error) {
+	var a models.BasketAnalysis
+	err := repo.db.QueryRow(`
+		SELECT window_start, window_end, transactions, generated_at FROM basket_analysis
+	`).Scan(&a.WindowStart, &a.WindowEnd, &a.Transactions, &a.GeneratedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetProductTransactions mengembalikan nama produk dan jumlah transaksi dalam
+// jendela analisis yang memuat produk tersebut.
+func (repo *BasketRepository) GetProductTransactions(productID int) (string, int, error) {
+	var name string
+	var transactions int
+	err := repo.db.QueryRow(`
+		SELECT p.name, COALESCE(bp.transactions, 0)
+		FROM products p
+		LEFT JOIN basket_products bp ON bp.product_id = p.id
+		WHERE p.id = $1
+	`, productID).Scan(&name, &transactions)
+	if err == sql.ErrNoRows {
+		return "", 0, ErrProductNotFound
+	}
+	return name, transactions, err
+}
+
+// basketMetricsSQL menghitung support (persen semua transaksi), confidence
+// (persen transaksi produk x) dan lift pasangan bp dengan jumlah transaksi
+// produk x dan y, dibulatkan dua desimal.
+func basketMetricsSQL(x, y string) string {
+	return `ROUND(bp.transactions * 100.0 / ba.transactions, 2) AS support,
+		ROUND(bp.transactions * 100.0 / ` + x + `.transactions, 2) AS confidence,
+		ROUND(bp.transactions::numeric * ba.transactions / (` + x + `.transactions::numeric * ` + y + `.transactions), 2) AS lift`
+}
+
+// GetAssociations mengembalikan produk yang dibeli bersama productID dalam
+// minimal minTransactions transaksi, diurutkan dari confidence lalu lift
+// terbesar.
+func (repo *BasketRepository) GetAssociations(productID, minTransactions, limit int) ([]models.ProductAssociation, error) {
+	rows, err := repo.db.Query(`
+		SELECT other.id, other.name, bp.transactions, `+basketMetricsSQL("px", "py")+`
+		FROM basket_pairs bp
+		CROSS JOIN basket_analysis ba
+		JOIN products other ON other.id = CASE WHEN bp.product_a = $1 THEN bp.product_b ELSE bp.product_a END
+		JOIN basket_products px ON px.product_id = $1
+		JOIN basket_products py ON py.product_id = other.id
+		WHERE (bp.product_a = $1 OR bp.product_b = $1) AND bp.transactions >= $2
+		ORDER BY bp.transactions DESC, py.transactions, other.name, other.id
+		LIMIT $3
+	`, productID, minTransactions, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ProductAssociation, 0)
+	for rows.Next() {
+		var item models.ProductAssociation
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.Transactions, &item.Support, &item.Confidence, &item.Lift)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// basketPairOrder adalah urutan laporan pasangan produk per metrik by.
+var basketPairOrder = map[string]string{
+	models.BasketBySupport: "bp.transactions DESC, lift DESC",
+	models.BasketByLift:    "lift DESC, bp.transactions DESC",
+}
+
+// GetPairs mengembalikan limit pasangan produk teratas menurut by (support
+// atau lift) yang dibeli bersama dalam minimal minTransactions transaksi.
+// categoryID bukan 0 membatasi ke pasangan yang salah satu produknya ada di
+// kategori tersebut atau sub-kategorinya.
+func (repo *BasketRepository) GetPairs(categoryID int, by string, minTransactions, limit int) ([]models.ProductPair, error) {
+	query := `
+		SELECT a.id, a.name, b.id, b.name, bp.transactions, ` + basketMetricsSQL("pa", "pb") + `,
+			ROUND(bp.transactions * 100.0 / pb.transactions, 2)
+		FROM basket_pairs bp
+		CROSS JOIN basket_analysis ba
+		JOIN products a ON a.id = bp.product_a
+		JOIN products b ON b.id = bp.product_b
+		JOIN basket_products pa ON pa.product_id = bp.product_a
+		JOIN basket_products pb ON pb.product_id = bp.product_b
+		WHERE bp.transactions >= $2
+		  AND ($1 = 0 OR a.category_id IN ` + categorySubtreeSQL("$1") + ` OR b.category_id IN ` + categorySubtreeSQL("$1") + `)
+		ORDER BY ` + basketPairOrder[by] + `, a.name, b.name, a.id, b.id
+		LIMIT $3
+	`
+	rows, err := repo.db.Query(query, categoryID, minTransactions, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := make([]models.ProductPair, 0)
+	for rows.Next() {
+		var p models.ProductPair
+		err := rows.Scan(&p.ProductAID, &p.ProductAName, &p.ProductBID, &p.ProductBName, &p.Transactions,
+			&p.Support, &p.ConfidenceAToB, &p.Lift, &p.ConfidenceBToA)
+		if err != nil {
+			return nil, err
+		}
+		p.Rank = len(pairs) + 1
+		pairs = append(pairs, p)
+	}
+
+	return pairs, rows.Err()
+}
@@ -3,10 +3,53 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"kasir-api/models"
-	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrCategoryNotFound  = errors.New("kategori tidak ditemukan")
+	ErrCategoryNameTaken = errors.New("nama kategori sudah dipakai")
 )
 
+// CategoryInUseError dikembalikan saat kategori yang masih dipakai dihapus
+// dengan strategi reject.
+type CategoryInUseError struct {
+	ProductCount     int
+	SubcategoryCount int
+}
+
+func (e *CategoryInUseError) Error() string {
+	return fmt.Sprintf("kategori masih dipakai oleh %d produk dan %d sub-kategori", e.ProductCount, e.SubcategoryCount)
+}
+
+// InvalidCategoryParentError dikembalikan saat parent baru atau kategori
+// tujuan reassign tidak ada, atau merupakan kategori itu sendiri atau salah
+// satu turunannya.
+type InvalidCategoryParentError struct {
+	Message string
+}
+
+func (e *InvalidCategoryParentError) Error() string {
+	return e.Message
+}
+
+// categorySubtreeSQL mengembalikan subquery berisi id kategori param beserta
+// seluruh turunannya, dipakai untuk filter kategori yang mencakup
+// sub-kategori, misalnya "p.category_id IN " + categorySubtreeSQL("$2").
+func categorySubtreeSQL(param string) string {
+	return `(
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ` + param + `
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree
+	)`
+}
+
 type CategoryRepository struct {
 	db *sql.DB
 }
@@ -16,7 +59,7 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 }
 
 func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
-	query := "SELECT id, name, description, created_at, updated_at FROM categories ORDER BY created_at DESC"
+	query := "SELECT id, name, description, parent_id, created_at, updated_at FROM categories ORDER BY created_at DESC"
 	rows, err := repo.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -26,76 +69,312 @@ func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
 	categories := make([]models.Category, 0)
 	for rows.Next() {
 		var c models.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.ParentID, &c.CreatedAt, &c.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	paths, err := getCategoryPaths(repo.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		categories[i].Path = paths[categories[i].ID]
+	}
+
+	if err := repo.attachStats(categories); err != nil {
+		return nil, err
+	}
 
 	return categories, nil
 }
 
 func (repo *CategoryRepository) Create(category *models.Category) error {
-	query := "INSERT INTO categories (name, description, created_at) VALUES ($1, $2, $3) RETURNING id"
-	now := time.Now()
-	err := repo.db.QueryRow(query, category.Name, category.Description, now).Scan(&category.ID)
-	if err == nil {
-		category.CreatedAt = now
+	if category.ParentID != nil {
+		if _, err := repo.GetByID(*category.ParentID); err != nil {
+			return fmt.Errorf("parent kategori id %d tidak ditemukan", *category.ParentID)
+		}
+	}
+
+	query := "INSERT INTO categories (name, description, parent_id, created_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP) RETURNING id, created_at"
+	err := repo.db.QueryRow(query, category.Name, category.Description, category.ParentID).Scan(&category.ID, &category.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrCategoryNameTaken
 	}
 	return err
 }
 
 func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
-	query := "SELECT id, name, description, created_at, updated_at FROM categories WHERE id = $1"
+	query := "SELECT id, name, description, parent_id, created_at, updated_at FROM categories WHERE id = $1"
 
 	var c models.Category
-	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.ParentID, &c.CreatedAt, &c.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("kategori tidak ditemukan")
+		return nil, ErrCategoryNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &c, nil
+	paths, err := getCategoryPaths(repo.db, []int{c.ID})
+	if err != nil {
+		return nil, err
+	}
+	c.Path = paths[c.ID]
+
+	categories := []models.Category{c}
+	if err := repo.attachStats(categories); err != nil {
+		return nil, err
+	}
+
+	return &categories[0], nil
 }
 
 func (repo *CategoryRepository) Update(category *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2, updated_at = $3 WHERE id = $4"
-	now := time.Now()
-	result, err := repo.db.Exec(query, category.Name, category.Description, now, category.ID)
+	tx, err := repo.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	if category.ParentID != nil {
+		// Kunci tabel supaya dua update bersamaan tidak bisa membentuk siklus
+		// (A di bawah B dan B di bawah A)
+		if _, err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+		if err := checkCategoryParent(tx, category.ID, *category.ParentID); err != nil {
+			return err
+		}
+	}
 
-	rows, err := result.RowsAffected()
+	query := "UPDATE categories SET name = $1, description = $2, parent_id = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4 RETURNING created_at, updated_at"
+	err = tx.QueryRow(query, category.Name, category.Description, category.ParentID, category.ID).Scan(&category.CreatedAt, &category.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrCategoryNameTaken
+	}
+	if err == sql.ErrNoRows {
+		return ErrCategoryNotFound
+	}
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return errors.New("kategori tidak ditemukan")
+	return tx.Commit()
+}
+
+// Delete menghapus kategori dalam satu transaksi dan mengembalikan jumlah
+// produk yang terdampak. Produk dan sub-kategori ditangani sesuai strategy:
+// reject menolak jika kategori masih dipakai, reassign memindahkan semuanya
+// ke kategori targetID, detach mengosongkan kategori produk dan menaikkan
+// sub-kategori satu level.
+func (repo *CategoryRepository) Delete(id int, strategy string, targetID int) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
 	}
+	defer tx.Rollback()
 
-	category.UpdatedAt = &now
-	return nil
+	// Lock tabel mencegah perubahan parent bersamaan (lihat Update), FOR UPDATE
+	// menahan produk atau sub-kategori baru yang menunjuk kategori ini
+	if _, err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return 0, err
+	}
+
+	var parentID *int
+	err = tx.QueryRow("SELECT parent_id FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return 0, ErrCategoryNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var productCount, subcategoryCount int
+	err = tx.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM products WHERE category_id = $1),
+			(SELECT COUNT(*) FROM categories WHERE parent_id = $1)
+	`, id).Scan(&productCount, &subcategoryCount)
+	if err != nil {
+		return 0, err
+	}
+
+	switch strategy {
+	case models.CategoryDeleteReject:
+		if productCount > 0 || subcategoryCount > 0 {
+			return 0, &CategoryInUseError{ProductCount: productCount, SubcategoryCount: subcategoryCount}
+		}
+	case models.CategoryDeleteReassign:
+		if err := checkCategoryParent(tx, id, targetID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE products SET category_id = $1, updated_at = NOW() WHERE category_id = $2", targetID, id); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE categories SET parent_id = $1, updated_at = NOW() WHERE parent_id = $2", targetID, id); err != nil {
+			return 0, err
+		}
+	case models.CategoryDeleteDetach:
+		if _, err := tx.Exec("UPDATE products SET category_id = NULL, updated_at = NOW() WHERE category_id = $1", id); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE categories SET parent_id = $1, updated_at = NOW() WHERE parent_id = $2", parentID, id); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("strategy %q tidak dikenal", strategy)
+	}
+
+	if _, err := tx.Exec("DELETE FROM categories WHERE id = $1", id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return productCount, nil
 }
 
-func (repo *CategoryRepository) Delete(id int) error {
-	query := "DELETE FROM categories WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+// checkCategoryParent memastikan parentID (parent baru atau kategori tujuan
+// reassign) ada dan bukan kategori itu sendiri atau salah satu turunannya,
+// karena keduanya akan membentuk siklus.
+func checkCategoryParent(tx *sql.Tx, id, parentID int) error {
+	if parentID == id {
+		return &InvalidCategoryParentError{Message: "kategori tujuan tidak boleh kategori itu sendiri"}
+	}
+
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", parentID).Scan(&exists)
 	if err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
+	if !exists {
+		return &InvalidCategoryParentError{Message: fmt.Sprintf("kategori id %d tidak ditemukan", parentID)}
+	}
+
+	var isDescendant bool
+	err = tx.QueryRow("SELECT $2 IN "+categorySubtreeSQL("$1"), id, parentID).Scan(&isDescendant)
 	if err != nil {
 		return err
 	}
+	if isDescendant {
+		return &InvalidCategoryParentError{Message: "kategori tujuan tidak boleh turunan dari kategori ini"}
+	}
+	return nil
+}
+
+// getCategoryPaths mengembalikan breadcrumb (root sampai kategori itu
+// sendiri) untuk setiap id kategori dengan satu query rekursif.
+func getCategoryPaths(db *sql.DB, ids []int) (map[int][]models.CategoryBreadcrumb, error) {
+	paths := make(map[int][]models.CategoryBreadcrumb)
+	if len(ids) == 0 {
+		return paths, nil
+	}
 
-	if rows == 0 {
-		return errors.New("kategori tidak ditemukan")
+	rows, err := db.Query(`
+		WITH RECURSIVE path AS (
+			SELECT id AS leaf_id, id, name, parent_id, 0 AS depth
+			FROM categories
+			WHERE id = ANY($1)
+			UNION ALL
+			SELECT path.leaf_id, c.id, c.name, c.parent_id, path.depth + 1
+			FROM categories c
+			JOIN path ON c.id = path.parent_id
+		)
+		SELECT leaf_id, id, name FROM path ORDER BY leaf_id, depth DESC
+	`, ids)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
+	for rows.Next() {
+		var leafID int
+		var b models.CategoryBreadcrumb
+		if err := rows.Scan(&leafID, &b.ID, &b.Name); err != nil {
+			return nil, err
+		}
+		paths[leafID] = append(paths[leafID], b)
+	}
+
+	return paths, rows.Err()
+}
+
+// attachStats menghitung jumlah produk, total stok dan nilai stok (harga jual
+// dan harga pokok) untuk setiap kategori termasuk sub-kategorinya, dengan
+// satu query untuk seluruh daftar.
+func (repo *CategoryRepository) attachStats(categories []models.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+
+	rows, err := repo.db.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id AS root_id, id FROM categories WHERE id = ANY($1)
+			UNION ALL
+			SELECT tree.root_id, c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+		),
+		per_category AS (
+			SELECT category_id,
+				COUNT(*) AS product_count,
+				SUM(stock) FILTER (WHERE NOT is_bundle) AS stock_units,
+				SUM(stock * price) FILTER (WHERE NOT is_bundle) AS stock_value,
+				SUM(stock * cost_price) FILTER (WHERE NOT is_bundle) AS stock_cost_value
+			FROM products
+			WHERE category_id IS NOT NULL
+			GROUP BY category_id
+		)
+		SELECT tree.root_id,
+			COALESCE(SUM(pc.product_count), 0),
+			COALESCE(SUM(pc.stock_units), 0),
+			ROUND(COALESCE(SUM(pc.stock_value), 0))::bigint,
+			ROUND(COALESCE(SUM(pc.stock_cost_value), 0))::bigint
+		FROM tree
+		LEFT JOIN per_category pc ON pc.category_id = tree.id
+		GROUP BY tree.root_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	stats := make(map[int]*models.CategoryStats)
+	for rows.Next() {
+		var id int
+		var st models.CategoryStats
+		if err := rows.Scan(&id, &st.ProductCount, &st.StockUnits, &st.StockValue, &st.StockCostValue); err != nil {
+			return err
+		}
+		stats[id] = &st
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range categories {
+		categories[i].Stats = stats[categories[i].ID]
+	}
 	return nil
 }
+
+// isUniqueViolation bernilai true jika err adalah pelanggaran unique
+// constraint Postgres.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+)
+
+type CustomerGroupRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerGroupRepository(db *sql.DB) *CustomerGroupRepository {
+	return &CustomerGroupRepository{db: db}
+}
+
+func (repo *CustomerGroupRepository) GetAll() ([]models.CustomerGroup, error) {
+	query := "SELECT id, name, description, created_at, updated_at FROM customer_groups ORDER BY name"
+	rows, err := repo.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make([]models.CustomerGroup, 0)
+	for rows.Next() {
+		var g models.CustomerGroup
+		err := rows.Scan(&g.ID, &g.Name, &g.Description, &g.CreatedAt, &g.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	return groups, rows.Err()
+}
+
+func (repo *CustomerGroupRepository) Create(group *models.CustomerGroup) error {
+	if err := repo.checkNameAvailable(group.Name, 0); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO customer_groups (name, description, created_at) VALUES ($1, $2, CURRENT_TIMESTAMP) RETURNING id, created_at"
+	return repo.db.QueryRow(query, group.Name, group.Description).Scan(&group.ID, &group.CreatedAt)
+}
+
+func (repo *CustomerGroupRepository) GetByID(id int) (*models.CustomerGroup, error) {
+	query := "SELECT id, name, description, created_at, updated_at FROM customer_groups WHERE id = $1"
+
+	var g models.CustomerGroup
+	err := repo.db.QueryRow(query, id).Scan(&g.ID, &g.Name, &g.Description, &g.CreatedAt, &g.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("customer group tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+func (repo *CustomerGroupRepository) Update(group *models.CustomerGroup) error {
+	if err := repo.checkNameAvailable(group.Name, group.ID); err != nil {
+		return err
+	}
+
+	query := "UPDATE customer_groups SET name = $1, description = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING created_at, updated_at"
+	err := repo.db.QueryRow(query, group.Name, group.Description, group.ID).Scan(&group.CreatedAt, &group.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("customer group tidak ditemukan")
+	}
+	return err
+}
+
+func (repo *CustomerGroupRepository) Delete(id int) error {
+	query := "DELETE FROM customer_groups WHERE id = $1"
+	result, err := repo.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("customer group tidak ditemukan")
+	}
+
+	return nil
+}
+
+// checkNameAvailable memastikan nama belum dipakai group lain (tanpa
+// membedakan huruf besar/kecil).
+func (repo *CustomerGroupRepository) checkNameAvailable(name string, excludeID int) error {
+	var exists bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM customer_groups WHERE lower(name) = lower($1) AND id <> $2)", name, excludeID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("nama customer group sudah dipakai")
+	}
+	return nil
+}
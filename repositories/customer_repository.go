@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+)
+
+var ErrCustomerNotFound = errors.New("pelanggan tidak ditemukan")
+
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+const customerColumns = `c.id, c.name, c.phone, c.email, c.customer_group_id, g.name, c.created_at, c.updated_at`
+
+func scanCustomer(row rowScanner, c *models.Customer) error {
+	return row.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.CustomerGroupID, &c.CustomerGroupName, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// GetAll mengembalikan semua pelanggan, atau yang nama atau nomor teleponnya
+// memuat search.
+func (repo *CustomerRepository) GetAll(search string) ([]models.Customer, error) {
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers c
+		LEFT JOIN customer_groups g ON g.id = c.customer_group_id
+		WHERE $1 = '' OR c.name ILIKE '%' || $1 || '%' OR c.phone LIKE '%' || $1 || '%'
+		ORDER BY c.name, c.id
+	`
+	rows, err := repo.db.Query(query, search)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		var c models.Customer
+		if err := scanCustomer(rows, &c); err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+func (repo *CustomerRepository) GetByID(id int) (*models.Customer, error) {
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers c
+		LEFT JOIN customer_groups g ON g.id = c.customer_group_id
+		WHERE c.id = $1
+	`
+
+	var c models.Customer
+	err := scanCustomer(repo.db.QueryRow(query, id), &c)
+	if err == sql.ErrNoRows {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (repo *CustomerRepository) Create(customer *models.Customer) error {
+	if err := repo.checkCustomer(customer); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO customers (name, phone, email, customer_group_id, created_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, created_at"
+	err := repo.db.QueryRow(query, customer.Name, customer.Phone, customer.Email, customer.CustomerGroupID).Scan(&customer.ID, &customer.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return repo.fillGroupName(customer)
+}
+
+func (repo *CustomerRepository) Update(customer *models.Customer) error {
+	if err := repo.checkCustomer(customer); err != nil {
+		return err
+	}
+
+	query := "UPDATE customers SET name = $1, phone = $2, email = $3, customer_group_id = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5 RETURNING created_at, updated_at"
+	err := repo.db.QueryRow(query, customer.Name, customer.Phone, customer.Email, customer.CustomerGroupID, customer.ID).Scan(&customer.CreatedAt, &customer.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrCustomerNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return repo.fillGroupName(customer)
+}
+
+// Delete menghapus pelanggan. Transaksinya tetap ada sebagai transaksi
+// pembeli umum.
+func (repo *CustomerRepository) Delete(id int) error {
+	result, err := repo.db.Exec("DELETE FROM customers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+// checkCustomer memastikan nomor telepon belum dipakai pelanggan lain dan
+// customer group yang dipilih ada.
+func (repo *CustomerRepository) checkCustomer(customer *models.Customer) error {
+	if customer.Phone != "" {
+		var exists bool
+		err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM customers WHERE phone = $1 AND id <> $2)", customer.Phone, customer.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("nomor telepon sudah dipakai pelanggan lain")
+		}
+	}
+
+	if customer.CustomerGroupID != nil {
+		var exists bool
+		err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM customer_groups WHERE id = $1)", *customer.CustomerGroupID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("customer group id %d not found", *customer.CustomerGroupID)
+		}
+	}
+	return nil
+}
+
+func (repo *CustomerRepository) fillGroupName(customer *models.Customer) error {
+	customer.CustomerGroupName = nil
+	if customer.CustomerGroupID == nil {
+		return nil
+	}
+	return repo.db.QueryRow("SELECT name FROM customer_groups WHERE id = $1", *customer.CustomerGroupID).Scan(&customer.CustomerGroupName)
+}
@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"kasir-api/models"
+)
+
+var ErrEODReportNotFound = errors.New("laporan tutup hari tidak ditemukan")
+
+type EODReportRepository struct {
+	db *sql.DB
+}
+
+func NewEODReportRepository(db *sql.DB) *EODReportRepository {
+	return &EODReportRepository{db: db}
+}
+
+// GetSales menghitung ringkasan penjualan transaksi pada [start, end)
+// langsung dari tabel transaksi, karena periode tutup hari tidak selalu
+// sejajar dengan hari kalender rollup. Revenue, jumlah transaksi, item dan
+// HPP dihitung dalam satu query supaya berasal dari snapshot yang sama.
+func (repo *EODReportRepository) GetSales(start, end time.Time) (models.EODSales, error) {
+	var sales models.EODSales
+	err := repo.db.QueryRow(`
+		WITH period AS (
+			SELECT id, total_amount, created_at
+			FROM transactions
+			WHERE created_at >= $1 AND created_at < $2
+		), details AS (
+			SELECT td.transaction_id, SUM(td.base_quantity) AS quantity, SUM(td.cost_amount) AS cost
+			FROM transaction_details td
+			JOIN period t ON t.id = td.transaction_id
+			GROUP BY td.transaction_id
+		)
+		SELECT COALESCE(SUM(t.total_amount), 0), COUNT(*), MIN(t.created_at), MAX(t.created_at),
+			COALESCE(SUM(d.quantity), 0), COALESCE(SUM(d.cost), 0)
+		FROM period t
+		LEFT JOIN details d ON d.transaction_id = t.id
+	`, start, end).Scan(&sales.Revenue, &sales.Transactions, &sales.FirstTransactionAt, &sales.LastTransactionAt,
+		&sales.ItemsSold, &sales.Cost)
+	return sales, err
+}
+
+// GetTopProducts mengembalikan limit produk dengan revenue terbesar pada
+// [start, end).
+func (repo *EODReportRepository) GetTopProducts(start, end time.Time, limit int) ([]models.ProductSalesRanked, error) {
+	rows, err := repo.db.Query(`
+		SELECT p.id, p.name, p.category_id, c.name, SUM(td.base_quantity), SUM(td.subtotal), SUM(td.cost_amount),
+			COUNT(DISTINCT td.transaction_id)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products p ON p.id = td.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY p.id, p.name, p.category_id, c.name
+		ORDER BY SUM(td.subtotal) DESC, p.name, p.id
+		LIMIT $3
+	`, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ProductSalesRanked, 0)
+	for rows.Next() {
+		var item models.ProductSalesRanked
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName,
+			&item.Quantity, &item.Revenue, &item.Cost, &item.Transactions)
+		if err != nil {
+			return nil, err
+		}
+		item.Rank = len(items) + 1
+		item.Profit = item.Revenue - item.Cost
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetOutOfStock mengembalikan produk non-bundle yang stoknya habis atau
+// minus.
+func (repo *EODReportRepository) GetOutOfStock() ([]models.OutOfStockProduct, error) {
+	rows, err := repo.db.Query(`
+		SELECT id, name, stock, unit
+		FROM products
+		WHERE NOT is_bundle AND stock <= 0
+		ORDER BY name, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.OutOfStockProduct, 0)
+	for rows.Next() {
+		var p models.OutOfStockProduct
+		if err := rows.Scan(&p.ProductID, &p.ProductName, &p.Stock, &p.Unit); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
+
+// GetLatest mengembalikan laporan dengan tanggal bisnis terakhir, atau nil
+// jika belum ada laporan.
+func (repo *EODReportRepository) GetLatest() (*models.EODReportSummary, error) {
+	var report models.EODReportSummary
+	row := repo.db.QueryRow("SELECT " + eodSummaryColumns + " FROM eod_reports ORDER BY business_date DESC LIMIT 1")
+	err := scanEODSummary(row, &report)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Create mengarsipkan laporan. Jika laporan untuk tanggal bisnis yang sama
+// sudah ada (misalnya dibuat instance lain), laporan tidak disimpan dan
+// created bernilai false.
+func (repo *EODReportRepository) Create(report *models.EODReport) (created bool, err error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return false, err
+	}
+
+	err = repo.db.QueryRow(`
+		INSERT INTO eod_reports (business_date, period_start, period_end, generated_at, revenue, transactions, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb)
+		ON CONFLICT (business_date) DO NOTHING
+		RETURNING id
+	`, report.BusinessDate, report.PeriodStart, report.PeriodEnd, report.GeneratedAt,
+		report.Sales.Revenue, report.Sales.Transactions, string(data)).Scan(&report.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetByDate mengembalikan laporan lengkap tanggal bisnis date (YYYY-MM-DD)
+// beserta status pengirimannya.
+func (repo *EODReportRepository) GetByDate(date string) (*models.EODReport, error) {
+	var id int
+	var data []byte
+	err := repo.db.QueryRow("SELECT id, data FROM eod_reports WHERE business_date = $1", date).Scan(&id, &data)
+	if err == sql.ErrNoRows {
+		return nil, ErrEODReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report models.EODReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	report.ID = id
+
+	deliveries, err := repo.getDeliveries([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	report.Deliveries = deliveries[id]
+	return &report, nil
+}
+
+// List mengembalikan laporan dengan tanggal bisnis start sampai end
+// (YYYY-MM-DD, inklusif), terbaru lebih dulu.
+func (repo *EODReportRepository) List(start, end string) ([]models.EODReportSummary, error) {
+	return repo.listSummaries(`
+		SELECT `+eodSummaryColumns+`
+		FROM eod_reports
+		WHERE business_date >= $1 AND business_date <= $2
+		ORDER BY business_date DESC
+	`, start, end)
+}
+
+// ListGeneratedSince mengembalikan laporan yang dibuat sejak since beserta
+// status pengirimannya, dipakai untuk mencoba ulang pengiriman yang gagal.
+func (repo *EODReportRepository) ListGeneratedSince(since time.Time) ([]models.EODReportSummary, error) {
+	return repo.listSummaries(`
+		SELECT `+eodSummaryColumns+`
+		FROM eod_reports
+		WHERE generated_at >= $1
+		ORDER BY business_date
+	`, since)
+}
+
+const eodSummaryColumns = `id, to_char(business_date, 'YYYY-MM-DD'), period_start, period_end, generated_at, revenue, transactions`
+
+func scanEODSummary(row rowScanner, r *models.EODReportSummary) error {
+	return row.Scan(&r.ID, &r.BusinessDate, &r.PeriodStart, &r.PeriodEnd, &r.GeneratedAt, &r.Revenue, &r.Transactions)
+}
+
+func (repo *EODReportRepository) listSummaries(query string, args ...any) ([]models.EODReportSummary, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]models.EODReportSummary, 0)
+	ids := make([]int, 0)
+	for rows.Next() {
+		var r models.EODReportSummary
+		if err := scanEODSummary(rows, &r); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+		ids = append(ids, r.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := repo.getDeliveries(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		reports[i].Deliveries = deliveries[reports[i].ID]
+		if reports[i].Deliveries == nil {
+			reports[i].Deliveries = []models.EODDelivery{}
+		}
+	}
+	return reports, nil
+}
+
+// getDeliveries merangkum percobaan pengiriman per laporan dan channel.
+// Jika pernah terkirim, status diambil dari pengiriman yang berhasil; jika
+// belum, dari percobaan terakhir beserta error-nya.
+func (repo *EODReportRepository) getDeliveries(reportIDs []int) (map[int][]models.EODDelivery, error) {
+	result := make(map[int][]models.EODDelivery)
+	if len(reportIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT DISTINCT ON (report_id, channel) report_id, channel, status,
+			COUNT(*) OVER (PARTITION BY report_id, channel), error, attempted_at
+		FROM eod_report_deliveries
+		WHERE report_id = ANY($1)
+		ORDER BY report_id, channel, (status = 'sent') DESC, attempted_at DESC
+	`, reportIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reportID int
+		var d models.EODDelivery
+		if err := rows.Scan(&reportID, &d.Channel, &d.Status, &d.Attempts, &d.Error, &d.LastAttemptAt); err != nil {
+			return nil, err
+		}
+		result[reportID] = append(result[reportID], d)
+	}
+
+	return result, rows.Err()
+}
+
+// RecordDelivery mencatat satu percobaan pengiriman. sendErr nil berarti
+// berhasil.
+func (repo *EODReportRepository) RecordDelivery(reportID int, channel string, sendErr error) error {
+	status := models.DeliveryStatusSent
+	var message *string
+	if sendErr != nil {
+		status = models.DeliveryStatusFailed
+		s := sendErr.Error()
+		message = &s
+	}
+	_, err := repo.db.Exec(`
+		INSERT INTO eod_report_deliveries (report_id, channel, status, error)
+		VALUES ($1, $2, $3, $4)
+	`, reportID, channel, status, message)
+	return err
+}
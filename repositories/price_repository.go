@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+	"time"
+)
+
+var ErrPriceScheduleNotFound = errors.New("jadwal harga tidak ditemukan")
+
+type PriceRepository struct {
+	db *sql.DB
+}
+
+func NewPriceRepository(db *sql.DB) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+func (repo *PriceRepository) GetHistory(productID int) ([]models.PriceHistory, error) {
+	query := `
+		SELECT id, product_id, old_price, new_price, source, schedule_id, changed_by, changed_at
+		FROM price_history
+		WHERE product_id = $1
+		ORDER BY changed_at DESC, id DESC
+	`
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]models.PriceHistory, 0)
+	for rows.Next() {
+		var h models.PriceHistory
+		err := rows.Scan(&h.ID, &h.ProductID, &h.OldPrice, &h.NewPrice, &h.Source, &h.ScheduleID, &h.ChangedBy, &h.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
+
+// GetSchedules mengembalikan jadwal harga, bisa difilter per produk
+// (productID 0 berarti semua produk) dan status.
+func (repo *PriceRepository) GetSchedules(productID int, status string) ([]models.PriceSchedule, error) {
+	query := `
+		SELECT ps.id, ps.product_id, p.name, ps.new_price, ps.effective_at, ps.status, ps.created_by, ps.created_at, ps.applied_at
+		FROM price_schedules ps
+		JOIN products p ON ps.product_id = p.id
+		WHERE ($1 = 0 OR ps.product_id = $1) AND ($2 = '' OR ps.status = $2)
+		ORDER BY ps.effective_at, ps.id
+	`
+	rows, err := repo.db.Query(query, productID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make([]models.PriceSchedule, 0)
+	for rows.Next() {
+		var s models.PriceSchedule
+		err := rows.Scan(&s.ID, &s.ProductID, &s.ProductName, &s.NewPrice, &s.EffectiveAt, &s.Status, &s.CreatedBy, &s.CreatedAt, &s.AppliedAt)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (repo *PriceRepository) CreateSchedule(schedule *models.PriceSchedule) error {
+	err := repo.db.QueryRow("SELECT name FROM products WHERE id = $1", schedule.ProductID).Scan(&schedule.ProductName)
+	if err == sql.ErrNoRows {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO price_schedules (product_id, new_price, effective_at, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at
+	`
+	return repo.db.QueryRow(query, schedule.ProductID, schedule.NewPrice, schedule.EffectiveAt, schedule.CreatedBy).
+		Scan(&schedule.ID, &schedule.Status, &schedule.CreatedAt)
+}
+
+// CancelSchedule membatalkan jadwal yang belum diterapkan.
+func (repo *PriceRepository) CancelSchedule(productID, scheduleID int) error {
+	var status string
+	err := repo.db.QueryRow("SELECT status FROM price_schedules WHERE id = $1 AND product_id = $2", scheduleID, productID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrPriceScheduleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := repo.db.Exec("UPDATE price_schedules SET status = $1 WHERE id = $2 AND status = $3", models.PriceScheduleCancelled, scheduleID, models.PriceSchedulePending)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("jadwal harga dengan status " + status + " tidak bisa dibatalkan")
+	}
+	return nil
+}
+
+// ApplyDue menerapkan semua jadwal pending yang effective_at-nya sudah lewat,
+// berurutan dari yang paling awal. FOR UPDATE SKIP LOCKED mencegah jadwal
+// yang sama diterapkan dua kali jika ada lebih dari satu instance server.
+func (repo *PriceRepository) ApplyDue(now time.Time) ([]models.PriceSchedule, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, product_id, new_price, effective_at, created_by
+		FROM price_schedules
+		WHERE status = $1 AND effective_at <= $2
+		ORDER BY effective_at, id
+		FOR UPDATE SKIP LOCKED
+	`, models.PriceSchedulePending, now)
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]models.PriceSchedule, 0)
+	for rows.Next() {
+		var s models.PriceSchedule
+		if err := rows.Scan(&s.ID, &s.ProductID, &s.NewPrice, &s.EffectiveAt, &s.CreatedBy); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range due {
+		s := &due[i]
+
+		var oldPrice int
+		err := tx.QueryRow("SELECT name, price FROM products WHERE id = $1 FOR UPDATE", s.ProductID).Scan(&s.ProductName, &oldPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec("UPDATE products SET price = $1, updated_at = $2 WHERE id = $3", s.NewPrice, now, s.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = recordPriceChange(tx, &models.PriceHistory{
+			ProductID:  s.ProductID,
+			OldPrice:   &oldPrice,
+			NewPrice:   s.NewPrice,
+			Source:     models.PriceChangeScheduled,
+			ScheduleID: intPtr(s.ID),
+			ChangedBy:  s.CreatedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.QueryRow("UPDATE price_schedules SET status = $1, applied_at = $2 WHERE id = $3 RETURNING applied_at, status", models.PriceScheduleApplied, now, s.ID).
+			Scan(&s.AppliedAt, &s.Status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// recordPriceChange mencatat perubahan harga ke price_history di dalam
+// transaksi yang sama dengan UPDATE products.price.
+func recordPriceChange(tx *sql.Tx, h *models.PriceHistory) error {
+	query := `
+		INSERT INTO price_history (product_id, old_price, new_price, source, schedule_id, changed_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, changed_at
+	`
+	return tx.QueryRow(query, h.ProductID, h.OldPrice, h.NewPrice, h.Source, h.ScheduleID, h.ChangedBy).
+		Scan(&h.ID, &h.ChangedAt)
+}
+
+// recordCostChange mencatat perubahan cost_price produk ke riwayat HPP.
+// oldCost kosong untuk produk baru.
+func recordCostChange(tx *sql.Tx, productID int, oldCost *int, newCost int) error {
+	_, err := tx.Exec("INSERT INTO cost_history (product_id, old_cost, new_cost) VALUES ($1, $2, $3)", productID, oldCost, newCost)
+	return err
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+)
+
+type ProductImageRepository struct {
+	db *sql.DB
+}
+
+func NewProductImageRepository(db *sql.DB) *ProductImageRepository {
+	return &ProductImageRepository{db: db}
+}
+
+func (repo *ProductImageRepository) Create(image *models.ProductImage) error {
+	query := `
+		INSERT INTO product_images (product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7,
+			(SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1))
+		RETURNING id, position, created_at
+	`
+	return repo.db.QueryRow(query, image.ProductID, image.StorageKey, image.ThumbnailKey, image.ContentType, image.SizeBytes, image.Width, image.Height).
+		Scan(&image.ID, &image.Position, &image.CreatedAt)
+}
+
+// GetByProductIDs mengambil gambar untuk banyak produk sekaligus supaya
+// listing produk tidak perlu satu query per produk.
+func (repo *ProductImageRepository) GetByProductIDs(productIDs []int) (map[int][]models.ProductImage, error) {
+	result := make(map[int][]models.ProductImage)
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, position, created_at
+		FROM product_images
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position, id
+	`
+	rows, err := repo.db.Query(query, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img models.ProductImage
+		err := rows.Scan(&img.ID, &img.ProductID, &img.StorageKey, &img.ThumbnailKey, &img.ContentType, &img.SizeBytes, &img.Width, &img.Height, &img.Position, &img.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		result[img.ProductID] = append(result[img.ProductID], img)
+	}
+
+	return result, rows.Err()
+}
+
+func (repo *ProductImageRepository) GetByID(productID, imageID int) (*models.ProductImage, error) {
+	query := `
+		SELECT id, product_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, position, created_at
+		FROM product_images
+		WHERE id = $1 AND product_id = $2
+	`
+	var img models.ProductImage
+	err := repo.db.QueryRow(query, imageID, productID).Scan(&img.ID, &img.ProductID, &img.StorageKey, &img.ThumbnailKey, &img.ContentType, &img.SizeBytes, &img.Width, &img.Height, &img.Position, &img.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("gambar tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &img, nil
+}
+
+func (repo *ProductImageRepository) Delete(id int) error {
+	_, err := repo.db.Exec("DELETE FROM product_images WHERE id = $1", id)
+	return err
+}
@@ -3,10 +3,35 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"kasir-api/models"
-	"time"
+	"strings"
+	"unicode"
 )
 
+var ErrProductNotFound = errors.New("produk tidak ditemukan")
+
+// productColumns adalah kolom standar untuk membaca produk (dengan alias p
+// untuk products dan c untuk categories). Urutannya harus sama dengan
+// scanProduct. Stok bundle dihitung dari stok komponennya.
+const productColumns = `p.id, p.name, p.sku, p.barcode, p.price, p.cost_price,
+		CASE WHEN p.is_bundle THEN COALESCE((
+			SELECT FLOOR(MIN(cp.stock / bc.quantity))
+			FROM bundle_components bc
+			JOIN products cp ON cp.id = bc.component_id
+			WHERE bc.bundle_id = p.id
+		), 0) ELSE p.stock END AS stock, p.unit,
+		p.category_id, c.name AS category_name, p.is_bundle, p.track_lots, p.created_at, p.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner, p *models.Product, extra ...any) error {
+	dest := []any{&p.ID, &p.Name, &p.SKU, &p.Barcode, &p.Price, &p.CostPrice, &p.Stock, &p.Unit, &p.CategoryID, &p.CategoryName, &p.IsBundle, &p.TrackLots, &p.CreatedAt, &p.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
 type ProductRepository struct {
 	db *sql.DB
 }
@@ -15,17 +40,27 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (repo *ProductRepository) GetAll(nameFilter string) ([]models.Product, error) {
+// GetAll mengembalikan produk, bisa difilter nama dan kategori. Filter
+// kategori mencakup seluruh sub-kategorinya.
+func (repo *ProductRepository) GetAll(nameFilter string, categoryID int) ([]models.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.price, p.stock, p.category_id, c.name as category_name, p.created_at, p.updated_at
+		SELECT ` + productColumns + `
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
 	`
 
 	args := []interface{}{}
+	conditions := []string{}
 	if nameFilter != "" {
-		query += " WHERE p.name ILIKE $1"
 		args = append(args, "%"+nameFilter+"%")
+		conditions = append(conditions, fmt.Sprintf("p.name ILIKE $%d", len(args)))
+	}
+	if categoryID != 0 {
+		args = append(args, categoryID)
+		conditions = append(conditions, "p.category_id IN "+categorySubtreeSQL(fmt.Sprintf("$%d", len(args))))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	query += " ORDER BY p.created_at DESC"
@@ -39,65 +74,349 @@ func (repo *ProductRepository) GetAll(nameFilter string) ([]models.Product, erro
 	products := make([]models.Product, 0)
 	for rows.Next() {
 		var p models.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName, &p.CreatedAt, &p.UpdatedAt)
+		err := scanProduct(rows, &p)
 		if err != nil {
 			return nil, err
 		}
 		products = append(products, p)
 	}
 
+	if err := repo.attachDetails(products); err != nil {
+		return nil, err
+	}
+
 	return products, nil
 }
 
-func (repo *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock, category_id, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
-	now := time.Now()
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID, now).Scan(&product.ID)
-	if err == nil {
-		product.CreatedAt = now
+// Search mencari produk berdasarkan nama, SKU, barcode dan nama kategori.
+// Hasil diurutkan berdasarkan relevansi: barcode/SKU yang sama persis paling
+// atas, lalu prefix match (untuk pencarian sambil mengetik) dan kemiripan
+// trigram supaya salah ketik seperti "indomi" tetap menemukan "Indomie".
+func (repo *ProductRepository) Search(term string, limit int) ([]models.ProductSearchResult, error) {
+	prefixQuery := prefixTsQuery(term)
+
+	// Kandidat dikumpulkan lewat UNION supaya tiap cabang bisa memakai
+	// index-nya sendiri. OR dengan kolom dari LEFT JOIN categories memaksa
+	// planner memindai seluruh tabel products.
+	query := `
+		WITH matches AS (
+			SELECT id FROM products WHERE barcode = $1
+			UNION
+			SELECT id FROM products WHERE lower(sku) = lower($1)
+			UNION
+			SELECT id FROM products WHERE sku ILIKE $4 OR barcode LIKE $4
+			UNION
+			SELECT id FROM products WHERE $2 <> '' AND to_tsvector('simple', name) @@ to_tsquery('simple', $2)
+			UNION
+			SELECT id FROM products WHERE $1 <% name
+			UNION
+			SELECT id FROM products WHERE category_id IN (SELECT id FROM categories WHERE $1 <% name)
+		)
+		SELECT ` + productColumns + `,
+			(
+				CASE WHEN p.barcode = $1 OR lower(p.sku) = lower($1) THEN 10 ELSE 0 END
+				+ CASE WHEN $2 <> '' AND to_tsvector('simple', p.name) @@ to_tsquery('simple', $2)
+					THEN 2 + ts_rank(to_tsvector('simple', p.name), to_tsquery('simple', $2)) ELSE 0 END
+				+ 2 * word_similarity($1, p.name)
+				+ CASE WHEN p.sku ILIKE $4 OR p.barcode LIKE $4 THEN 1.5 ELSE 0 END
+				+ COALESCE(word_similarity($1, c.name), 0)
+			)::float8 AS score
+		FROM matches m
+		JOIN products p ON p.id = m.id
+		LEFT JOIN categories c ON p.category_id = c.id
+		ORDER BY score DESC, p.name
+		LIMIT $3
+	`
+
+	rows, err := repo.db.Query(query, term, prefixQuery, limit, escapeLike(term)+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]models.ProductSearchResult, 0)
+	for rows.Next() {
+		var r models.ProductSearchResult
+		err := scanProduct(rows, &r.Product, &r.Score)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	products := make([]models.Product, len(results))
+	for i := range results {
+		products[i] = results[i].Product
+	}
+	if err := repo.attachDetails(products); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Components = products[i].Components
+		results[i].Units = products[i].Units
+		results[i].PriceTiers = products[i].PriceTiers
+		results[i].CategoryPath = products[i].CategoryPath
+	}
+
+	return results, nil
+}
+
+// escapeLike meng-escape karakter wildcard LIKE supaya input user dicocokkan apa adanya.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// prefixTsQuery mengubah input bebas menjadi tsquery prefix, misalnya
+// "kopi sus" menjadi "kopi:* & sus:*". Karakter selain huruf dan angka
+// dibuang supaya input user tidak bisa merusak sintaks tsquery.
+func prefixTsQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	parts := make([]string, 0, len(words))
+	for _, w := range words {
+		parts = append(parts, w+":*")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Create menyimpan produk baru. changedBy dicatat di riwayat harga sebagai
+// pengisi harga awal.
+func (repo *ProductRepository) Create(product *models.Product, changedBy *string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO products (name, sku, barcode, price, cost_price, stock, unit, category_id, is_bundle, track_lots, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP) RETURNING id, created_at"
+	err = tx.QueryRow(query, product.Name, product.SKU, product.Barcode, product.Price, product.CostPrice, product.Stock, product.Unit, product.CategoryID, product.IsBundle, product.TrackLots).
+		Scan(&product.ID, &product.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if product.TrackLots && product.Stock > 0 {
+		if _, err := addToLot(tx, product.ID, openingLotNumber, nil, product.Stock, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := replaceBundleComponents(tx, product); err != nil {
+		return err
+	}
+
+	if err := replaceProductUnits(tx, product); err != nil {
+		return err
+	}
+
+	if err := replacePriceTiers(tx, product); err != nil {
+		return err
+	}
+
+	err = recordPriceChange(tx, &models.PriceHistory{
+		ProductID: product.ID,
+		NewPrice:  product.Price,
+		Source:    models.PriceChangeManual,
+		ChangedBy: changedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := recordCostChange(tx, product.ID, nil, product.CostPrice); err != nil {
+		return err
+	}
+
+	// Stok awal dicatat sebagai adjustment supaya ledger bisa merekonstruksi stok
+	if product.Stock != 0 {
+		err = recordStockMovement(tx, &models.StockMovement{
+			ProductID:  product.ID,
+			Quantity:   product.Stock,
+			Type:       models.StockMovementAdjustment,
+			StockAfter: product.Stock,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByCategory mengembalikan satu halaman produk dalam kategori, diurutkan
+// berdasarkan nama, beserta jumlah total produknya. Jika includeSub true,
+// produk di sub-kategori ikut dihitung.
+func (repo *ProductRepository) GetByCategory(categoryID int, includeSub bool, limit, offset int) ([]models.Product, int, error) {
+	condition := "p.category_id = $1"
+	if includeSub {
+		condition = "p.category_id IN " + categorySubtreeSQL("$1")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM products p WHERE "+condition, categoryID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
 	}
-	return err
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE ` + condition + `
+		ORDER BY p.name, p.id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := repo.db.Query(query, categoryID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	for rows.Next() {
+		var p models.Product
+		if err := scanProduct(rows, &p); err != nil {
+			return nil, 0, err
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := repo.attachDetails(products); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
 }
 
 func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.price, p.stock, p.category_id, c.name as category_name, p.created_at, p.updated_at
+		SELECT ` + productColumns + `
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
 		WHERE p.id = $1
 	`
 
 	var p models.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName, &p.CreatedAt, &p.UpdatedAt)
+	err := scanProduct(repo.db.QueryRow(query, id), &p)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("produk tidak ditemukan")
+		return nil, ErrProductNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &p, nil
+	products := []models.Product{p}
+	if err := repo.attachDetails(products); err != nil {
+		return nil, err
+	}
+
+	return &products[0], nil
 }
 
-func (repo *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4, updated_at = $5 WHERE id = $6"
-	now := time.Now()
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, now, product.ID)
+// Update mengubah produk. Perubahan harga dicatat di riwayat harga atas nama
+// changedBy.
+func (repo *ProductRepository) Update(product *models.Product, changedBy *string) error {
+	tx, err := repo.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	var currentStock models.Quantity
+	var currentPrice, currentCost int
+	var currentTrackLots bool
+	err = tx.QueryRow("SELECT stock, price, cost_price, track_lots FROM products WHERE id = $1 FOR UPDATE", product.ID).Scan(&currentStock, &currentPrice, &currentCost, &currentTrackLots)
+	if err == sql.ErrNoRows {
+		return ErrProductNotFound
+	}
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+	// Stok produk dengan lot harus sama dengan total lot, jadi tidak boleh
+	// diubah langsung. Saat pelacakan lot baru diaktifkan, stok yang ada
+	// dimasukkan ke lot pembuka.
+	if product.TrackLots && product.Stock != currentStock {
+		return errors.New("stok produk dengan track_lots hanya bisa diubah lewat penerimaan lot atau penjualan")
+	}
+	if product.TrackLots && !currentTrackLots && currentStock > 0 {
+		if _, err := addToLot(tx, product.ID, openingLotNumber, nil, currentStock, nil); err != nil {
+			return err
+		}
 	}
 
-	product.UpdatedAt = &now
-	return nil
+	// Produk yang dipakai sebagai komponen tidak boleh diubah menjadi bundle
+	if product.IsBundle {
+		var usedAsComponent bool
+		err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM bundle_components WHERE component_id = $1)", product.ID).Scan(&usedAsComponent)
+		if err != nil {
+			return err
+		}
+		if usedAsComponent {
+			return errors.New("produk ini dipakai sebagai komponen bundle lain sehingga tidak bisa menjadi bundle")
+		}
+	}
+
+	query := "UPDATE products SET name = $1, sku = $2, barcode = $3, price = $4, cost_price = $5, stock = $6, unit = $7, category_id = $8, is_bundle = $9, track_lots = $10, updated_at = CURRENT_TIMESTAMP WHERE id = $11 RETURNING created_at, updated_at"
+	err = tx.QueryRow(query, product.Name, product.SKU, product.Barcode, product.Price, product.CostPrice, product.Stock, product.Unit, product.CategoryID, product.IsBundle, product.TrackLots, product.ID).
+		Scan(&product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceBundleComponents(tx, product); err != nil {
+		return err
+	}
+
+	if err := replaceProductUnits(tx, product); err != nil {
+		return err
+	}
+
+	if err := replacePriceTiers(tx, product); err != nil {
+		return err
+	}
+
+	if product.Price != currentPrice {
+		err = recordPriceChange(tx, &models.PriceHistory{
+			ProductID: product.ID,
+			OldPrice:  &currentPrice,
+			NewPrice:  product.Price,
+			Source:    models.PriceChangeManual,
+			ChangedBy: changedBy,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if product.CostPrice != currentCost {
+		if err := recordCostChange(tx, product.ID, &currentCost, product.CostPrice); err != nil {
+			return err
+		}
+	}
+
+	// Perubahan stok manual dicatat sebagai adjustment
+	if diff := product.Stock - currentStock; diff != 0 {
+		err = recordStockMovement(tx, &models.StockMovement{
+			ProductID:  product.ID,
+			Quantity:   diff,
+			Type:       models.StockMovementAdjustment,
+			StockAfter: product.Stock,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (repo *ProductRepository) Delete(id int) error {
@@ -112,8 +431,265 @@ func (repo *ProductRepository) Delete(id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+		return ErrProductNotFound
+	}
+
+	return nil
+}
+
+// replaceBundleComponents menyimpan ulang daftar komponen bundle. Untuk
+// produk biasa semua komponen dihapus.
+func replaceBundleComponents(tx *sql.Tx, product *models.Product) error {
+	_, err := tx.Exec("DELETE FROM bundle_components WHERE bundle_id = $1", product.ID)
+	if err != nil {
+		return err
+	}
+
+	if !product.IsBundle {
+		product.Components = nil
+		return nil
+	}
+
+	for i := range product.Components {
+		c := &product.Components[i]
+		if c.ComponentID == product.ID {
+			return errors.New("bundle tidak boleh berisi dirinya sendiri")
+		}
+
+		var isBundle bool
+		err := tx.QueryRow("SELECT name, is_bundle FROM products WHERE id = $1", c.ComponentID).Scan(&c.ComponentName, &isBundle)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("komponen product id %d not found", c.ComponentID)
+		}
+		if err != nil {
+			return err
+		}
+		if isBundle {
+			return fmt.Errorf("komponen %s adalah bundle, bundle bertingkat tidak didukung", c.ComponentName)
+		}
+
+		_, err = tx.Exec("INSERT INTO bundle_components (bundle_id, component_id, quantity) VALUES ($1, $2, $3)", product.ID, c.ComponentID, c.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachComponents mengisi Components untuk produk bundle dengan satu query.
+func (repo *ProductRepository) attachComponents(products []models.Product) error {
+	ids := make([]int, 0)
+	for _, p := range products {
+		if p.IsBundle {
+			ids = append(ids, p.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
 	}
 
+	query := `
+		SELECT bc.bundle_id, bc.component_id, p.name, bc.quantity
+		FROM bundle_components bc
+		JOIN products p ON p.id = bc.component_id
+		WHERE bc.bundle_id = ANY($1)
+		ORDER BY bc.bundle_id, p.name
+	`
+	rows, err := repo.db.Query(query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.BundleComponent)
+	for rows.Next() {
+		var bundleID int
+		var c models.BundleComponent
+		if err := rows.Scan(&bundleID, &c.ComponentID, &c.ComponentName, &c.Quantity); err != nil {
+			return err
+		}
+		components[bundleID] = append(components[bundleID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range products {
+		if products[i].IsBundle {
+			products[i].Components = components[products[i].ID]
+		}
+	}
+	return nil
+}
+
+// attachDetails mengisi data turunan produk (komponen bundle, unit, tier
+// harga dan breadcrumb kategori), masing-masing dengan satu query.
+func (repo *ProductRepository) attachDetails(products []models.Product) error {
+	if err := repo.attachComponents(products); err != nil {
+		return err
+	}
+	if err := repo.attachUnits(products); err != nil {
+		return err
+	}
+	if err := repo.attachPriceTiers(products); err != nil {
+		return err
+	}
+	return repo.attachCategoryPaths(products)
+}
+
+// attachCategoryPaths mengisi CategoryPath untuk produk yang punya kategori.
+func (repo *ProductRepository) attachCategoryPaths(products []models.Product) error {
+	ids := make([]int, 0)
+	for _, p := range products {
+		if p.CategoryID != nil {
+			ids = append(ids, *p.CategoryID)
+		}
+	}
+
+	paths, err := getCategoryPaths(repo.db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		if products[i].CategoryID != nil {
+			products[i].CategoryPath = paths[*products[i].CategoryID]
+		}
+	}
+	return nil
+}
+
+// replaceProductUnits menyimpan ulang daftar unit jual alternatif produk.
+func replaceProductUnits(tx *sql.Tx, product *models.Product) error {
+	_, err := tx.Exec("DELETE FROM product_units WHERE product_id = $1", product.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range product.Units {
+		u := &product.Units[i]
+		err := tx.QueryRow(
+			"INSERT INTO product_units (product_id, name, factor, price) VALUES ($1, $2, $3, $4) RETURNING id",
+			product.ID, u.Name, u.Factor, u.Price,
+		).Scan(&u.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachUnits mengisi Units untuk daftar produk dengan satu query.
+func (repo *ProductRepository) attachUnits(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT id, product_id, name, factor, price
+		FROM product_units
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, factor
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	units := make(map[int][]models.ProductUnit)
+	for rows.Next() {
+		var productID int
+		var u models.ProductUnit
+		if err := rows.Scan(&u.ID, &productID, &u.Name, &u.Factor, &u.Price); err != nil {
+			return err
+		}
+		units[productID] = append(units[productID], u)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Units = units[products[i].ID]
+	}
+	return nil
+}
+
+// replacePriceTiers menyimpan ulang daftar harga bertingkat produk.
+func replacePriceTiers(tx *sql.Tx, product *models.Product) error {
+	_, err := tx.Exec("DELETE FROM product_price_tiers WHERE product_id = $1", product.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range product.PriceTiers {
+		t := &product.PriceTiers[i]
+		if t.CustomerGroupID != nil {
+			var exists bool
+			err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM customer_groups WHERE id = $1)", *t.CustomerGroupID).Scan(&exists)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("customer group id %d not found", *t.CustomerGroupID)
+			}
+		}
+
+		err := tx.QueryRow(
+			"INSERT INTO product_price_tiers (product_id, customer_group_id, min_quantity, price) VALUES ($1, $2, $3, $4) RETURNING id",
+			product.ID, t.CustomerGroupID, t.MinQuantity, t.Price,
+		).Scan(&t.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachPriceTiers mengisi PriceTiers untuk daftar produk dengan satu query.
+func (repo *ProductRepository) attachPriceTiers(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT id, product_id, customer_group_id, min_quantity, price
+		FROM product_price_tiers
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, customer_group_id NULLS FIRST, min_quantity
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tiers := make(map[int][]models.PriceTier)
+	for rows.Next() {
+		var productID int
+		var t models.PriceTier
+		if err := rows.Scan(&t.ID, &productID, &t.CustomerGroupID, &t.MinQuantity, &t.Price); err != nil {
+			return err
+		}
+		tiers[productID] = append(tiers[productID], t)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].PriceTiers = tiers[products[i].ID]
+	}
 	return nil
 }
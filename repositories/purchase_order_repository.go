@@ -0,0 +1,386 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+func (repo *PurchaseOrderRepository) GetAll(status string, supplierID int) ([]models.PurchaseOrder, error) {
+	query := `
+		SELECT po.id, po.supplier_id, s.name, po.status, po.notes, po.total_cost, po.created_at, po.updated_at, po.sent_at, po.received_at
+		FROM purchase_orders po
+		JOIN suppliers s ON po.supplier_id = s.id
+		WHERE ($1 = '' OR po.status = $1) AND ($2 = 0 OR po.supplier_id = $2)
+		ORDER BY po.created_at DESC
+	`
+	rows, err := repo.db.Query(query, status, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		var po models.PurchaseOrder
+		err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Notes, &po.TotalCost, &po.CreatedAt, &po.UpdatedAt, &po.SentAt, &po.ReceivedAt)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+
+	return orders, nil
+}
+
+func (repo *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	query := `
+		SELECT po.id, po.supplier_id, s.name, po.status, po.notes, po.total_cost, po.created_at, po.updated_at, po.sent_at, po.received_at
+		FROM purchase_orders po
+		JOIN suppliers s ON po.supplier_id = s.id
+		WHERE po.id = $1
+	`
+	var po models.PurchaseOrder
+	err := repo.db.QueryRow(query, id).Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Notes, &po.TotalCost, &po.CreatedAt, &po.UpdatedAt, &po.SentAt, &po.ReceivedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("purchase order tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	itemsQuery := `
+		SELECT poi.id, poi.purchase_order_id, poi.product_id, p.name, poi.quantity, poi.received_quantity, poi.unit_cost
+		FROM purchase_order_items poi
+		JOIN products p ON poi.product_id = p.id
+		WHERE poi.purchase_order_id = $1
+		ORDER BY poi.id
+	`
+	rows, err := repo.db.Query(itemsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	po.Items = make([]models.PurchaseOrderItem, 0)
+	for rows.Next() {
+		var item models.PurchaseOrderItem
+		err := rows.Scan(&item.ID, &item.PurchaseOrderID, &item.ProductID, &item.ProductName, &item.Quantity, &item.ReceivedQuantity, &item.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		po.Items = append(po.Items, item)
+	}
+
+	return &po, nil
+}
+
+func (repo *PurchaseOrderRepository) Create(po *models.PurchaseOrder) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkSupplierExists(tx, po.SupplierID); err != nil {
+		return err
+	}
+
+	po.Status = models.PurchaseOrderDraft
+	po.TotalCost, err = purchaseOrderTotal(po.Items)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO purchase_orders (supplier_id, status, notes, total_cost, created_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, created_at"
+	err = tx.QueryRow(query, po.SupplierID, po.Status, po.Notes, po.TotalCost).Scan(&po.ID, &po.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertPurchaseOrderItems(tx, po); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update mengganti supplier, catatan dan item purchase order. Hanya PO
+// berstatus draft yang boleh diubah.
+func (repo *PurchaseOrderRepository) Update(po *models.PurchaseOrder) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, po.ID)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderDraft {
+		return fmt.Errorf("purchase order berstatus %s tidak dapat diubah", status)
+	}
+
+	if err := checkSupplierExists(tx, po.SupplierID); err != nil {
+		return err
+	}
+
+	po.Status = status
+	po.TotalCost, err = purchaseOrderTotal(po.Items)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE purchase_orders SET supplier_id = $1, notes = $2, total_cost = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4 RETURNING created_at, updated_at"
+	err = tx.QueryRow(query, po.SupplierID, po.Notes, po.TotalCost, po.ID).Scan(&po.CreatedAt, &po.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM purchase_order_items WHERE purchase_order_id = $1", po.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertPurchaseOrderItems(tx, po); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *PurchaseOrderRepository) Send(id int) error {
+	return repo.transition(id, models.PurchaseOrderSent, "sent_at", models.PurchaseOrderDraft)
+}
+
+func (repo *PurchaseOrderRepository) Cancel(id int) error {
+	return repo.transition(id, models.PurchaseOrderCancelled, "", models.PurchaseOrderDraft, models.PurchaseOrderSent)
+}
+
+func (repo *PurchaseOrderRepository) transition(id int, to, timestampColumn string, from ...string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	for _, f := range from {
+		if status == f {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("purchase order berstatus %s tidak dapat diubah menjadi %s", status, to)
+	}
+
+	query := "UPDATE purchase_orders SET status = $1, updated_at = $2 WHERE id = $3"
+	if timestampColumn != "" {
+		query = "UPDATE purchase_orders SET status = $1, updated_at = $2, " + timestampColumn + " = $2 WHERE id = $3"
+	}
+	_, err = tx.Exec(query, to, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive mencatat penerimaan barang untuk PO yang sudah dikirim. Stok produk
+// bertambah lewat ledger stock_movements dan cost_price diperbarui dengan
+// rata-rata tertimbang antara stok lama dan barang yang baru diterima.
+func (repo *PurchaseOrderRepository) Receive(id int, items []models.ReceiveItem) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderSent && status != models.PurchaseOrderPartiallyReceived {
+		return fmt.Errorf("purchase order berstatus %s tidak dapat diterima", status)
+	}
+
+	for _, item := range items {
+		var productID, orderedCost int
+		var ordered, received models.Quantity
+		err := tx.QueryRow(
+			"SELECT product_id, quantity, received_quantity, unit_cost FROM purchase_order_items WHERE id = $1 AND purchase_order_id = $2 FOR UPDATE",
+			item.ItemID, id,
+		).Scan(&productID, &ordered, &received, &orderedCost)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item id %d tidak ada di purchase order ini", item.ItemID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if received+item.Quantity > ordered {
+			return fmt.Errorf("penerimaan item id %d melebihi jumlah pesanan (dipesan: %s, sudah diterima: %s, diterima: %s)", item.ItemID, ordered, received, item.Quantity)
+		}
+
+		unitCost := orderedCost
+		if item.UnitCost != nil {
+			unitCost = *item.UnitCost
+		}
+
+		var stock models.Quantity
+		var costPrice int
+		var trackLots, isBundle bool
+		err = tx.QueryRow("SELECT stock, cost_price, track_lots, is_bundle FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&stock, &costPrice, &trackLots, &isBundle)
+		if err != nil {
+			return err
+		}
+		// Produk bisa saja diubah menjadi bundle setelah PO dibuat
+		if isBundle {
+			return fmt.Errorf("item id %d adalah bundle dan tidak bisa diterima, terima komponennya", item.ItemID)
+		}
+
+		// Produk dengan lot: barang masuk ke lot yang disebutkan, atau lot
+		// otomatis per item PO jika nomor lot tidak diisi
+		if trackLots {
+			lotNumber := item.LotNumber
+			if lotNumber == "" {
+				lotNumber = fmt.Sprintf("PO%d-%d", id, item.ItemID)
+			}
+			if _, err := addToLot(tx, productID, lotNumber, item.ExpiryDate, item.Quantity, &id); err != nil {
+				return err
+			}
+		}
+
+		newCost, err := weightedCost(stock, costPrice, item.Quantity, unitCost)
+		if err != nil {
+			return err
+		}
+		newStock := stock + item.Quantity
+
+		_, err = tx.Exec("UPDATE products SET stock = $1, cost_price = $2, updated_at = $3 WHERE id = $4", newStock, newCost, time.Now(), productID)
+		if err != nil {
+			return err
+		}
+		if newCost != costPrice {
+			if err := recordCostChange(tx, productID, &costPrice, newCost); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("UPDATE purchase_order_items SET received_quantity = received_quantity + $1 WHERE id = $2", item.Quantity, item.ItemID)
+		if err != nil {
+			return err
+		}
+
+		err = recordStockMovement(tx, &models.StockMovement{
+			ProductID:     productID,
+			Quantity:      item.Quantity,
+			Type:          models.StockMovementPurchase,
+			ReferenceType: stringPtr("purchase_order"),
+			ReferenceID:   intPtr(id),
+			UnitCost:      intPtr(unitCost),
+			StockAfter:    newStock,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var outstanding int
+	err = tx.QueryRow("SELECT COUNT(*) FROM purchase_order_items WHERE purchase_order_id = $1 AND received_quantity < quantity", id).Scan(&outstanding)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if outstanding == 0 {
+		_, err = tx.Exec("UPDATE purchase_orders SET status = $1, updated_at = $2, received_at = $2 WHERE id = $3", models.PurchaseOrderReceived, now, id)
+	} else {
+		_, err = tx.Exec("UPDATE purchase_orders SET status = $1, updated_at = $2 WHERE id = $3", models.PurchaseOrderPartiallyReceived, now, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockPurchaseOrder(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New("purchase order tidak ditemukan")
+	}
+	return status, err
+}
+
+func checkSupplierExists(tx *sql.Tx, supplierID int) error {
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)", supplierID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("supplier id %d not found", supplierID)
+	}
+	return nil
+}
+
+func insertPurchaseOrderItems(tx *sql.Tx, po *models.PurchaseOrder) error {
+	for i := range po.Items {
+		item := &po.Items[i]
+		item.PurchaseOrderID = po.ID
+		item.ReceivedQuantity = 0
+
+		var isBundle bool
+		err := tx.QueryRow("SELECT name, is_bundle FROM products WHERE id = $1", item.ProductID).Scan(&item.ProductName, &isBundle)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+		// Bundle tidak punya stok sendiri, yang dibeli dari supplier adalah komponennya
+		if isBundle {
+			return fmt.Errorf("%s adalah bundle dan tidak bisa dipesan lewat purchase order, pesan komponennya", item.ProductName)
+		}
+
+		err = tx.QueryRow(
+			"INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity, unit_cost) VALUES ($1, $2, $3, $4) RETURNING id",
+			po.ID, item.ProductID, item.Quantity, item.UnitCost,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func purchaseOrderTotal(items []models.PurchaseOrderItem) (int, error) {
+	total := 0
+	for _, item := range items {
+		cost, err := item.Quantity.MulPrice(item.UnitCost)
+		if err != nil {
+			return 0, fmt.Errorf("total biaya product id %d terlalu besar", item.ProductID)
+		}
+		total += cost
+	}
+	return total, nil
+}
@@ -0,0 +1,559 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+// ReportRepository berisi query laporan analitik penjualan dan stok.
+type ReportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// rollupDates mengubah rentang [start, end) menjadi tanggal untuk tabel
+// rollup harian. start dan end harus tengah malam di zona waktu toko (batas
+// hari dari ReportService), sehingga rentangnya selalu hari utuh.
+func rollupDates(start, end time.Time) (string, string) {
+	return start.Format("2006-01-02"), end.Format("2006-01-02")
+}
+
+// GetSalesBuckets mengelompokkan transaksi pada [start, end) per bucket
+// granularity (hour, day, week atau month) menurut jam dinding zona waktu
+// timezone. Hanya bucket yang punya transaksi yang dikembalikan. Bucket per
+// jam dibaca dari tabel transaksi dan Start-nya waktu absolut awal jam lokal,
+// sehingga jam yang terulang saat DST berakhir tidak tergabung. Bucket
+// harian ke atas dibaca dari rollup harian dan Start-nya tanggal lokal (tanpa
+// zona).
+func (repo *ReportRepository) GetSalesBuckets(start, end time.Time, granularity, timezone string) ([]models.SalesBucket, error) {
+	var rows *sql.Rows
+	var err error
+	if granularity == models.GranularityHour {
+		rows, err = repo.db.Query(`
+			WITH tx AS (
+				SELECT t.id, t.total_amount,
+					t.created_at - ((t.created_at AT TIME ZONE $3) - date_trunc('hour', t.created_at AT TIME ZONE $3)) AS bucket
+				FROM transactions t
+				WHERE t.created_at >= $1 AND t.created_at < $2
+			)
+			SELECT tx.bucket, COALESCE(SUM(tx.total_amount), 0), COUNT(*), COALESCE(SUM(items.quantity), 0)
+			FROM tx
+			LEFT JOIN LATERAL (
+				SELECT SUM(td.base_quantity) AS quantity
+				FROM transaction_details td
+				WHERE td.transaction_id = tx.id
+			) items ON true
+			GROUP BY tx.bucket
+			ORDER BY tx.bucket
+		`, start, end, timezone)
+	} else {
+		startDate, endDate := rollupDates(start, end)
+		rows, err = repo.db.Query(`
+			SELECT date_trunc($3, d.sales_date::timestamp) AS bucket,
+				SUM(d.revenue), SUM(d.transactions), COALESCE(SUM(items.quantity), 0)
+			FROM daily_sales d
+			LEFT JOIN LATERAL (
+				SELECT SUM(p.quantity) AS quantity
+				FROM daily_product_sales p
+				WHERE p.sales_date = d.sales_date
+			) items ON true
+			WHERE d.sales_date >= $1 AND d.sales_date < $2
+			GROUP BY bucket
+			ORDER BY bucket
+		`, startDate, endDate, granularity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := make([]models.SalesBucket, 0)
+	for rows.Next() {
+		var b models.SalesBucket
+		if err := rows.Scan(&b.Start, &b.Revenue, &b.Transactions, &b.ItemsSold); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
+// productRankMetrics memetakan parameter by ke ekspresi SQL (whitelist,
+// karena ORDER BY tidak bisa memakai parameter).
+var productRankMetrics = map[string]string{
+	models.ProductRankByQty:     "COALESCE(s.quantity, 0)",
+	models.ProductRankByRevenue: "COALESCE(s.revenue, 0)",
+	models.ProductRankByProfit:  "COALESCE(s.revenue, 0) - COALESCE(s.cost, 0)",
+}
+
+// GetProductRanking mengembalikan limit produk dengan metrik by tertinggi
+// (atau terendah jika bottom) pada [start, end). Urutan top hanya berisi
+// produk yang terjual; urutan bottom juga mencakup produk tanpa penjualan
+// sehingga barang yang tidak laku ikut muncul. Nilai yang sama diurutkan
+// berdasarkan nama lalu id supaya hasilnya stabil.
+func (repo *ReportRepository) GetProductRanking(start, end time.Time, categoryID int, by string, bottom bool, limit int) ([]models.ProductSalesRanked, error) {
+	return repo.rankProducts(start, end, categoryID, by, bottom, bottom, &limit)
+}
+
+// GetProductContributions mengembalikan semua produk, termasuk yang tidak
+// terjual, urut metrik by tertinggi pada [start, end).
+func (repo *ReportRepository) GetProductContributions(start, end time.Time, categoryID int, by string) ([]models.ProductSalesRanked, error) {
+	return repo.rankProducts(start, end, categoryID, by, false, true, nil)
+}
+
+// rankProducts mengurutkan produk berdasarkan metrik by dari rollup harian.
+// limit nil berarti tanpa batas.
+func (repo *ReportRepository) rankProducts(start, end time.Time, categoryID int, by string, ascending, includeUnsold bool, limit *int) ([]models.ProductSalesRanked, error) {
+	metric, ok := productRankMetrics[by]
+	if !ok {
+		return nil, fmt.Errorf("metrik %q tidak dikenal", by)
+	}
+	direction := "DESC"
+	if ascending {
+		direction = "ASC"
+	}
+
+	query := `
+		WITH s AS (
+			SELECT product_id, SUM(quantity) AS quantity, SUM(revenue) AS revenue,
+				SUM(cost) AS cost, SUM(transactions) AS transactions
+			FROM daily_product_sales
+			WHERE sales_date >= $1 AND sales_date < $2
+			GROUP BY product_id
+		)
+		SELECT p.id, p.name, p.category_id, c.name,
+			COALESCE(s.quantity, 0), COALESCE(s.revenue, 0), COALESCE(s.cost, 0), COALESCE(s.transactions, 0)
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		LEFT JOIN s ON s.product_id = p.id
+		WHERE ($3 = 0 OR p.category_id IN ` + categorySubtreeSQL("$3") + `)
+			AND ($4 OR s.product_id IS NOT NULL)
+		ORDER BY ` + metric + ` ` + direction + `, p.name, p.id
+		LIMIT $5
+	`
+	startDate, endDate := rollupDates(start, end)
+	rows, err := repo.db.Query(query, startDate, endDate, categoryID, includeUnsold, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ProductSalesRanked, 0)
+	for rows.Next() {
+		var item models.ProductSalesRanked
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName,
+			&item.Quantity, &item.Revenue, &item.Cost, &item.Transactions)
+		if err != nil {
+			return nil, err
+		}
+		item.Profit = item.Revenue - item.Cost
+		item.Rank = len(items) + 1
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// salesDetailFilter adalah kondisi WHERE untuk detail transaksi pada [$1, $2)
+// yang produknya berada di kategori $3 beserta turunannya (0 berarti semua).
+var salesDetailFilter = `t.created_at >= $1 AND t.created_at < $2
+			AND ($3 = 0 OR p.category_id IN ` + categorySubtreeSQL("$3") + `)`
+
+// GetSalesTotals menghitung total revenue, quantity (unit dasar) dan jumlah
+// transaksi pada [start, end) untuk filter kategori categoryID.
+func (repo *ReportRepository) GetSalesTotals(start, end time.Time, categoryID int) (models.SalesBreakdownTotals, error) {
+	return salesTotals(repo.db, start, end, categoryID)
+}
+
+// salesTotals membaca total dari rollup harian. Dengan filter kategori,
+// jumlah transaksi (transaksi yang memuat produk kategori tersebut) tidak bisa
+// dijumlahkan dari rollup per produk, jadi dihitung dari tabel transaksi.
+func salesTotals(db *sql.DB, start, end time.Time, categoryID int) (models.SalesBreakdownTotals, error) {
+	var totals models.SalesBreakdownTotals
+	startDate, endDate := rollupDates(start, end)
+
+	if categoryID == 0 {
+		err := db.QueryRow(`
+			SELECT COALESCE(SUM(revenue), 0), COALESCE(SUM(transactions), 0),
+				(SELECT COALESCE(SUM(quantity), 0) FROM daily_product_sales WHERE sales_date >= $1 AND sales_date < $2)
+			FROM daily_sales
+			WHERE sales_date >= $1 AND sales_date < $2
+		`, startDate, endDate).Scan(&totals.Revenue, &totals.Transactions, &totals.Quantity)
+		return totals, err
+	}
+
+	err := db.QueryRow(`
+		SELECT COALESCE(SUM(d.revenue), 0), COALESCE(SUM(d.quantity), 0)
+		FROM daily_product_sales d
+		JOIN products p ON p.id = d.product_id
+		WHERE d.sales_date >= $1 AND d.sales_date < $2
+			AND p.category_id IN `+categorySubtreeSQL("$3"), startDate, endDate, categoryID).Scan(&totals.Revenue, &totals.Quantity)
+	if err != nil {
+		return totals, err
+	}
+
+	err = db.QueryRow(`
+		SELECT COUNT(DISTINCT td.transaction_id)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products p ON p.id = td.product_id
+		WHERE `+salesDetailFilter, start, end, categoryID).Scan(&totals.Transactions)
+	return totals, err
+}
+
+// GetCategorySales mengelompokkan penjualan pada [start, end) per kategori
+// langsung produk, termasuk produk tanpa kategori, urut revenue terbesar.
+// Jumlah transaksi per kategori tidak bisa dijumlahkan dari rollup per
+// produk, jadi laporan ini dihitung dari tabel transaksi.
+func (repo *ReportRepository) GetCategorySales(start, end time.Time, categoryID int) ([]models.CategorySales, error) {
+	query := `
+		SELECT p.category_id, COALESCE(c.name, $4), SUM(td.subtotal), SUM(td.base_quantity), COUNT(DISTINCT td.transaction_id)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products p ON p.id = td.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE ` + salesDetailFilter + `
+		GROUP BY p.category_id, c.name
+		ORDER BY SUM(td.subtotal) DESC, c.name NULLS LAST, p.category_id
+	`
+	rows, err := repo.db.Query(query, start, end, categoryID, models.UncategorizedName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.CategorySales, 0)
+	for rows.Next() {
+		var item models.CategorySales
+		if err := rows.Scan(&item.CategoryID, &item.CategoryName, &item.Revenue, &item.Quantity, &item.Transactions); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetProductSales mengelompokkan penjualan pada [start, end) per produk dari
+// rollup harian, urut revenue terbesar.
+func (repo *ReportRepository) GetProductSales(start, end time.Time, categoryID int) ([]models.ProductSales, error) {
+	query := `
+		SELECT p.id, p.name, p.category_id, COALESCE(c.name, $4), SUM(d.revenue), SUM(d.quantity), SUM(d.transactions)
+		FROM daily_product_sales d
+		JOIN products p ON p.id = d.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE d.sales_date >= $1 AND d.sales_date < $2
+			AND ($3 = 0 OR p.category_id IN ` + categorySubtreeSQL("$3") + `)
+		GROUP BY p.id, p.name, p.category_id, c.name
+		ORDER BY SUM(d.revenue) DESC, p.name, p.id
+	`
+	startDate, endDate := rollupDates(start, end)
+	rows, err := repo.db.Query(query, startDate, endDate, categoryID, models.UncategorizedName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var item models.ProductSales
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName,
+			&item.Revenue, &item.Quantity, &item.Transactions)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetInventoryValues mengembalikan stok, harga jual dan HPP setiap produk
+// non-bundle yang stoknya tidak nol. Jika asOf diisi, nilainya direkonstruksi
+// untuk saat asOf: stok dari stok sekarang dikurangi mutasi ledger sesudahnya,
+// harga dari riwayat harga dan HPP dari riwayat HPP. Produk yang dibuat
+// sesudah asOf tidak dihitung.
+func (repo *ReportRepository) GetInventoryValues(asOf *time.Time, categoryID int) ([]models.InventoryProductValue, error) {
+	query := `
+		WITH v AS (
+			SELECT p.id, p.name, p.category_id, c.name AS category_name, p.unit,
+				CASE WHEN $1::timestamptz IS NULL THEN p.stock ELSE p.stock - COALESCE((
+					SELECT SUM(m.quantity) FROM stock_movements m
+					WHERE m.product_id = p.id AND m.created_at >= $1
+				), 0) END AS stock,
+				CASE WHEN $1::timestamptz IS NULL THEN p.price ELSE COALESCE(
+					(SELECT h.new_price FROM price_history h WHERE h.product_id = p.id AND h.changed_at < $1
+						ORDER BY h.changed_at DESC, h.id DESC LIMIT 1),
+					(SELECT h.old_price FROM price_history h WHERE h.product_id = p.id AND h.changed_at >= $1
+						ORDER BY h.changed_at, h.id LIMIT 1),
+					p.price) END AS price,
+				CASE WHEN $1::timestamptz IS NULL THEN p.cost_price ELSE COALESCE(
+					(SELECT h.new_cost FROM cost_history h WHERE h.product_id = p.id AND h.changed_at < $1
+						ORDER BY h.changed_at DESC, h.id DESC LIMIT 1),
+					(SELECT h.old_cost FROM cost_history h WHERE h.product_id = p.id AND h.changed_at >= $1
+						ORDER BY h.changed_at, h.id LIMIT 1),
+					p.cost_price) END AS cost_price
+			FROM products p
+			LEFT JOIN categories c ON c.id = p.category_id
+			WHERE NOT p.is_bundle
+				AND ($1::timestamptz IS NULL OR p.created_at IS NULL OR p.created_at < $1)
+				AND ($2 = 0 OR p.category_id IN ` + categorySubtreeSQL("$2") + `)
+		)
+		SELECT id, name, category_id, COALESCE(category_name, $3), unit, stock, price, cost_price,
+			ROUND(stock * price)::int, ROUND(stock * cost_price)::int
+		FROM v
+		WHERE stock <> 0
+		ORDER BY category_name NULLS LAST, name, id
+	`
+	rows, err := repo.db.Query(query, asOf, categoryID, models.UncategorizedName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.InventoryProductValue, 0)
+	for rows.Next() {
+		var item models.InventoryProductValue
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName, &item.Unit,
+			&item.Stock, &item.Price, &item.CostPrice, &item.RetailValue, &item.CostValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetStockSales mengembalikan setiap produk non-bundle yang masih punya stok
+// beserta jumlah yang keluar karena penjualan sejak since dan waktu penjualan
+// terakhirnya. Penjualan dihitung dari ledger stok, jadi komponen yang
+// terjual lewat bundle ikut terhitung. Status dan sell-through diisi oleh
+// service.
+func (repo *ReportRepository) GetStockSales(since time.Time, categoryID int) ([]models.DeadStockItem, error) {
+	query := `
+		SELECT p.id, p.name, p.category_id, COALESCE(c.name, $3), p.stock, p.unit,
+			COALESCE(sold.quantity, 0), last_sale.created_at,
+			ROUND(p.stock * p.cost_price)::int, ROUND(p.stock * p.price)::int
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		LEFT JOIN LATERAL (
+			SELECT -SUM(m.quantity) AS quantity
+			FROM stock_movements m
+			WHERE m.product_id = p.id AND m.type = $4 AND m.created_at >= $1
+		) sold ON true
+		LEFT JOIN LATERAL (
+			SELECT m.created_at
+			FROM stock_movements m
+			WHERE m.product_id = p.id AND m.type = $4
+			ORDER BY m.created_at DESC
+			LIMIT 1
+		) last_sale ON true
+		WHERE NOT p.is_bundle AND p.stock > 0
+		  AND ($2 = 0 OR p.category_id IN ` + categorySubtreeSQL("$2") + `)
+	`
+	rows, err := repo.db.Query(query, since, categoryID, models.UncategorizedName, models.StockMovementSale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.DeadStockItem, 0)
+	for rows.Next() {
+		var item models.DeadStockItem
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName, &item.Stock, &item.Unit,
+			&item.SoldQuantity, &item.LastSaleAt, &item.CostValue, &item.RetailValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetReorderCandidates mengembalikan produk non-bundle beserta supplier
+// purchase order terakhirnya (selain PO yang dibatalkan), lead time supplier
+// tersebut dan jumlah yang masih dipesan (PO sent atau partially_received
+// yang belum diterima). supplierID bukan 0 membatasi ke produk dengan
+// supplier terakhir tersebut. Ramalan dan saran diisi oleh service.
+func (repo *ReportRepository) GetReorderCandidates(categoryID, supplierID int) ([]models.ReorderSuggestion, error) {
+	query := `
+		SELECT p.id, p.name, p.category_id, COALESCE(c.name, $3), p.stock, p.unit, p.cost_price,
+			sup.id, sup.name, sup.lead_time_days, COALESCE(ordered.quantity, 0)
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		LEFT JOIN LATERAL (
+			SELECT s.id, s.name, s.lead_time_days
+			FROM purchase_order_items poi
+			JOIN purchase_orders po ON po.id = poi.purchase_order_id
+			JOIN suppliers s ON s.id = po.supplier_id
+			WHERE poi.product_id = p.id AND po.status <> $4
+			ORDER BY po.created_at DESC, po.id DESC
+			LIMIT 1
+		) sup ON true
+		LEFT JOIN LATERAL (
+			SELECT SUM(GREATEST(poi.quantity - poi.received_quantity, 0)) AS quantity
+			FROM purchase_order_items poi
+			JOIN purchase_orders po ON po.id = poi.purchase_order_id
+			WHERE poi.product_id = p.id AND po.status IN ($5, $6)
+		) ordered ON true
+		WHERE NOT p.is_bundle
+		  AND ($1 = 0 OR p.category_id IN ` + categorySubtreeSQL("$1") + `)
+		  AND ($2 = 0 OR sup.id = $2)
+	`
+	rows, err := repo.db.Query(query, categoryID, supplierID, models.UncategorizedName, models.PurchaseOrderCancelled,
+		models.PurchaseOrderSent, models.PurchaseOrderPartiallyReceived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ReorderSuggestion, 0)
+	for rows.Next() {
+		var item models.ReorderSuggestion
+		var leadTime *int
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryID, &item.CategoryName, &item.Stock, &item.Unit,
+			&item.UnitCost, &item.SupplierID, &item.SupplierName, &leadTime, &item.OnOrder)
+		if err != nil {
+			return nil, err
+		}
+		if leadTime != nil {
+			item.LeadTimeDays = *leadTime
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetDailySoldQuantities mengembalikan jumlah yang keluar karena penjualan
+// per produk per tanggal lokal zona waktu timezone (YYYY-MM-DD) pada
+// [start, end). Seperti laporan dead stock, penjualan diambil dari ledger
+// stok supaya komponen yang terjual lewat bundle ikut terhitung.
+func (repo *ReportRepository) GetDailySoldQuantities(start, end time.Time, timezone string) (map[int]map[string]models.Quantity, error) {
+	rows, err := repo.db.Query(`
+		SELECT product_id, to_char(created_at AT TIME ZONE $3, 'YYYY-MM-DD') AS sales_date, -SUM(quantity)
+		FROM stock_movements
+		WHERE type = $4 AND created_at >= $1 AND created_at < $2
+		GROUP BY product_id, sales_date
+	`, start, end, timezone, models.StockMovementSale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sold := make(map[int]map[string]models.Quantity)
+	for rows.Next() {
+		var productID int
+		var date string
+		var quantity models.Quantity
+		if err := rows.Scan(&productID, &date, &quantity); err != nil {
+			return nil, err
+		}
+		if sold[productID] == nil {
+			sold[productID] = make(map[string]models.Quantity)
+		}
+		sold[productID][date] = quantity
+	}
+
+	return sold, rows.Err()
+}
+
+// GetCustomerActivity mengembalikan setiap pelanggan yang bertransaksi pada
+// [start, end) beserta waktu transaksi terakhir, jumlah transaksi dan total
+// belanjanya. customerGroupID bukan 0 membatasi ke pelanggan kelompok
+// tersebut. Skor dan segmen diisi oleh service.
+func (repo *ReportRepository) GetCustomerActivity(start, end time.Time, customerGroupID int) ([]models.RFMCustomer, error) {
+	rows, err := repo.db.Query(`
+		SELECT c.id, c.name, c.phone, c.customer_group_id, g.name, MAX(t.created_at), COUNT(*), SUM(t.total_amount)
+		FROM transactions t
+		JOIN customers c ON c.id = t.customer_id
+		LEFT JOIN customer_groups g ON g.id = c.customer_group_id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		  AND ($3 = 0 OR c.customer_group_id = $3)
+		GROUP BY c.id, c.name, c.phone, c.customer_group_id, g.name
+	`, start, end, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.RFMCustomer, 0)
+	for rows.Next() {
+		var c models.RFMCustomer
+		err := rows.Scan(&c.CustomerID, &c.CustomerName, &c.Phone, &c.CustomerGroupID, &c.CustomerGroupName,
+			&c.LastPurchaseAt, &c.Frequency, &c.Monetary)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+// RebuildDailyRollups menghitung ulang rollup harian tanggal from sampai to
+// (YYYY-MM-DD, inklusif) dari tabel transaksi, dengan tanggal menurut zona
+// waktu timezone. from atau to kosong berarti tanpa batas. Tabel rollup
+// dikunci selama rebuild sehingga checkout yang berjalan bersamaan menunggu
+// dan tidak ada penjualan yang hilang atau terhitung dua kali. Mengembalikan
+// jumlah hari yang punya transaksi.
+func (repo *ReportRepository) RebuildDailyRollups(from, to *string, timezone string) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE daily_sales, daily_product_sales IN EXCLUSIVE MODE"); err != nil {
+		return 0, err
+	}
+
+	const dateFilter = `($1::date IS NULL OR sales_date >= $1::date) AND ($2::date IS NULL OR sales_date <= $2::date)`
+	if _, err := tx.Exec("DELETE FROM daily_sales WHERE "+dateFilter, from, to); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM daily_product_sales WHERE "+dateFilter, from, to); err != nil {
+		return 0, err
+	}
+
+	// Batas tanggal diubah ke timestamptz supaya index created_at terpakai
+	const createdFilter = `($1::date IS NULL OR t.created_at >= $1::date::timestamp AT TIME ZONE $3)
+		AND ($2::date IS NULL OR t.created_at < ($2::date + 1)::timestamp AT TIME ZONE $3)`
+	result, err := tx.Exec(`
+		INSERT INTO daily_sales (sales_date, revenue, transactions)
+		SELECT (t.created_at AT TIME ZONE $3)::date, SUM(t.total_amount), COUNT(*)
+		FROM transactions t
+		WHERE `+createdFilter+`
+		GROUP BY 1
+	`, from, to, timezone)
+	if err != nil {
+		return 0, err
+	}
+	days, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO daily_product_sales (sales_date, product_id, quantity, revenue, cost, transactions)
+		SELECT (t.created_at AT TIME ZONE $3)::date, td.product_id, SUM(td.base_quantity), SUM(td.subtotal),
+			SUM(td.cost_amount), COUNT(DISTINCT td.transaction_id)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		WHERE `+createdFilter+`
+		GROUP BY 1, 2
+	`, from, to, timezone)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(days), tx.Commit()
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"kasir-api/database"
+	"kasir-api/models"
+)
+
+// benchDB membuka database benchmark dari TEST_DATABASE_URL. Pakai database
+// terpisah yang sudah diisi dengan "go run . bench-seed", jangan database
+// production. Zona waktu toko dibaca dari STORE_TIMEZONE (default
+// Asia/Jakarta) dan harus sama dengan saat rollup dibangun.
+func benchDB(b *testing.B) (*sql.DB, *time.Location) {
+	b.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_URL not set")
+	}
+	tz := os.Getenv("STORE_TIMEZONE")
+	if tz == "" {
+		tz = "Asia/Jakarta"
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		b.Fatal(err)
+	}
+	db, err := database.InitDB(dsn, location)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		b.Fatal(err)
+	}
+	return db, location
+}
+
+// benchRange mengembalikan 30 hari terakhir (termasuk hari ini) di zona
+// waktu location sebagai [start, end).
+func benchRange(location *time.Location) (time.Time, time.Time) {
+	y, m, d := time.Now().In(location).Date()
+	end := time.Date(y, m, d+1, 0, 0, 0, 0, location)
+	return end.AddDate(0, 0, -30), end
+}
+
+// benchRawQuery menjalankan query dan membaca semua barisnya supaya waktunya
+// sebanding dengan method repository yang memindai hasil.
+func benchRawQuery(b *testing.B, db *sql.DB, query string, args ...any) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkGetSalesBuckets membandingkan bucket harian dari rollup dengan
+// query yang sama langsung ke tabel transaksi.
+func BenchmarkGetSalesBuckets(b *testing.B) {
+	db, location := benchDB(b)
+	repo := NewReportRepository(db)
+	start, end := benchRange(location)
+
+	b.Run("rollup", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.GetSalesBuckets(start, end, models.GranularityDay, location.String()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("raw", func(b *testing.B) {
+		for b.Loop() {
+			benchRawQuery(b, db, `
+				SELECT date_trunc('day', t.created_at AT TIME ZONE $3) AS bucket,
+					SUM(t.total_amount), COUNT(*), COALESCE(SUM(items.quantity), 0)
+				FROM transactions t
+				LEFT JOIN LATERAL (
+					SELECT SUM(td.base_quantity) AS quantity
+					FROM transaction_details td
+					WHERE td.transaction_id = t.id
+				) items ON true
+				WHERE t.created_at >= $1 AND t.created_at < $2
+				GROUP BY bucket
+				ORDER BY bucket
+			`, start, end, location.String())
+		}
+	})
+}
+
+// BenchmarkGetProductSales membandingkan penjualan per produk dari rollup
+// dengan query yang sama langsung ke tabel transaksi.
+func BenchmarkGetProductSales(b *testing.B) {
+	db, location := benchDB(b)
+	repo := NewReportRepository(db)
+	start, end := benchRange(location)
+
+	b.Run("rollup", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.GetProductSales(start, end, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("raw", func(b *testing.B) {
+		for b.Loop() {
+			benchRawQuery(b, db, `
+				SELECT p.id, p.name, p.category_id, COALESCE(c.name, $3),
+					SUM(td.subtotal), SUM(td.base_quantity), COUNT(DISTINCT td.transaction_id)
+				FROM transaction_details td
+				JOIN transactions t ON td.transaction_id = t.id
+				JOIN products p ON td.product_id = p.id
+				LEFT JOIN categories c ON c.id = p.category_id
+				WHERE t.created_at >= $1 AND t.created_at < $2
+				GROUP BY p.id, p.name, p.category_id, c.name
+				ORDER BY SUM(td.subtotal) DESC, p.name, p.id
+			`, start, end, models.UncategorizedName)
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"math"
+	"time"
+)
+
+// openingLotNumber dipakai untuk stok yang sudah ada sebelum produk mulai
+// dilacak per lot. Lot ini tidak punya tanggal kedaluwarsa.
+const openingLotNumber = "OPENING"
+
+type StockLotRepository struct {
+	db *sql.DB
+}
+
+func NewStockLotRepository(db *sql.DB) *StockLotRepository {
+	return &StockLotRepository{db: db}
+}
+
+const stockLotColumns = `l.id, l.product_id, p.name, l.lot_number, to_char(l.expiry_date, 'YYYY-MM-DD'),
+	l.quantity, l.initial_quantity, l.purchase_order_id, l.received_at`
+
+func scanStockLot(row rowScanner, l *models.StockLot, extra ...any) error {
+	dest := []any{&l.ID, &l.ProductID, &l.ProductName, &l.LotNumber, &l.ExpiryDate, &l.Quantity, &l.InitialQuantity, &l.PurchaseOrderID, &l.ReceivedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (repo *StockLotRepository) GetByProductID(productID int) ([]models.StockLot, error) {
+	query := `
+		SELECT ` + stockLotColumns + `
+		FROM stock_lots l
+		JOIN products p ON p.id = l.product_id
+		WHERE l.product_id = $1
+		ORDER BY l.quantity = 0, l.expiry_date NULLS LAST, l.id
+	`
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := make([]models.StockLot, 0)
+	for rows.Next() {
+		var l models.StockLot
+		if err := scanStockLot(rows, &l); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+
+	return lots, rows.Err()
+}
+
+// Receive menerima stok langsung ke sebuah lot (di luar purchase order).
+func (repo *StockLotRepository) Receive(productID int, req models.ReceiveLotRequest) (*models.StockLot, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var stock models.Quantity
+	var costPrice int
+	var trackLots bool
+	err = tx.QueryRow("SELECT stock, cost_price, track_lots FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&stock, &costPrice, &trackLots)
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !trackLots {
+		return nil, errors.New("produk ini tidak dilacak per lot (aktifkan track_lots terlebih dahulu)")
+	}
+
+	lotID, err := addToLot(tx, productID, req.LotNumber, req.ExpiryDate, req.Quantity, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	newStock := stock + req.Quantity
+	newCost := costPrice
+	if req.UnitCost != nil {
+		newCost, err = weightedCost(stock, costPrice, req.Quantity, *req.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE products SET stock = $1, cost_price = $2, updated_at = $3 WHERE id = $4", newStock, newCost, time.Now(), productID)
+	if err != nil {
+		return nil, err
+	}
+	if newCost != costPrice {
+		if err := recordCostChange(tx, productID, &costPrice, newCost); err != nil {
+			return nil, err
+		}
+	}
+
+	err = recordStockMovement(tx, &models.StockMovement{
+		ProductID:     productID,
+		Quantity:      req.Quantity,
+		Type:          models.StockMovementReceipt,
+		ReferenceType: stringPtr("stock_lot"),
+		ReferenceID:   intPtr(lotID),
+		UnitCost:      req.UnitCost,
+		StockAfter:    newStock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var lot models.StockLot
+	err = scanStockLot(tx.QueryRow(`
+		SELECT `+stockLotColumns+`
+		FROM stock_lots l
+		JOIN products p ON p.id = l.product_id
+		WHERE l.id = $1
+	`, lotID), &lot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// GetExpiring mengembalikan lot yang masih ada stoknya dan kedaluwarsa paling
+// lambat pada tanggal until, termasuk yang sudah kedaluwarsa.
+func (repo *StockLotRepository) GetExpiring(today, until time.Time) ([]models.ExpiringLot, error) {
+	query := `
+		SELECT ` + stockLotColumns + `, (l.expiry_date - $1::date), ROUND(l.quantity * p.cost_price)::int
+		FROM stock_lots l
+		JOIN products p ON p.id = l.product_id
+		WHERE l.quantity > 0 AND l.expiry_date IS NOT NULL AND l.expiry_date <= $2
+		ORDER BY l.expiry_date, p.name
+	`
+	rows, err := repo.db.Query(query, today.Format("2006-01-02"), until.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := make([]models.ExpiringLot, 0)
+	for rows.Next() {
+		var l models.ExpiringLot
+		if err := scanStockLot(rows, &l.StockLot, &l.DaysUntilExpiry, &l.CostValue); err != nil {
+			return nil, err
+		}
+		l.Expired = l.DaysUntilExpiry < 0
+		lots = append(lots, l)
+	}
+
+	return lots, rows.Err()
+}
+
+// addToLot menambah quantity ke lot (dibuat jika belum ada) dan mengembalikan id lot.
+// Nomor lot yang sudah ada hanya bisa ditambah jika tanggal kedaluwarsanya sama,
+// supaya urutan FEFO tidak kacau karena satu lot berisi barang dengan expiry berbeda.
+func addToLot(tx *sql.Tx, productID int, lotNumber string, expiryDate *string, quantity models.Quantity, purchaseOrderID *int) (int, error) {
+	var lotID int
+	err := tx.QueryRow(`
+		INSERT INTO stock_lots (product_id, lot_number, expiry_date, quantity, initial_quantity, purchase_order_id)
+		VALUES ($1, $2, $3, $4, $4, $5)
+		ON CONFLICT (product_id, lot_number) DO UPDATE
+		SET quantity = stock_lots.quantity + EXCLUDED.quantity,
+			initial_quantity = stock_lots.initial_quantity + EXCLUDED.initial_quantity
+		WHERE stock_lots.expiry_date IS NOT DISTINCT FROM EXCLUDED.expiry_date
+		RETURNING id
+	`, productID, lotNumber, expiryDate, quantity, purchaseOrderID).Scan(&lotID)
+	if err != sql.ErrNoRows {
+		return lotID, err
+	}
+
+	// Tidak ada baris yang dikembalikan berarti lot sudah ada dengan expiry berbeda
+	var existing sql.NullString
+	err = tx.QueryRow(
+		"SELECT to_char(expiry_date, 'YYYY-MM-DD') FROM stock_lots WHERE product_id = $1 AND lot_number = $2",
+		productID, lotNumber,
+	).Scan(&existing)
+	if err != nil {
+		return 0, err
+	}
+	stored, received := "tanpa tanggal", "tanpa tanggal"
+	if existing.Valid {
+		stored = existing.String
+	}
+	if expiryDate != nil {
+		received = *expiryDate
+	}
+	return 0, fmt.Errorf("lot %s sudah ada dengan tanggal kedaluwarsa %s, tidak bisa ditambah barang dengan kedaluwarsa %s", lotNumber, stored, received)
+}
+
+// consumeLots mengambil stok dari lot yang belum kedaluwarsa dengan urutan
+// FEFO (first expired, first out). Lot tanpa tanggal kedaluwarsa dipakai
+// paling akhir. Lot yang sudah kedaluwarsa tidak pernah dijual.
+func consumeLots(tx *sql.Tx, productID int, productName string, quantity models.Quantity, today time.Time) ([]models.TransactionDetailLot, error) {
+	rows, err := tx.Query(`
+		SELECT id, lot_number, to_char(expiry_date, 'YYYY-MM-DD'), quantity
+		FROM stock_lots
+		WHERE product_id = $1 AND quantity > 0 AND (expiry_date IS NULL OR expiry_date >= $2)
+		ORDER BY expiry_date NULLS LAST, id
+		FOR UPDATE
+	`, productID, today.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	var available models.Quantity
+	lots := make([]models.TransactionDetailLot, 0)
+	for rows.Next() {
+		var l models.TransactionDetailLot
+		if err := rows.Scan(&l.LotID, &l.LotNumber, &l.ExpiryDate, &l.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		l.ProductID = productID
+		available += l.Quantity
+		lots = append(lots, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if available < quantity {
+		return nil, fmt.Errorf("stock yang belum kedaluwarsa tidak cukup untuk product %s (tersedia: %s, diminta: %s)", productName, available, quantity)
+	}
+
+	consumed := make([]models.TransactionDetailLot, 0)
+	remaining := quantity
+	for _, l := range lots {
+		if remaining == 0 {
+			break
+		}
+		take := min(l.Quantity, remaining)
+		remaining -= take
+
+		if _, err := tx.Exec("UPDATE stock_lots SET quantity = quantity - $1 WHERE id = $2", take, l.LotID); err != nil {
+			return nil, err
+		}
+
+		l.Quantity = take
+		consumed = append(consumed, l)
+	}
+
+	return consumed, nil
+}
+
+// weightedCost menghitung harga pokok rata-rata tertimbang (per unit dasar)
+// setelah menerima quantity barang dengan harga unitCost.
+func weightedCost(stock models.Quantity, costPrice int, quantity models.Quantity, unitCost int) (int, error) {
+	if stock <= 0 {
+		return unitCost, nil
+	}
+	stockValue, err := stock.MulPrice(costPrice)
+	if err != nil {
+		return 0, err
+	}
+	receivedValue, err := quantity.MulPrice(unitCost)
+	if err != nil {
+		return 0, err
+	}
+	totalValue := int64(stockValue + receivedValue)
+	if totalValue > math.MaxInt64/models.QuantityScale {
+		return 0, models.ErrQuantityOverflow
+	}
+	totalQty := int64(stock + quantity)
+	return int((totalValue*models.QuantityScale + totalQty/2) / totalQty), nil
+}
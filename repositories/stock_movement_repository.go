@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+func (repo *StockMovementRepository) GetByProductID(productID int) ([]models.StockMovement, error) {
+	query := `
+		SELECT id, product_id, quantity, type, reference_type, reference_id, unit_cost, stock_after, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.ProductID, &m.Quantity, &m.Type, &m.ReferenceType, &m.ReferenceID, &m.UnitCost, &m.StockAfter, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
+
+// recordStockMovement mencatat perubahan stok ke ledger. Dipanggil di dalam
+// transaksi yang sama dengan UPDATE products.stock supaya keduanya selalu
+// konsisten.
+func recordStockMovement(tx *sql.Tx, m *models.StockMovement) error {
+	query := `
+		INSERT INTO stock_movements (product_id, quantity, type, reference_type, reference_id, unit_cost, stock_after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return tx.QueryRow(query, m.ProductID, m.Quantity, m.Type, m.ReferenceType, m.ReferenceID, m.UnitCost, m.StockAfter).
+		Scan(&m.ID, &m.CreatedAt)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
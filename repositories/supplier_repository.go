@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+)
+
+var ErrSupplierNotFound = errors.New("supplier tidak ditemukan")
+
+// SupplierInUseError dikembalikan saat supplier yang masih dipakai purchase
+// order dihapus.
+type SupplierInUseError struct {
+	PurchaseOrderCount int
+}
+
+func (e *SupplierInUseError) Error() string {
+	return fmt.Sprintf("supplier masih dipakai oleh %d purchase order", e.PurchaseOrderCount)
+}
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+func (repo *SupplierRepository) GetAll() ([]models.Supplier, error) {
+	query := "SELECT id, name, contact_name, phone, email, address, lead_time_days, created_at, updated_at FROM suppliers ORDER BY name"
+	rows, err := repo.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		var s models.Supplier
+		err := rows.Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.LeadTimeDays, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	return suppliers, nil
+}
+
+func (repo *SupplierRepository) Create(supplier *models.Supplier) error {
+	query := "INSERT INTO suppliers (name, contact_name, phone, email, address, lead_time_days, created_at) VALUES ($1, $2, $3, $4, $5, COALESCE($6, 7), CURRENT_TIMESTAMP) RETURNING id, lead_time_days, created_at"
+	return repo.db.QueryRow(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.LeadTimeDays).
+		Scan(&supplier.ID, &supplier.LeadTimeDays, &supplier.CreatedAt)
+}
+
+func (repo *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+	query := "SELECT id, name, contact_name, phone, email, address, lead_time_days, created_at, updated_at FROM suppliers WHERE id = $1"
+
+	var s models.Supplier
+	err := repo.db.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.LeadTimeDays, &s.CreatedAt, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSupplierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (repo *SupplierRepository) Update(supplier *models.Supplier) error {
+	query := "UPDATE suppliers SET name = $1, contact_name = $2, phone = $3, email = $4, address = $5, lead_time_days = COALESCE($6, lead_time_days), updated_at = CURRENT_TIMESTAMP WHERE id = $7 RETURNING lead_time_days, created_at, updated_at"
+	err := repo.db.QueryRow(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.LeadTimeDays, supplier.ID).
+		Scan(&supplier.LeadTimeDays, &supplier.CreatedAt, &supplier.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrSupplierNotFound
+	}
+	return err
+}
+
+// Delete menghapus supplier yang belum punya purchase order. Baris supplier
+// dikunci lebih dulu supaya purchase order baru untuk supplier ini menunggu
+// sampai penghapusan selesai.
+func (repo *SupplierRepository) Delete(id int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRow("SELECT id FROM suppliers WHERE id = $1 FOR UPDATE", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrSupplierNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var purchaseOrderCount int
+	err = tx.QueryRow("SELECT COUNT(*) FROM purchase_orders WHERE supplier_id = $1", id).Scan(&purchaseOrderCount)
+	if err != nil {
+		return err
+	}
+	if purchaseOrderCount > 0 {
+		return &SupplierInUseError{PurchaseOrderCount: purchaseOrderCount}
+	}
+
+	if _, err := tx.Exec("DELETE FROM suppliers WHERE id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"kasir-api/models"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -15,65 +17,137 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+// CreateTransaction menyimpan transaksi, mengurangi stok dan memperbarui
+// rollup penjualan harian. now adalah waktu transaksi dalam zona waktu toko;
+// tanggalnya dipakai untuk melewati lot yang sudah kedaluwarsa dan sebagai
+// tanggal rollup.
+func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem, customerID, customerGroupID *int, now time.Time) (*models.Transaction, error) {
 	tx, err := repo.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
+	customerGroupID, err = resolveCustomerGroup(tx, customerID, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+	priced, err := priceCheckoutItems(tx, items, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+
 	totalAmount := 0
 	details := make([]models.TransactionDetail, 0)
+	movements := make([]models.StockMovement, 0)
 
-	for _, item := range items {
-		var productPrice, stock int
-		var productName string
-
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &stock)
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("stock tidak cukup untuk product %s (tersedia: %d, diminta: %d)", productName, stock, item.Quantity)
-		}
-
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
+	for _, item := range priced {
+		detail := item.detail
+		baseQty := detail.BaseQuantity
+		totalAmount += detail.Subtotal
 
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, item.ProductID)
-		if err != nil {
-			return nil, err
+		if item.isBundle {
+			// Bundle tidak punya stok sendiri, yang dikurangi adalah stok komponennya
+			components, err := getBundleComponentsForSale(tx, detail.ProductID)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range components {
+				componentQty, err := c.Quantity.Mul(baseQty)
+				if err != nil {
+					return nil, fmt.Errorf("quantity komponen %s untuk bundle %s terlalu besar", c.ProductName, detail.ProductName)
+				}
+				movement, lots, err := deductStock(tx, c.ProductID, componentQty, now)
+				if err != nil {
+					return nil, err
+				}
+				movements = append(movements, movement)
+				detail.Lots = append(detail.Lots, lots...)
+				cost, err := (-movement.Quantity).MulPrice(*movement.UnitCost)
+				if err != nil {
+					return nil, err
+				}
+				detail.CostAmount += cost
+			}
+			detail.Components, err = allocateBundleRevenue(components, baseQty, detail.Subtotal)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			movement, lots, err := deductStock(tx, detail.ProductID, baseQty, now)
+			if err != nil {
+				return nil, err
+			}
+			movements = append(movements, movement)
+			detail.Lots = lots
+			detail.CostAmount, err = baseQty.MulPrice(*movement.UnitCost)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
-		})
+		details = append(details, detail)
 	}
 
 	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+	err = tx.QueryRow("INSERT INTO transactions (total_amount, customer_id, customer_group_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		totalAmount, customerID, customerGroupID, now).Scan(&transactionID)
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range details {
 		details[i].TransactionID = transactionID
+		var tierMinQty *models.Quantity
+		var tierGroupID *int
+		if tier := details[i].PriceTier; tier != nil {
+			tierMinQty = &tier.MinQuantity
+			tierGroupID = tier.CustomerGroupID
+		}
+
 		var detailID int
-		err = tx.QueryRow(
-			"INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4) RETURNING id",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal,
+		err = tx.QueryRow(`
+			INSERT INTO transaction_details (transaction_id, product_id, quantity, unit, base_quantity, unit_price, tier_min_quantity, tier_customer_group_id, subtotal, cost_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+			transactionID, details[i].ProductID, details[i].Quantity, details[i].Unit, details[i].BaseQuantity,
+			details[i].UnitPrice, tierMinQty, tierGroupID, details[i].Subtotal, details[i].CostAmount,
 		).Scan(&detailID)
 		if err != nil {
 			return nil, err
 		}
 		details[i].ID = detailID
+
+		for _, l := range details[i].Lots {
+			_, err = tx.Exec(
+				"INSERT INTO transaction_detail_lots (transaction_detail_id, product_id, lot_id, quantity) VALUES ($1, $2, $3, $4)",
+				detailID, l.ProductID, l.LotID, l.Quantity,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, c := range details[i].Components {
+			_, err = tx.Exec(
+				"INSERT INTO transaction_detail_components (transaction_detail_id, product_id, quantity, revenue) VALUES ($1, $2, $3, $4)",
+				detailID, c.ProductID, c.Quantity, c.Revenue,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := addToDailyRollups(tx, now, totalAmount, details); err != nil {
+		return nil, err
+	}
+
+	for i := range movements {
+		movements[i].ReferenceType = stringPtr("transaction")
+		movements[i].ReferenceID = intPtr(transactionID)
+		if err := recordStockMovement(tx, &movements[i]); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -81,48 +155,478 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 	}
 
 	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
+		ID:              transactionID,
+		TotalAmount:     totalAmount,
+		CustomerID:      customerID,
+		CustomerGroupID: customerGroupID,
+		Details:         details,
 	}, nil
 }
 
-func (repo *TransactionRepository) GetTodaySummary() (*models.SalesReport, error) {
-	today := time.Now().Format("2006-01-02")
-	return repo.getSummaryByDate(today, today)
+// Quote menghitung harga checkout (termasuk unit dan tier grosir) tanpa
+// menyimpan transaksi atau mengurangi stok.
+func (repo *TransactionRepository) Quote(items []models.CheckoutItem, customerID, customerGroupID *int) (*models.Transaction, error) {
+	customerGroupID, err := resolveCustomerGroup(repo.db, customerID, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+	priced, err := priceCheckoutItems(repo.db, items, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &models.Transaction{
+		CustomerID:      customerID,
+		CustomerGroupID: customerGroupID,
+		Details:         make([]models.TransactionDetail, len(priced)),
+	}
+	for i, item := range priced {
+		quote.Details[i] = item.detail
+		quote.TotalAmount += item.detail.Subtotal
+	}
+	return quote, nil
 }
 
-func (repo *TransactionRepository) GetSummaryByDateRange(startDate, endDate time.Time) (*models.SalesReport, error) {
-	return repo.getSummaryByDate(startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+// queryRower dipenuhi oleh *sql.DB dan *sql.Tx sehingga perhitungan harga bisa
+// dipakai untuk checkout maupun quote.
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
 }
 
-func (repo *TransactionRepository) getSummaryByDate(startDate, endDate string) (*models.SalesReport, error) {
+// querier dipenuhi *sql.DB dan *sql.Tx untuk query yang mengembalikan banyak
+// baris.
+type querier interface {
+	queryRower
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// resolveCustomerGroup memastikan pelanggan customerID ada dan mengembalikan
+// customer group untuk harga: customerGroupID jika diisi kasir, selain itu
+// kelompok pelanggan tersebut.
+func resolveCustomerGroup(q queryRower, customerID, customerGroupID *int) (*int, error) {
+	if customerID == nil {
+		return customerGroupID, nil
+	}
+
+	var groupID *int
+	err := q.QueryRow("SELECT customer_group_id FROM customers WHERE id = $1", *customerID).Scan(&groupID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("customer id %d not found", *customerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if customerGroupID != nil {
+		return customerGroupID, nil
+	}
+	return groupID, nil
+}
+
+// pricedItem adalah item checkout yang harganya sudah dihitung.
+type pricedItem struct {
+	detail   models.TransactionDetail
+	isBundle bool
+}
+
+// priceCheckoutItems menghitung harga setiap item checkout. Harga per unit
+// diambil dari unit jual, lalu diganti harga tier grosir jika tier yang
+// berlaku lebih murah. Syarat tier dihitung dari total jumlah (unit dasar)
+// produk yang sama di seluruh keranjang, jadi produk yang discan di beberapa
+// baris tetap mendapat harga grosir.
+func priceCheckoutItems(q querier, items []models.CheckoutItem, customerGroupID *int) ([]pricedItem, error) {
+	if customerGroupID != nil {
+		var exists bool
+		err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM customer_groups WHERE id = $1)", *customerGroupID).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("customer group id %d not found", *customerGroupID)
+		}
+	}
+
+	priced := make([]pricedItem, len(items))
+	factors := make([]models.Quantity, len(items))
+	totals := make(map[int]models.Quantity)
+	for i, item := range items {
+		var productPrice int
+		var productName, baseUnit string
+		var isBundle bool
+
+		err := q.QueryRow("SELECT name, price, unit, is_bundle FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &baseUnit, &isBundle)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		unit, factor, unitPrice, err := resolveSaleUnit(q, item.ProductID, productName, baseUnit, productPrice, item.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		// Stok selalu dikurangi dalam unit dasar. Quantity kecil dengan faktor
+		// pecahan bisa dibulatkan menjadi 0, yang berarti barang gratis.
+		baseQty, err := item.Quantity.Mul(factor)
+		if err != nil {
+			return nil, fmt.Errorf("quantity untuk product %s terlalu besar", productName)
+		}
+		if baseQty <= 0 {
+			return nil, fmt.Errorf("quantity untuk product %s terlalu kecil, menjadi %s %s setelah konversi unit", productName, baseQty, baseUnit)
+		}
+		totals[item.ProductID] += baseQty
+		factors[i] = factor
+		priced[i] = pricedItem{
+			detail: models.TransactionDetail{
+				ProductID:    item.ProductID,
+				ProductName:  productName,
+				Quantity:     item.Quantity,
+				Unit:         unit,
+				BaseQuantity: baseQty,
+				UnitPrice:    unitPrice,
+			},
+			isBundle: isBundle,
+		}
+	}
+
+	tiers := make(map[int][]models.PriceTier)
+	for i := range priced {
+		d := &priced[i].detail
+		productTiers, ok := tiers[d.ProductID]
+		if !ok {
+			var err error
+			productTiers, err = getPriceTiers(q, d.ProductID, customerGroupID)
+			if err != nil {
+				return nil, err
+			}
+			tiers[d.ProductID] = productTiers
+		}
+		if tier := bestPriceTier(productTiers, totals[d.ProductID], customerGroupID); tier != nil {
+			tierPrice, err := factors[i].MulPrice(tier.Price)
+			if err != nil {
+				return nil, err
+			}
+			if tierPrice < d.UnitPrice {
+				d.UnitPrice = tierPrice
+				d.PriceTier = tier
+			}
+		}
+
+		// Subtotal dibulatkan ke Rupiah
+		subtotal, err := d.Quantity.MulPrice(d.UnitPrice)
+		if err != nil {
+			return nil, fmt.Errorf("subtotal untuk product %s terlalu besar", d.ProductName)
+		}
+		d.Subtotal = subtotal
+	}
+
+	return priced, nil
+}
+
+// getPriceTiers mengembalikan tier umum produk beserta tier kelompok
+// pelanggan customerGroupID.
+func getPriceTiers(q querier, productID int, customerGroupID *int) ([]models.PriceTier, error) {
+	rows, err := q.Query(`
+		SELECT id, customer_group_id, min_quantity, price
+		FROM product_price_tiers
+		WHERE product_id = $1 AND (customer_group_id IS NULL OR customer_group_id = $2)
+	`, productID, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tiers := make([]models.PriceTier, 0)
+	for rows.Next() {
+		var t models.PriceTier
+		if err := rows.Scan(&t.ID, &t.CustomerGroupID, &t.MinQuantity, &t.Price); err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers, rows.Err()
+}
+
+// bestPriceTier memilih tier termurah yang berlaku untuk jumlah baseQty
+// (total unit dasar produk di keranjang), baik tier umum maupun tier
+// kelompok customerGroupID. Jika harganya sama, tier kelompok pelanggan
+// didahulukan, lalu tier dengan min_quantity terbesar. nil berarti tidak ada
+// tier yang berlaku.
+func bestPriceTier(tiers []models.PriceTier, baseQty models.Quantity, customerGroupID *int) *models.PriceTier {
+	var best *models.PriceTier
+	for i := range tiers {
+		t := &tiers[i]
+		if t.MinQuantity > baseQty {
+			continue
+		}
+		if t.CustomerGroupID != nil && (customerGroupID == nil || *t.CustomerGroupID != *customerGroupID) {
+			continue
+		}
+
+		switch {
+		case best == nil, t.Price < best.Price:
+			best = t
+		case t.Price > best.Price:
+		case (t.CustomerGroupID != nil) != (best.CustomerGroupID != nil):
+			if t.CustomerGroupID != nil {
+				best = t
+			}
+		case t.MinQuantity > best.MinQuantity:
+			best = t
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	tier := *best
+	return &tier
+}
+
+// resolveSaleUnit menentukan unit jual untuk item checkout. Unit kosong atau
+// sama dengan unit dasar berarti faktor 1 dengan harga produk; unit alternatif
+// memakai harganya sendiri, atau harga produk x faktor jika tidak diisi.
+func resolveSaleUnit(q queryRower, productID int, productName, baseUnit string, basePrice int, name string) (string, models.Quantity, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, baseUnit) {
+		return baseUnit, models.NewQuantity(1), basePrice, nil
+	}
+
+	var unit string
+	var factor models.Quantity
+	var price *int
+	err := q.QueryRow(
+		"SELECT name, factor, price FROM product_units WHERE product_id = $1 AND lower(name) = lower($2)",
+		productID, name,
+	).Scan(&unit, &factor, &price)
+	if err == sql.ErrNoRows {
+		return "", 0, 0, fmt.Errorf("unit %s tidak tersedia untuk product %s", name, productName)
+	}
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if price != nil {
+		return unit, factor, *price, nil
+	}
+	unitPrice, err := factor.MulPrice(basePrice)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return unit, factor, unitPrice, nil
+}
+
+// deductStock mengunci baris produk, memastikan stok cukup lalu menguranginya.
+// Untuk produk dengan track_lots stok diambil dari lot secara FEFO. Movement
+// yang dikembalikan belum dicatat karena id transaksi belum ada.
+func deductStock(tx *sql.Tx, productID int, quantity models.Quantity, today time.Time) (models.StockMovement, []models.TransactionDetailLot, error) {
+	var name string
+	var stock models.Quantity
+	var trackLots bool
+	var costPrice int
+	err := tx.QueryRow("SELECT name, stock, track_lots, cost_price FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&name, &stock, &trackLots, &costPrice)
+	if err == sql.ErrNoRows {
+		return models.StockMovement{}, nil, fmt.Errorf("product id %d not found", productID)
+	}
+	if err != nil {
+		return models.StockMovement{}, nil, err
+	}
+
+	if stock < quantity {
+		return models.StockMovement{}, nil, fmt.Errorf("stock tidak cukup untuk product %s (tersedia: %s, diminta: %s)", name, stock, quantity)
+	}
+
+	var lots []models.TransactionDetailLot
+	if trackLots {
+		lots, err = consumeLots(tx, productID, name, quantity, today)
+		if err != nil {
+			return models.StockMovement{}, nil, err
+		}
+	}
+
+	var remaining models.Quantity
+	err = tx.QueryRow("UPDATE products SET stock = stock - $1 WHERE id = $2 RETURNING stock", quantity, productID).Scan(&remaining)
+	if err != nil {
+		return models.StockMovement{}, nil, err
+	}
+
+	return models.StockMovement{
+		ProductID:  productID,
+		Quantity:   -quantity,
+		Type:       models.StockMovementSale,
+		UnitCost:   &costPrice,
+		StockAfter: remaining,
+	}, lots, nil
+}
+
+// addToDailyRollups menambahkan satu transaksi ke rollup harian tanggal now.
+// Baris produk diperbarui urut product id supaya checkout yang bersamaan
+// tidak saling deadlock.
+func addToDailyRollups(tx *sql.Tx, now time.Time, totalAmount int, details []models.TransactionDetail) error {
+	salesDate := now.Format("2006-01-02")
+
+	type productSales struct {
+		quantity      models.Quantity
+		revenue, cost int
+	}
+	byProduct := make(map[int]*productSales)
+	productIDs := make([]int, 0, len(details))
+	for _, d := range details {
+		p, ok := byProduct[d.ProductID]
+		if !ok {
+			p = &productSales{}
+			byProduct[d.ProductID] = p
+			productIDs = append(productIDs, d.ProductID)
+		}
+		p.quantity += d.BaseQuantity
+		p.revenue += d.Subtotal
+		p.cost += d.CostAmount
+	}
+	sort.Ints(productIDs)
+
+	for _, id := range productIDs {
+		p := byProduct[id]
+		_, err := tx.Exec(`
+			INSERT INTO daily_product_sales (sales_date, product_id, quantity, revenue, cost, transactions)
+			VALUES ($1, $2, $3, $4, $5, 1)
+			ON CONFLICT (sales_date, product_id) DO UPDATE SET
+				quantity = daily_product_sales.quantity + EXCLUDED.quantity,
+				revenue = daily_product_sales.revenue + EXCLUDED.revenue,
+				cost = daily_product_sales.cost + EXCLUDED.cost,
+				transactions = daily_product_sales.transactions + 1`,
+			salesDate, id, p.quantity, p.revenue, p.cost,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO daily_sales (sales_date, revenue, transactions)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (sales_date) DO UPDATE SET
+			revenue = daily_sales.revenue + EXCLUDED.revenue,
+			transactions = daily_sales.transactions + 1`,
+		salesDate, totalAmount,
+	)
+	return err
+}
+
+// bundleComponentSale adalah komponen bundle beserta harga jual normalnya,
+// dipakai untuk mengalokasikan pendapatan bundle.
+type bundleComponentSale struct {
+	ProductID   int
+	ProductName string
+	Quantity    models.Quantity
+	Price       int
+}
+
+func getBundleComponentsForSale(tx *sql.Tx, bundleID int) ([]bundleComponentSale, error) {
+	rows, err := tx.Query(`
+		SELECT bc.component_id, p.name, bc.quantity, p.price
+		FROM bundle_components bc
+		JOIN products p ON p.id = bc.component_id
+		WHERE bc.bundle_id = $1
+		ORDER BY bc.component_id
+	`, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make([]bundleComponentSale, 0)
+	for rows.Next() {
+		var c bundleComponentSale
+		if err := rows.Scan(&c.ProductID, &c.ProductName, &c.Quantity, &c.Price); err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("bundle id %d belum memiliki komponen", bundleID)
+	}
+	return components, nil
+}
+
+// allocateBundleRevenue membagi subtotal bundle ke komponen secara
+// proporsional terhadap harga jual normal x quantity. Sisa pembulatan
+// diberikan ke komponen terakhir sehingga total alokasi selalu sama dengan
+// subtotal.
+func allocateBundleRevenue(components []bundleComponentSale, bundleQty models.Quantity, subtotal int) ([]models.TransactionDetailComponent, error) {
+	weights := make([]int, len(components))
+	totalWeight := 0
+	for i, c := range components {
+		weight, err := c.Quantity.MulPrice(c.Price)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = weight
+		totalWeight += weight
+	}
+
+	result := make([]models.TransactionDetailComponent, len(components))
+	allocated := 0
+	for i, c := range components {
+		weight := weights[i]
+		revenue := 0
+		switch {
+		case i == len(components)-1:
+			revenue = subtotal - allocated
+		case totalWeight > 0:
+			revenue = subtotal * weight / totalWeight
+		default:
+			revenue = subtotal / len(components)
+		}
+		allocated += revenue
+
+		quantity, err := c.Quantity.Mul(bundleQty)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = models.TransactionDetailComponent{
+			ProductID:   c.ProductID,
+			ProductName: c.ProductName,
+			Quantity:    quantity,
+			Revenue:     revenue,
+		}
+	}
+	return result, nil
+}
+
+// GetSummary menghitung ringkasan penjualan transaksi pada [start, end) dari
+// rollup harian.
+// Jika categoryID diisi, hanya item dari kategori tersebut (termasuk
+// sub-kategorinya) yang dihitung dan jumlah transaksi adalah transaksi yang
+// memuat item tersebut.
+func (repo *TransactionRepository) GetSummary(start, end time.Time, categoryID int) (*models.SalesReport, error) {
 	report := &models.SalesReport{}
 
 	// Get total revenue and total transactions
-	summaryQuery := `
-		SELECT COALESCE(SUM(total_amount), 0), COUNT(*)
-		FROM transactions
-		WHERE DATE(created_at) >= $1 AND DATE(created_at) <= $2
-	`
-	err := repo.db.QueryRow(summaryQuery, startDate, endDate).Scan(&report.TotalRevenue, &report.TotalTransaksi)
+	totals, err := salesTotals(repo.db, start, end, categoryID)
 	if err != nil {
 		return nil, err
 	}
+	report.TotalRevenue = totals.Revenue
+	report.TotalTransaksi = totals.Transactions
 
 	// Get best selling product
 	bestSellerQuery := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) as total_qty
-		FROM transaction_details td
-		JOIN transactions t ON td.transaction_id = t.id
-		JOIN products p ON td.product_id = p.id
-		WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2
+		SELECT p.name, SUM(d.quantity) as total_qty
+		FROM daily_product_sales d
+		JOIN products p ON d.product_id = p.id
+		WHERE d.sales_date >= $1 AND d.sales_date < $2
+		  AND ($3 = 0 OR p.category_id IN ` + categorySubtreeSQL("$3") + `)
 		GROUP BY p.id, p.name
-		ORDER BY total_qty DESC
+		ORDER BY total_qty DESC, p.name, p.id
 		LIMIT 1
 	`
+	startDate, endDate := rollupDates(start, end)
 	var bestSeller models.BestSellingProduct
-	err = repo.db.QueryRow(bestSellerQuery, startDate, endDate).Scan(&bestSeller.Nama, &bestSeller.QtyTerjual)
+	err = repo.db.QueryRow(bestSellerQuery, startDate, endDate, categoryID).Scan(&bestSeller.Nama, &bestSeller.QtyTerjual)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -132,3 +636,34 @@ func (repo *TransactionRepository) getSummaryByDate(startDate, endDate string) (
 
 	return report, nil
 }
+
+// GetBundleComponentSales mengembalikan pendapatan bundle yang diatribusikan
+// ke setiap komponen untuk transaksi pada [start, end).
+func (repo *TransactionRepository) GetBundleComponentSales(start, end time.Time) ([]models.BundleComponentSales, error) {
+	query := `
+		SELECT p.id, p.name, COALESCE(SUM(tdc.quantity), 0), COALESCE(SUM(tdc.revenue), 0)
+		FROM transaction_detail_components tdc
+		JOIN transaction_details td ON tdc.transaction_detail_id = td.id
+		JOIN transactions t ON td.transaction_id = t.id
+		JOIN products p ON tdc.product_id = p.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY p.id, p.name
+		ORDER BY 4 DESC, p.name
+	`
+	rows, err := repo.db.Query(query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.BundleComponentSales, 0)
+	for rows.Next() {
+		var s models.BundleComponentSales
+		if err := rows.Scan(&s.ProductID, &s.ProductName, &s.Quantity, &s.Revenue); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
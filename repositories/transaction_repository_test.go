@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestAllocateBundleRevenue(t *testing.T) {
+	q := func(n int64) models.Quantity { return models.Quantity(n * models.QuantityScale) }
+
+	tests := []struct {
+		name       string
+		components []bundleComponentSale
+		bundleQty  models.Quantity
+		subtotal   int
+		want       []int
+	}{
+		{
+			name: "proportional to regular price",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: q(1), Price: 3000},
+				{ProductID: 2, Quantity: q(2), Price: 1000},
+			},
+			bundleQty: q(1),
+			subtotal:  4500,
+			want:      []int{2700, 1800},
+		},
+		{
+			name: "rounding remainder goes to last component",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: q(1), Price: 1000},
+				{ProductID: 2, Quantity: q(1), Price: 1000},
+				{ProductID: 3, Quantity: q(1), Price: 1000},
+			},
+			bundleQty: q(1),
+			subtotal:  10000,
+			want:      []int{3333, 3333, 3334},
+		},
+		{
+			name: "zero-price component gets nothing",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: q(1), Price: 0},
+				{ProductID: 2, Quantity: q(1), Price: 5000},
+				{ProductID: 3, Quantity: q(1), Price: 2500},
+			},
+			bundleQty: q(2),
+			subtotal:  13999,
+			want:      []int{0, 9332, 4667},
+		},
+		{
+			name: "zero-price last component still takes the remainder",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: q(1), Price: 700},
+				{ProductID: 2, Quantity: q(1), Price: 700},
+				{ProductID: 3, Quantity: q(1), Price: 0},
+			},
+			bundleQty: q(1),
+			subtotal:  1001,
+			want:      []int{500, 500, 1},
+		},
+		{
+			name: "all components free split evenly",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: q(1), Price: 0},
+				{ProductID: 2, Quantity: q(3), Price: 0},
+				{ProductID: 3, Quantity: q(1), Price: 0},
+			},
+			bundleQty: q(1),
+			subtotal:  1000,
+			want:      []int{333, 333, 334},
+		},
+		{
+			name: "fractional quantities",
+			components: []bundleComponentSale{
+				{ProductID: 1, Quantity: 250, Price: 12000},
+				{ProductID: 2, Quantity: 1500, Price: 999},
+			},
+			bundleQty: 1500,
+			subtotal:  7777,
+			want:      []int{5185, 2592},
+		},
+		{
+			name:       "single component gets everything",
+			components: []bundleComponentSale{{ProductID: 1, Quantity: q(2), Price: 1500}},
+			bundleQty:  q(3),
+			subtotal:   8000,
+			want:       []int{8000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateBundleRevenue(tt.components, tt.bundleQty, tt.subtotal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.components) {
+				t.Fatalf("got %d components, want %d", len(got), len(tt.components))
+			}
+			sum := 0
+			for i, c := range got {
+				sum += c.Revenue
+				if c.Revenue != tt.want[i] {
+					t.Errorf("component %d revenue = %d, want %d", c.ProductID, c.Revenue, tt.want[i])
+				}
+				if c.Revenue < 0 {
+					t.Errorf("component %d revenue = %d, want >= 0", c.ProductID, c.Revenue)
+				}
+				if want, _ := tt.components[i].Quantity.Mul(tt.bundleQty); c.Quantity != want {
+					t.Errorf("component %d quantity = %s, want %s", c.ProductID, c.Quantity, want)
+				}
+			}
+			if sum != tt.subtotal {
+				t.Errorf("revenues sum to %d, want bundle subtotal %d", sum, tt.subtotal)
+			}
+		})
+	}
+}
+
+// TestAllocateBundleRevenueSumsToSubtotal memeriksa invarian penjumlahan
+// untuk banyak kombinasi harga, termasuk komponen berharga nol.
+func TestAllocateBundleRevenueSumsToSubtotal(t *testing.T) {
+	prices := []int{0, 1, 999, 3500, 12345}
+	for _, a := range prices {
+		for _, b := range prices {
+			for _, c := range prices {
+				components := []bundleComponentSale{
+					{ProductID: 1, Quantity: 1000, Price: a},
+					{ProductID: 2, Quantity: 2500, Price: b},
+					{ProductID: 3, Quantity: 333, Price: c},
+				}
+				for _, subtotal := range []int{0, 1, 7, 9999, 1234567} {
+					sum := 0
+					allocations, err := allocateBundleRevenue(components, 1000, subtotal)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, r := range allocations {
+						if r.Revenue < 0 {
+							t.Errorf("prices %d/%d/%d subtotal %d: negative revenue %d", a, b, c, subtotal, r.Revenue)
+						}
+						sum += r.Revenue
+					}
+					if sum != subtotal {
+						t.Errorf("prices %d/%d/%d: revenues sum to %d, want %d", a, b, c, sum, subtotal)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBestPriceTier(t *testing.T) {
+	warung, reseller := 1, 2
+	general := func(id int, minQty models.Quantity, price int) models.PriceTier {
+		return models.PriceTier{ID: id, MinQuantity: minQty, Price: price}
+	}
+	group := func(id, groupID int, minQty models.Quantity, price int) models.PriceTier {
+		return models.PriceTier{ID: id, CustomerGroupID: &groupID, MinQuantity: minQty, Price: price}
+	}
+
+	tiers := []models.PriceTier{
+		general(1, 12000, 3200),
+		general(2, 48000, 3000),
+		group(3, warung, 12000, 3100),
+		group(4, warung, 48000, 3000),
+		group(5, reseller, 6000, 2900),
+	}
+
+	tests := []struct {
+		name     string
+		tiers    []models.PriceTier
+		baseQty  models.Quantity
+		groupID  *int
+		wantTier int // 0 berarti tidak ada tier
+	}{
+		{name: "below every threshold", tiers: tiers, baseQty: 11000},
+		{name: "just under threshold", tiers: tiers, baseQty: 11999},
+		{name: "exactly at threshold", tiers: tiers, baseQty: 12000, wantTier: 1},
+		{name: "between thresholds", tiers: tiers, baseQty: 47999, wantTier: 1},
+		{name: "highest general tier", tiers: tiers, baseQty: 100000, wantTier: 2},
+		{name: "group tier cheaper than general", tiers: tiers, baseQty: 12000, groupID: &warung, wantTier: 3},
+		{name: "group tier ties general tier", tiers: tiers, baseQty: 48000, groupID: &warung, wantTier: 4},
+		{name: "other group's tier ignored", tiers: tiers, baseQty: 6000, groupID: &warung},
+		{name: "group tier without general tier", tiers: tiers, baseQty: 6000, groupID: &reseller, wantTier: 5},
+		{name: "group tier ignored without group", tiers: tiers, baseQty: 6000},
+		{name: "cheapest applicable tier wins", tiers: tiers, baseQty: 48000, groupID: &reseller, wantTier: 5},
+		{
+			name: "cheaper general tier beats more expensive group tier",
+			tiers: []models.PriceTier{
+				group(1, warung, 10000, 3500),
+				general(2, 10000, 3400),
+			},
+			baseQty: 10000, groupID: &warung, wantTier: 2,
+		},
+		{
+			name: "tie between group tier and general tier regardless of order",
+			tiers: []models.PriceTier{
+				group(1, warung, 12000, 3000),
+				general(2, 24000, 3000),
+			},
+			baseQty: 24000, groupID: &warung, wantTier: 1,
+		},
+		{
+			name: "tie between general tiers prefers larger min quantity",
+			tiers: []models.PriceTier{
+				general(1, 24000, 3000),
+				general(2, 12000, 3000),
+			},
+			baseQty: 30000, wantTier: 1,
+		},
+		{
+			name:    "fractional threshold",
+			tiers:   []models.PriceTier{general(1, 2500, 11000)},
+			baseQty: 2499,
+		},
+		{name: "no tiers", tiers: nil, baseQty: 100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bestPriceTier(tt.tiers, tt.baseQty, tt.groupID)
+			gotID := 0
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.wantTier {
+				t.Errorf("bestPriceTier(qty %s) = tier %d, want tier %d", tt.baseQty, gotID, tt.wantTier)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+// Default analisis keranjang: pasangan harus muncul minimal di 2 transaksi
+// supaya kebetulan sekali beli tidak ikut tampil.
+const (
+	defaultBasketMinTransactions = 2
+	defaultAssociationLimit      = 10
+	defaultProductPairsLimit     = 50
+	maxBasketWindowDays          = 3650
+)
+
+type BasketService struct {
+	repo       *repositories.BasketRepository
+	location   *time.Location
+	windowDays int
+}
+
+// NewBasketService membuat service analisis keranjang dengan jendela
+// windowDays hari terakhir di zona waktu location.
+func NewBasketService(repo *repositories.BasketRepository, location *time.Location, windowDays int) (*BasketService, error) {
+	if windowDays < 1 || windowDays > maxBasketWindowDays {
+		return nil, fmt.Errorf("jendela analisis harus antara 1 dan %d hari", maxBasketWindowDays)
+	}
+	return &BasketService{repo: repo, location: location, windowDays: windowDays}, nil
+}
+
+// Refresh menghitung ulang analisis keranjang dari transaksi windowDays hari
+// penuh terakhir, tidak termasuk hari ini.
+func (s *BasketService) Refresh() (*models.BasketAnalysis, error) {
+	today := storeToday(s.location)
+	start, end := storeDayBounds(s.location, today.AddDate(0, 0, -s.windowDays), today.AddDate(0, 0, -1))
+	return s.repo.Rebuild(start, end)
+}
+
+// RunOnce menghitung ulang analisis jika hasil terakhir belum mencakup
+// kemarin, sehingga analisis diperbarui sekali sehari setelah tengah malam.
+func (s *BasketService) RunOnce() error {
+	analysis, err := s.repo.GetAnalysis()
+	if err != nil {
+		return err
+	}
+	today := storeToday(s.location)
+	todayStart, _ := storeDayBounds(s.location, today, today)
+	if analysis != nil && !analysis.WindowEnd.Before(todayStart) {
+		return nil
+	}
+
+	analysis, err = s.Refresh()
+	if err != nil {
+		return err
+	}
+	log.Printf("analisis keranjang diperbarui: %d transaksi", analysis.Transactions)
+	return nil
+}
+
+// RunScheduler menjalankan RunOnce setiap interval sampai ctx selesai,
+// dimulai saat server start.
+func (s *BasketService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(); err != nil {
+			log.Println("gagal memperbarui analisis keranjang:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetFrequentlyBoughtWith mengembalikan produk yang paling sering dibeli
+// bersama productID menurut analisis terakhir. limit dan minTransactions 0
+// memakai nilai default.
+func (s *BasketService) GetFrequentlyBoughtWith(productID, limit, minTransactions int) (*models.FrequentlyBoughtWith, error) {
+	limit, minTransactions, err := basketParams(limit, minTransactions, defaultAssociationLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	name, transactions, err := s.repo.GetProductTransactions(productID)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := s.repo.GetAnalysis()
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetAssociations(productID, minTransactions, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FrequentlyBoughtWith{
+		ProductID:    productID,
+		ProductName:  name,
+		Transactions: transactions,
+		Analysis:     analysis,
+		Items:        items,
+	}, nil
+}
+
+// GetProductPairs mengembalikan pasangan produk yang paling sering dibeli
+// bersamaan menurut by (support atau lift). by kosong, limit 0 dan
+// minTransactions 0 memakai nilai default.
+func (s *BasketService) GetProductPairs(categoryID int, by string, limit, minTransactions int) (*models.ProductPairsReport, error) {
+	if by == "" {
+		by = models.BasketBySupport
+	}
+	if by != models.BasketBySupport && by != models.BasketByLift {
+		return nil, invalidParam("by harus support atau lift")
+	}
+	limit, minTransactions, err := basketParams(limit, minTransactions, defaultProductPairsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := s.repo.GetAnalysis()
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := s.repo.GetPairs(categoryID, by, minTransactions, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProductPairsReport{
+		By:              by,
+		MinTransactions: minTransactions,
+		Analysis:        analysis,
+		Items:           pairs,
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	return report, nil
+}
+
+func basketParams(limit, minTransactions, defaultLimit int) (int, int, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if minTransactions == 0 {
+		minTransactions = defaultBasketMinTransactions
+	}
+	if limit < 1 || limit > maxRankingLimit {
+		return 0, 0, invalidParamf("limit harus antara 1 dan %d", maxRankingLimit)
+	}
+	if minTransactions < 1 {
+		return 0, 0, invalidParam("min_transactions harus lebih dari 0")
+	}
+	return limit, minTransactions, nil
+}
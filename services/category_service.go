@@ -1,6 +1,10 @@
 package services
 
 import (
+	"errors"
+	"sort"
+	"strings"
+
 	"kasir-api/models"
 	"kasir-api/repositories"
 )
@@ -17,7 +21,49 @@ func (s *CategoryService) GetAll() ([]models.Category, error) {
 	return s.repo.GetAll()
 }
 
+// GetTree mengembalikan kategori dalam bentuk pohon (root di level teratas,
+// sub-kategori di Children), diurutkan berdasarkan nama.
+func (s *CategoryService) GetTree() ([]models.Category, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	byParent := make(map[int][]models.Category)
+	for _, c := range categories {
+		parentID := 0
+		if c.ParentID != nil {
+			parentID = *c.ParentID
+		}
+		c.Path = nil
+		byParent[parentID] = append(byParent[parentID], c)
+	}
+
+	var build func(parentID int) []models.Category
+	build = func(parentID int) []models.Category {
+		nodes := byParent[parentID]
+		for i := range nodes {
+			nodes[i].Children = build(nodes[i].ID)
+		}
+		return nodes
+	}
+
+	tree := build(0)
+	if tree == nil {
+		tree = make([]models.Category, 0)
+	}
+	return tree, nil
+}
+
 func (s *CategoryService) Create(data *models.Category) error {
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		return errors.New("nama kategori wajib diisi")
+	}
 	return s.repo.Create(data)
 }
 
@@ -26,9 +72,33 @@ func (s *CategoryService) GetByID(id int) (*models.Category, error) {
 }
 
 func (s *CategoryService) Update(category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return errors.New("nama kategori wajib diisi")
+	}
 	return s.repo.Update(category)
 }
 
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+// Delete menghapus kategori dengan strategy reject (default), reassign (ke
+// kategori targetID) atau detach, dan mengembalikan jumlah produk terdampak.
+// Kesalahan parameter dikembalikan sebagai ValidationError.
+func (s *CategoryService) Delete(id int, strategy string, targetID int) (int, error) {
+	switch strategy {
+	case "":
+		strategy = models.CategoryDeleteReject
+	case models.CategoryDeleteReject, models.CategoryDeleteDetach:
+	case models.CategoryDeleteReassign:
+		if targetID <= 0 {
+			return 0, invalidParam("parameter to wajib diisi untuk strategy reassign")
+		}
+	default:
+		return 0, invalidParam("strategy harus reject, reassign atau detach")
+	}
+
+	affected, err := s.repo.Delete(id, strategy, targetID)
+	var invalidTarget *repositories.InvalidCategoryParentError
+	if errors.As(err, &invalidTarget) {
+		return 0, invalidParam(invalidTarget.Message)
+	}
+	return affected, err
 }
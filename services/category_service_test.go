@@ -0,0 +1,28 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+// Parameter yang salah harus ditolak sebagai ValidationError sebelum
+// repository disentuh, supaya handler membalas 400 dan bukan 500.
+func TestCategoryDeleteValidation(t *testing.T) {
+	s := NewCategoryService(nil)
+	tests := []struct {
+		name     string
+		strategy string
+		targetID int
+	}{
+		{"unknown strategy", "merge", 0},
+		{"reassign without to", "reassign", 0},
+		{"reassign with negative to", "reassign", -1},
+	}
+	for _, tt := range tests {
+		_, err := s.Delete(1, tt.strategy, tt.targetID)
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: err = %v, want ValidationError", tt.name, err)
+		}
+	}
+}
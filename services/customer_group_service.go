@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type CustomerGroupService struct {
+	repo *repositories.CustomerGroupRepository
+}
+
+func NewCustomerGroupService(repo *repositories.CustomerGroupRepository) *CustomerGroupService {
+	return &CustomerGroupService{repo: repo}
+}
+
+func (s *CustomerGroupService) GetAll() ([]models.CustomerGroup, error) {
+	return s.repo.GetAll()
+}
+
+func (s *CustomerGroupService) Create(data *models.CustomerGroup) error {
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		return errors.New("nama customer group wajib diisi")
+	}
+	return s.repo.Create(data)
+}
+
+func (s *CustomerGroupService) GetByID(id int) (*models.CustomerGroup, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *CustomerGroupService) Update(group *models.CustomerGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return errors.New("nama customer group wajib diisi")
+	}
+	return s.repo.Update(group)
+}
+
+func (s *CustomerGroupService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type CustomerService struct {
+	repo *repositories.CustomerRepository
+}
+
+func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService {
+	return &CustomerService{repo: repo}
+}
+
+func (s *CustomerService) GetAll(search string) ([]models.Customer, error) {
+	return s.repo.GetAll(strings.TrimSpace(search))
+}
+
+func (s *CustomerService) GetByID(id int) (*models.Customer, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *CustomerService) Create(customer *models.Customer) error {
+	if err := normalizeCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Create(customer)
+}
+
+func (s *CustomerService) Update(customer *models.Customer) error {
+	if err := normalizeCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Update(customer)
+}
+
+func (s *CustomerService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// normalizeCustomer merapikan input pelanggan. Nomor telepon disimpan hanya
+// angka dengan awalan 62 (format WhatsApp), jadi "0812-3456 789" dan
+// "+62 812 3456 789" dianggap nomor yang sama.
+func normalizeCustomer(customer *models.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = strings.TrimSpace(customer.Email)
+	if customer.Name == "" {
+		return errors.New("nama pelanggan wajib diisi")
+	}
+
+	var digits strings.Builder
+	for _, r := range customer.Phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	phone := digits.String()
+	if strings.HasPrefix(phone, "0") {
+		phone = "62" + phone[1:]
+	}
+	if phone != "" && len(phone) < 8 {
+		return errors.New("nomor telepon tidak valid")
+	}
+	customer.Phone = phone
+	return nil
+}
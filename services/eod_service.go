@@ -0,0 +1,413 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"kasir-api/delivery"
+	"kasir-api/export"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+const (
+	eodTopProductsLimit = 10
+	// eodExpiringDays adalah batas lot yang dianggap segera kedaluwarsa.
+	eodExpiringDays = 7
+	// eodMaxCatchUpDays membatasi laporan yang dibuat menyusul setelah server
+	// lama mati.
+	eodMaxCatchUpDays = 31
+	// Pengiriman yang gagal dicoba ulang setiap putaran scheduler, paling
+	// banyak eodMaxDeliveryAttempts kali dan hanya untuk laporan yang dibuat
+	// dalam eodDeliveryRetryWindow terakhir.
+	eodMaxDeliveryAttempts = 5
+	eodDeliveryRetryWindow = 7 * 24 * time.Hour
+	eodSendTimeout         = time.Minute
+)
+
+// eodReportStore adalah method EODReportRepository yang dipakai EODService.
+type eodReportStore interface {
+	GetSales(start, end time.Time) (models.EODSales, error)
+	GetTopProducts(start, end time.Time, limit int) ([]models.ProductSalesRanked, error)
+	GetOutOfStock() ([]models.OutOfStockProduct, error)
+	GetLatest() (*models.EODReportSummary, error)
+	Create(report *models.EODReport) (bool, error)
+	GetByDate(date string) (*models.EODReport, error)
+	List(start, end string) ([]models.EODReportSummary, error)
+	ListGeneratedSince(since time.Time) ([]models.EODReportSummary, error)
+	RecordDelivery(reportID int, channel string, sendErr error) error
+}
+
+type EODService struct {
+	repo         eodReportStore
+	reportRepo   *repositories.ReportRepository
+	stockLotRepo *repositories.StockLotRepository
+	channels     []delivery.Channel
+	location     *time.Location
+	storeName    string
+	closingHour  int
+	closingMin   int
+}
+
+// NewEODService membuat service laporan tutup hari. closingTime (HH:MM,
+// waktu toko) adalah jam tutup: laporan sebuah tanggal bisnis dibuat setelah
+// jam tersebut. Jam sebelum 12:00 dianggap lewat tengah malam, jadi "02:00"
+// menutup tanggal bisnis kemarin dan "00:00" berarti hari kalender penuh.
+func NewEODService(repo *repositories.EODReportRepository, reportRepo *repositories.ReportRepository, stockLotRepo *repositories.StockLotRepository,
+	channels []delivery.Channel, location *time.Location, storeName, closingTime string) (*EODService, error) {
+	closing, err := time.Parse("15:04", closingTime)
+	if err != nil {
+		return nil, fmt.Errorf("jam tutup %q tidak valid, gunakan HH:MM", closingTime)
+	}
+	return &EODService{
+		repo:         repo,
+		reportRepo:   reportRepo,
+		stockLotRepo: stockLotRepo,
+		channels:     channels,
+		location:     location,
+		storeName:    storeName,
+		closingHour:  closing.Hour(),
+		closingMin:   closing.Minute(),
+	}, nil
+}
+
+// closingAt mengembalikan waktu tutup tanggal bisnis date, yaitu akhir
+// periode laporannya.
+func (s *EODService) closingAt(date time.Time) time.Time {
+	day := date.Day()
+	if s.closingHour < 12 {
+		day++
+	}
+	return time.Date(date.Year(), date.Month(), day, s.closingHour, s.closingMin, 0, 0, s.location)
+}
+
+// lastClosedDate mengembalikan tanggal bisnis terakhir yang jam tutupnya
+// sudah lewat pada waktu now.
+func (s *EODService) lastClosedDate(now time.Time) time.Time {
+	y, m, d := now.In(s.location).Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for s.closingAt(date).After(now) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// GenerateDue membuat dan mengarsipkan laporan untuk setiap tanggal bisnis
+// yang sudah tutup tapi belum punya laporan, termasuk yang terlewat saat
+// server mati (paling banyak eodMaxCatchUpDays hari). Periode laporan
+// dimulai tepat di akhir periode laporan hari sebelumnya, sehingga tidak ada
+// transaksi yang terlewat walaupun jam tutup diubah.
+func (s *EODService) GenerateDue(now time.Time) ([]*models.EODReport, error) {
+	target := s.lastClosedDate(now)
+	previous, err := s.repo.GetLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	date := target
+	if previous != nil {
+		last, err := time.Parse("2006-01-02", previous.BusinessDate)
+		if err != nil {
+			return nil, err
+		}
+		if !last.Before(target) {
+			return nil, nil
+		}
+		date = last.AddDate(0, 0, 1)
+		if earliest := target.AddDate(0, 0, -(eodMaxCatchUpDays - 1)); date.Before(earliest) {
+			date = earliest
+		}
+	}
+
+	generated := make([]*models.EODReport, 0)
+	for ; !date.After(target); date = date.AddDate(0, 0, 1) {
+		previousDate := date.AddDate(0, 0, -1)
+		start := s.closingAt(previousDate)
+		if previous != nil && previous.BusinessDate == previousDate.Format("2006-01-02") {
+			start = previous.PeriodEnd
+		}
+
+		report, err := s.build(date, start, s.closingAt(date), now)
+		if err != nil {
+			return generated, err
+		}
+		created, err := s.repo.Create(report)
+		if err != nil {
+			return generated, err
+		}
+		if created {
+			generated = append(generated, report)
+		}
+		previous = &models.EODReportSummary{BusinessDate: report.BusinessDate, PeriodEnd: report.PeriodEnd}
+	}
+	return generated, nil
+}
+
+// build menyusun laporan tanggal bisnis date untuk periode [start, end).
+// Peringatan stok menggambarkan kondisi saat laporan dibuat.
+func (s *EODService) build(date, start, end, now time.Time) (*models.EODReport, error) {
+	sales, err := s.repo.GetSales(start, end)
+	if err != nil {
+		return nil, err
+	}
+	sales.AverageBasket = averageBasket(models.SalesBreakdownTotals{Revenue: sales.Revenue, Transactions: sales.Transactions})
+	sales.GrossProfit = sales.Revenue - sales.Cost
+
+	categories, err := s.reportRepo.GetCategorySales(start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	var revenue int64
+	var quantity models.Quantity
+	for _, c := range categories {
+		revenue += int64(c.Revenue)
+		quantity += c.Quantity
+	}
+	for i := range categories {
+		categories[i].RevenueShare = sharePercent(int64(categories[i].Revenue), revenue)
+		categories[i].QuantityShare = sharePercent(int64(categories[i].Quantity), int64(quantity))
+	}
+
+	topProducts, err := s.repo.GetTopProducts(start, end, eodTopProductsLimit)
+	if err != nil {
+		return nil, err
+	}
+	outOfStock, err := s.repo.GetOutOfStock()
+	if err != nil {
+		return nil, err
+	}
+	today := storeToday(s.location)
+	expiring, err := s.stockLotRepo.GetExpiring(today, today.AddDate(0, 0, eodExpiringDays))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EODReport{
+		BusinessDate: date.Format("2006-01-02"),
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		GeneratedAt:  now.In(s.location).Truncate(time.Second),
+		Timezone:     s.location.String(),
+		StoreName:    s.storeName,
+		Sales:        sales,
+		Categories:   categories,
+		TopProducts:  topProducts,
+		StockAlerts: models.EODStockAlerts{
+			OutOfStock:   outOfStock,
+			ExpiringLots: expiring,
+		},
+	}, nil
+}
+
+// DeliverPending mengirim laporan terbaru ke setiap channel yang belum
+// berhasil menerimanya. Setiap percobaan dicatat.
+func (s *EODService) DeliverPending(ctx context.Context) error {
+	if len(s.channels) == 0 {
+		return nil
+	}
+	reports, err := s.repo.ListGeneratedSince(time.Now().Add(-eodDeliveryRetryWindow))
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range reports {
+		pending := make([]delivery.Channel, 0)
+		for _, ch := range s.channels {
+			if needsDelivery(summary.Deliveries, ch.Name()) {
+				pending = append(pending, ch)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		report, err := s.repo.GetByDate(summary.BusinessDate)
+		if err != nil {
+			return err
+		}
+		report.Deliveries = nil
+		msg, err := s.message(report)
+		if err != nil {
+			return err
+		}
+
+		for _, ch := range pending {
+			sendCtx, cancel := context.WithTimeout(ctx, eodSendTimeout)
+			sendErr := ch.Send(sendCtx, msg)
+			cancel()
+			if sendErr != nil {
+				log.Printf("gagal mengirim laporan tutup hari %s lewat %s: %v", report.BusinessDate, ch.Name(), sendErr)
+			}
+			if err := s.repo.RecordDelivery(report.ID, ch.Name(), sendErr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// needsDelivery melaporkan apakah channel masih perlu dicoba: belum pernah
+// terkirim dan jatah percobaan belum habis.
+func needsDelivery(deliveries []models.EODDelivery, channel string) bool {
+	for _, d := range deliveries {
+		if d.Channel == channel {
+			return d.Status != models.DeliveryStatusSent && d.Attempts < eodMaxDeliveryAttempts
+		}
+	}
+	return true
+}
+
+// message menyusun pesan pengiriman: ringkasan teks, lampiran PDF dan JSON,
+// serta body webhook.
+func (s *EODService) message(report *models.EODReport) (*delivery.Message, error) {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]any{"event": "eod_report", "report": report})
+	if err != nil {
+		return nil, err
+	}
+	var pdf bytes.Buffer
+	if err := export.WritePDF(&pdf, EODExportReport(report)); err != nil {
+		return nil, err
+	}
+
+	name := "laporan-tutup-hari-" + report.BusinessDate
+	return &delivery.Message{
+		Subject: fmt.Sprintf("Laporan Tutup Hari %s %s", report.StoreName, report.BusinessDate),
+		Text:    eodText(report),
+		JSON:    payload,
+		Attachments: []delivery.Attachment{
+			{Filename: name + ".pdf", ContentType: export.ContentType(export.FormatPDF), Data: pdf.Bytes()},
+			{Filename: name + ".json", ContentType: "application/json", Data: reportJSON},
+		},
+	}, nil
+}
+
+// eodText adalah ringkasan laporan untuk isi email.
+func eodText(report *models.EODReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Laporan tutup hari %s\n", report.StoreName)
+	fmt.Fprintf(&b, "Tanggal bisnis: %s\n", report.BusinessDate)
+	fmt.Fprintf(&b, "Periode: %s s/d %s (%s)\n\n", report.PeriodStart.Format("02-01-2006 15:04"), report.PeriodEnd.Format("02-01-2006 15:04"), report.Timezone)
+	fmt.Fprintf(&b, "Total revenue: %s\n", export.FormatRupiah(int64(report.Sales.Revenue)))
+	fmt.Fprintf(&b, "Transaksi: %d\n", report.Sales.Transactions)
+	fmt.Fprintf(&b, "Rata-rata keranjang: %s\n", export.FormatRupiah(int64(report.Sales.AverageBasket)))
+	fmt.Fprintf(&b, "Item terjual: %s\n", report.Sales.ItemsSold)
+	fmt.Fprintf(&b, "Laba kotor: %s\n", export.FormatRupiah(int64(report.Sales.GrossProfit)))
+	if len(report.TopProducts) > 0 {
+		b.WriteString("\nProduk terlaris:\n")
+		for _, p := range report.TopProducts {
+			fmt.Fprintf(&b, "%d. %s - %s (%s)\n", p.Rank, p.ProductName, export.FormatRupiah(int64(p.Revenue)), p.Quantity)
+		}
+	}
+	fmt.Fprintf(&b, "\nStok habis: %d produk\n", len(report.StockAlerts.OutOfStock))
+	fmt.Fprintf(&b, "Lot kedaluwarsa dalam %d hari: %d lot\n", eodExpiringDays, len(report.StockAlerts.ExpiringLots))
+	b.WriteString("\nDetail lengkap ada di lampiran PDF dan JSON.\n")
+	return b.String()
+}
+
+// EODExportReport mengubah laporan tutup hari menjadi tabel untuk file
+// ekspor. Header memakai data arsip, termasuk waktu laporan dibuat.
+func EODExportReport(report *models.EODReport) *export.Report {
+	quantity := func(q models.Quantity) export.Cell {
+		return export.Number(q.Float64(), q.String())
+	}
+	empty := export.Text("")
+
+	rows := [][]export.Cell{
+		{export.Text("Penjualan"), export.Text("Total Revenue"), empty, export.Money(report.Sales.Revenue)},
+		{export.Text("Penjualan"), export.Text("Transaksi"), empty, export.Int(report.Sales.Transactions)},
+		{export.Text("Penjualan"), export.Text("Rata-rata Keranjang"), empty, export.Money(report.Sales.AverageBasket)},
+		{export.Text("Penjualan"), export.Text("Item Terjual"), quantity(report.Sales.ItemsSold), empty},
+		{export.Text("Penjualan"), export.Text("HPP"), empty, export.Money(report.Sales.Cost)},
+		{export.Text("Penjualan"), export.Text("Laba Kotor"), empty, export.Money(report.Sales.GrossProfit)},
+	}
+	for _, c := range report.Categories {
+		rows = append(rows, []export.Cell{export.Text("Kategori"), export.Text(c.CategoryName), quantity(c.Quantity), export.Money(c.Revenue)})
+	}
+	for _, p := range report.TopProducts {
+		rows = append(rows, []export.Cell{export.Text("Produk Terlaris"), export.Text(fmt.Sprintf("%d. %s", p.Rank, p.ProductName)), quantity(p.Quantity), export.Money(p.Revenue)})
+	}
+	for _, p := range report.StockAlerts.OutOfStock {
+		rows = append(rows, []export.Cell{export.Text("Stok Habis"), export.Text(p.ProductName), quantity(p.Stock), empty})
+	}
+	for _, l := range report.StockAlerts.ExpiringLots {
+		label := fmt.Sprintf("%s lot %s", l.ProductName, l.LotNumber)
+		if l.ExpiryDate != nil {
+			label += " (" + *l.ExpiryDate + ")"
+		}
+		rows = append(rows, []export.Cell{export.Text("Lot Kedaluwarsa"), export.Text(label), quantity(l.Quantity), export.Money(l.CostValue)})
+	}
+
+	return &export.Report{
+		Title: "Laporan Tutup Hari",
+		Fields: []export.Field{
+			{Label: "Toko", Value: report.StoreName},
+			{Label: "Tanggal bisnis", Value: report.BusinessDate},
+			{Label: "Periode", Value: report.PeriodStart.Format("02-01-2006 15:04") + " s/d " + report.PeriodEnd.Format("02-01-2006 15:04")},
+			{Label: "Zona waktu", Value: report.Timezone},
+			{Label: "Dibuat", Value: report.GeneratedAt.Format("02-01-2006 15:04 MST")},
+		},
+		Columns: []string{"Bagian", "Keterangan", "Qty", "Nilai"},
+		Rows:    rows,
+	}
+}
+
+// RunOnce membuat laporan yang sudah jatuh tempo lalu mengirim laporan yang
+// belum terkirim.
+func (s *EODService) RunOnce(ctx context.Context) error {
+	generated, err := s.GenerateDue(time.Now())
+	for _, r := range generated {
+		log.Printf("laporan tutup hari %s dibuat (revenue %d, %d transaksi)", r.BusinessDate, r.Sales.Revenue, r.Sales.Transactions)
+	}
+	if err != nil {
+		return err
+	}
+	return s.DeliverPending(ctx)
+}
+
+// RunScheduler menjalankan RunOnce setiap interval sampai ctx selesai.
+// Dijalankan sekali di awal supaya laporan yang terlewat saat server mati
+// langsung dibuat.
+func (s *EODService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			log.Println("gagal memproses laporan tutup hari:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetReports mengembalikan daftar arsip laporan dengan tanggal bisnis
+// startDate sampai endDate.
+func (s *EODService) GetReports(startDate, endDate time.Time) ([]models.EODReportSummary, error) {
+	if endDate.Before(startDate) {
+		return nil, invalidParam("end_date tidak boleh sebelum start_date")
+	}
+	return s.repo.List(startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+}
+
+// GetReport mengembalikan laporan arsip tanggal bisnis date.
+func (s *EODService) GetReport(date time.Time) (*models.EODReport, error) {
+	return s.repo.GetByDate(date.Format("2006-01-02"))
+}
+
+// Today mengembalikan tanggal hari ini di zona waktu toko.
+func (s *EODService) Today() time.Time {
+	return storeToday(s.location)
+}
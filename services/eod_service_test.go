@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kasir-api/delivery"
+	"kasir-api/models"
+)
+
+// fakeEODStore menyimpan laporan dan riwayat pengiriman di memori. Method
+// yang tidak dipakai DeliverPending tidak diimplementasikan.
+type fakeEODStore struct {
+	eodReportStore
+	reports    []models.EODReport
+	deliveries map[int][]models.EODDelivery
+	since      time.Time
+}
+
+func (f *fakeEODStore) ListGeneratedSince(since time.Time) ([]models.EODReportSummary, error) {
+	f.since = since
+	var result []models.EODReportSummary
+	for _, r := range f.reports {
+		if r.GeneratedAt.Before(since) {
+			continue
+		}
+		result = append(result, models.EODReportSummary{
+			ID:           r.ID,
+			BusinessDate: r.BusinessDate,
+			GeneratedAt:  r.GeneratedAt,
+			Deliveries:   f.deliveries[r.ID],
+		})
+	}
+	return result, nil
+}
+
+func (f *fakeEODStore) GetByDate(date string) (*models.EODReport, error) {
+	for _, r := range f.reports {
+		if r.BusinessDate == date {
+			report := r
+			report.Deliveries = f.deliveries[r.ID]
+			return &report, nil
+		}
+	}
+	return nil, errors.New("laporan tidak ditemukan")
+}
+
+// RecordDelivery meniru ringkasan repository: attempts adalah jumlah
+// percobaan dan status sent menang atas percobaan gagal.
+func (f *fakeEODStore) RecordDelivery(reportID int, channel string, sendErr error) error {
+	status := models.DeliveryStatusSent
+	if sendErr != nil {
+		status = models.DeliveryStatusFailed
+	}
+	deliveries := f.deliveries[reportID]
+	for i := range deliveries {
+		if deliveries[i].Channel == channel {
+			deliveries[i].Attempts++
+			if deliveries[i].Status != models.DeliveryStatusSent {
+				deliveries[i].Status = status
+			}
+			return nil
+		}
+	}
+	f.deliveries[reportID] = append(deliveries, models.EODDelivery{Channel: channel, Status: status, Attempts: 1})
+	return nil
+}
+
+// fakeChannel mencatat subject setiap pesan. Percobaan ke-n gagal
+// jika failures[n-1] bukan nil; setelah failures habis pengiriman berhasil.
+type fakeChannel struct {
+	name     string
+	failures []error
+	sent     []string
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Send(ctx context.Context, msg *delivery.Message) error {
+	c.sent = append(c.sent, msg.Subject)
+	if n := len(c.sent); n <= len(c.failures) {
+		return c.failures[n-1]
+	}
+	return nil
+}
+
+func alwaysFail(n int) []error {
+	failures := make([]error, n)
+	for i := range failures {
+		failures[i] = errors.New("connection refused")
+	}
+	return failures
+}
+
+func newTestEODService(t *testing.T, store *fakeEODStore, channels ...delivery.Channel) *EODService {
+	return &EODService{
+		repo:      store,
+		channels:  channels,
+		location:  mustLoadLocation(t, "Asia/Jakarta"),
+		storeName: "Toko Test",
+	}
+}
+
+func testEODReport(id int, date string, generatedAt time.Time) models.EODReport {
+	return models.EODReport{
+		ID:           id,
+		BusinessDate: date,
+		GeneratedAt:  generatedAt,
+		Timezone:     "Asia/Jakarta",
+		StoreName:    "Toko Test",
+	}
+}
+
+func TestDeliverPendingRetryCap(t *testing.T) {
+	store := &fakeEODStore{
+		reports:    []models.EODReport{testEODReport(1, "2026-10-18", time.Now().Add(-time.Hour))},
+		deliveries: make(map[int][]models.EODDelivery),
+	}
+	broken := &fakeChannel{name: "smtp", failures: alwaysFail(100)}
+	flaky := &fakeChannel{name: "webhook", failures: alwaysFail(2)}
+	ok := &fakeChannel{name: "file"}
+	s := newTestEODService(t, store, broken, flaky, ok)
+
+	// Satu putaran scheduler per iterasi, lebih banyak dari jatah percobaan
+	for i := 0; i < eodMaxDeliveryAttempts+3; i++ {
+		if err := s.DeliverPending(context.Background()); err != nil {
+			t.Fatalf("round %d: %v", i+1, err)
+		}
+	}
+
+	if got := len(broken.sent); got != 5 {
+		t.Errorf("failing channel sent %d times, want 5", got)
+	}
+	if got := len(flaky.sent); got != 3 {
+		t.Errorf("channel failing twice sent %d times, want 3", got)
+	}
+	if got := len(ok.sent); got != 1 {
+		t.Errorf("working channel sent %d times, want 1", got)
+	}
+
+	want := map[string]models.EODDelivery{
+		"smtp":    {Channel: "smtp", Status: models.DeliveryStatusFailed, Attempts: 5},
+		"webhook": {Channel: "webhook", Status: models.DeliveryStatusSent, Attempts: 3},
+		"file":    {Channel: "file", Status: models.DeliveryStatusSent, Attempts: 1},
+	}
+	for _, d := range store.deliveries[1] {
+		if d != want[d.Channel] {
+			t.Errorf("delivery %s = %+v, want %+v", d.Channel, d, want[d.Channel])
+		}
+	}
+	if len(store.deliveries[1]) != len(want) {
+		t.Errorf("recorded %d channels, want %d", len(store.deliveries[1]), len(want))
+	}
+}
+
+func TestDeliverPendingRetryWindow(t *testing.T) {
+	now := time.Now()
+	store := &fakeEODStore{
+		reports: []models.EODReport{
+			testEODReport(1, "2026-10-10", now.Add(-eodDeliveryRetryWindow-time.Hour)),
+			testEODReport(2, "2026-10-12", now.Add(-eodDeliveryRetryWindow+time.Hour)),
+		},
+		deliveries: make(map[int][]models.EODDelivery),
+	}
+	ch := &fakeChannel{name: "smtp"}
+	s := newTestEODService(t, store, ch)
+
+	before := time.Now()
+	if err := s.DeliverPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+
+	if store.since.Before(before.Add(-eodDeliveryRetryWindow)) || store.since.After(after.Add(-eodDeliveryRetryWindow)) {
+		t.Errorf("reports listed since %s, want 7 days before now", store.since)
+	}
+	if eodDeliveryRetryWindow != 7*24*time.Hour {
+		t.Errorf("retry window = %s, want 7 days", eodDeliveryRetryWindow)
+	}
+	if len(ch.sent) != 1 || ch.sent[0] != "Laporan Tutup Hari Toko Test 2026-10-12" {
+		t.Errorf("sent %q, want only the report generated within the window", ch.sent)
+	}
+	if _, ok := store.deliveries[1]; ok {
+		t.Error("report outside the retry window was delivered")
+	}
+}
+
+func TestDeliverPendingWithoutChannels(t *testing.T) {
+	// Tanpa channel, repository tidak perlu dibaca sama sekali
+	s := newTestEODService(t, &fakeEODStore{})
+	if err := s.DeliverPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
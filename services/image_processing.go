@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Registrasi decoder untuk image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// allowedImageTypes adalah content type yang bisa di-decode oleh standard
+// library sehingga thumbnail-nya bisa dibuat.
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// makeThumbnail mengecilkan gambar agar muat di dalam kotak maxSize x maxSize
+// dengan mempertahankan rasio, lalu meng-encode hasilnya sebagai JPEG.
+// Area transparan diisi warna putih.
+func makeThumbnail(src image.Image, maxSize int) ([]byte, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	tw, th := w, h
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			tw, th = maxSize, h*maxSize/w
+		} else {
+			tw, th = w*maxSize/h, maxSize
+		}
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	// Gabungkan dulu ke background putih supaya pixel transparan tidak jadi hitam di JPEG
+	flat := image.NewRGBA(b)
+	draw.Draw(flat, b, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, b, src, b.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	boxResize(dst, flat)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// boxResize mengisi dst dengan rata-rata pixel src yang tercakup oleh setiap
+// pixel tujuan (box filter). Cukup bagus untuk downscale thumbnail.
+func boxResize(dst, src *image.RGBA) {
+	sb := src.Bounds()
+	db := dst.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dw, dh := db.Dx(), db.Dy()
+
+	for y := 0; y < dh; y++ {
+		y0 := y * sh / dh
+		y1 := (y + 1) * sh / dh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			x0 := x * sw / dw
+			x1 := (x + 1) * sw / dw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				off := src.PixOffset(sb.Min.X+x0, sb.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[off])
+					g += uint32(src.Pix[off+1])
+					bl += uint32(src.Pix[off+2])
+					a += uint32(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+
+			doff := dst.PixOffset(db.Min.X+x, db.Min.Y+y)
+			dst.Pix[doff] = uint8(r / n)
+			dst.Pix[doff+1] = uint8(g / n)
+			dst.Pix[doff+2] = uint8(bl / n)
+			dst.Pix[doff+3] = uint8(a / n)
+		}
+	}
+}
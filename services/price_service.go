@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type PriceService struct {
+	repo        *repositories.PriceRepository
+	productRepo *repositories.ProductRepository
+}
+
+func NewPriceService(repo *repositories.PriceRepository, productRepo *repositories.ProductRepository) *PriceService {
+	return &PriceService{repo: repo, productRepo: productRepo}
+}
+
+func (s *PriceService) GetHistory(productID int) ([]models.PriceHistory, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetHistory(productID)
+}
+
+// GetSchedules mengembalikan jadwal harga. productID 0 berarti semua produk.
+func (s *PriceService) GetSchedules(productID int, status string) ([]models.PriceSchedule, error) {
+	switch status {
+	case "", models.PriceSchedulePending, models.PriceScheduleApplied, models.PriceScheduleCancelled:
+	default:
+		return nil, errors.New("status harus pending, applied atau cancelled")
+	}
+	if productID != 0 {
+		if _, err := s.productRepo.GetByID(productID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetSchedules(productID, status)
+}
+
+func (s *PriceService) Schedule(productID int, req models.PriceScheduleRequest, createdBy string) (*models.PriceSchedule, error) {
+	if req.NewPrice < 0 {
+		return nil, errors.New("new_price tidak boleh negatif")
+	}
+	if req.EffectiveAt.IsZero() {
+		return nil, errors.New("effective_at wajib diisi")
+	}
+	if !req.EffectiveAt.After(time.Now()) {
+		return nil, errors.New("effective_at harus di masa depan")
+	}
+
+	schedule := &models.PriceSchedule{
+		ProductID:   productID,
+		NewPrice:    req.NewPrice,
+		EffectiveAt: req.EffectiveAt,
+		CreatedBy:   optionalString(createdBy),
+	}
+	if err := s.repo.CreateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *PriceService) CancelSchedule(productID, scheduleID int) error {
+	return s.repo.CancelSchedule(productID, scheduleID)
+}
+
+// ApplyDue menerapkan jadwal harga yang sudah jatuh tempo.
+func (s *PriceService) ApplyDue() ([]models.PriceSchedule, error) {
+	return s.repo.ApplyDue(time.Now())
+}
+
+// RunScheduler menerapkan jadwal harga setiap interval sampai ctx selesai.
+// Dijalankan sekali di awal supaya jadwal yang terlewat saat server mati
+// langsung diterapkan.
+func (s *PriceService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := s.ApplyDue()
+		if err != nil {
+			log.Println("gagal menerapkan jadwal harga:", err)
+		}
+		for _, a := range applied {
+			log.Printf("harga %s (id %d) berubah menjadi %d sesuai jadwal id %d", a.ProductName, a.ProductID, a.NewPrice, a.ID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// optionalString mengubah string kosong menjadi nil untuk kolom nullable.
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
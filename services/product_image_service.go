@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/storage"
+)
+
+var (
+	ErrImageTooLarge        = errors.New("ukuran gambar melebihi batas")
+	ErrUnsupportedImageType = errors.New("tipe gambar tidak didukung (gunakan JPEG, PNG atau GIF)")
+)
+
+const (
+	thumbnailSize = 320
+	// maxImagePixels membatasi dimensi gambar supaya decode tidak menghabiskan memori
+	maxImagePixels = 40_000_000
+)
+
+type ProductImageService struct {
+	repo        *repositories.ProductImageRepository
+	productRepo *repositories.ProductRepository
+	storage     storage.Storage
+	baseURL     string
+	MaxSize     int64
+}
+
+// NewProductImageService membuat service gambar produk. baseURL adalah prefix
+// URL publik tempat gambar disajikan, misalnya "/images".
+func NewProductImageService(repo *repositories.ProductImageRepository, productRepo *repositories.ProductRepository, store storage.Storage, baseURL string, maxSize int64) *ProductImageService {
+	return &ProductImageService{
+		repo:        repo,
+		productRepo: productRepo,
+		storage:     store,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		MaxSize:     maxSize,
+	}
+}
+
+// Upload memvalidasi dan menyimpan gambar asli beserta thumbnail-nya.
+func (s *ProductImageService) Upload(ctx context.Context, productID int, data []byte) (*models.ProductImage, error) {
+	if int64(len(data)) > s.MaxSize {
+		return nil, ErrImageTooLarge
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	// Content type ditentukan dari isi file, bukan dari header yang dikirim client
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImageType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImageType
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: dimensi %dx%d terlalu besar", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImageType
+	}
+
+	thumb, err := makeThumbnail(img, thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("products/%d/%s%s", productID, name, ext)
+	thumbKey := fmt.Sprintf("products/%d/%s_thumb.jpg", productID, name)
+
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err != nil {
+		s.storage.Delete(ctx, key)
+		return nil, err
+	}
+
+	productImage := &models.ProductImage{
+		ProductID:    productID,
+		StorageKey:   key,
+		ThumbnailKey: thumbKey,
+		ContentType:  contentType,
+		SizeBytes:    len(data),
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+	}
+	if err := s.repo.Create(productImage); err != nil {
+		s.storage.Delete(ctx, key)
+		s.storage.Delete(ctx, thumbKey)
+		return nil, err
+	}
+
+	s.setURLs(productImage)
+	return productImage, nil
+}
+
+func (s *ProductImageService) GetByProductID(productID int) ([]models.ProductImage, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.GetByProductIDs([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := images[productID]
+	if result == nil {
+		result = make([]models.ProductImage, 0)
+	}
+	for i := range result {
+		s.setURLs(&result[i])
+	}
+	return result, nil
+}
+
+func (s *ProductImageService) Delete(ctx context.Context, productID, imageID int) error {
+	img, err := s.repo.GetByID(productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(img.ID); err != nil {
+		return err
+	}
+
+	// File dihapus setelah row DB supaya tidak ada URL yang menunjuk ke file hilang
+	if err := s.storage.Delete(ctx, img.StorageKey); err != nil {
+		return err
+	}
+	return s.storage.Delete(ctx, img.ThumbnailKey)
+}
+
+// DeleteFiles menghapus file gambar dan thumbnail dari storage. Error
+// diabaikan karena dipakai untuk pembersihan setelah row DB terhapus.
+func (s *ProductImageService) DeleteFiles(ctx context.Context, images []models.ProductImage) {
+	for _, img := range images {
+		s.storage.Delete(ctx, img.StorageKey)
+		s.storage.Delete(ctx, img.ThumbnailKey)
+	}
+}
+
+// Attach mengisi field Images untuk daftar produk dengan satu query.
+func (s *ProductImageService) Attach(products []models.Product) error {
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	images, err := s.repo.GetByProductIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+		for j := range products[i].Images {
+			s.setURLs(&products[i].Images[j])
+		}
+	}
+	return nil
+}
+
+// Open membuka file gambar dari storage untuk disajikan lewat HTTP.
+func (s *ProductImageService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.storage.Get(ctx, key)
+}
+
+func (s *ProductImageService) setURLs(img *models.ProductImage) {
+	img.URL = s.baseURL + "/" + img.StorageKey
+	img.ThumbnailURL = s.baseURL + "/" + img.ThumbnailKey
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,34 +1,272 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"kasir-api/models"
 	"kasir-api/repositories"
 )
 
 type ProductService struct {
-	repo *repositories.ProductRepository
+	repo         *repositories.ProductRepository
+	movementRepo *repositories.StockMovementRepository
+	imageService *ProductImageService
+}
+
+func NewProductService(repo *repositories.ProductRepository, movementRepo *repositories.StockMovementRepository, imageService *ProductImageService) *ProductService {
+	return &ProductService{repo: repo, movementRepo: movementRepo, imageService: imageService}
 }
 
-func NewProductService(repo *repositories.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+// GetAll mengembalikan produk. categoryID 0 berarti semua kategori; jika
+// diisi, produk di sub-kategorinya ikut dikembalikan.
+func (s *ProductService) GetAll(name string, categoryID int) ([]models.Product, error) {
+	products, err := s.repo.GetAll(name, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.imageService.Attach(products); err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
-func (s *ProductService) GetAll(name string) ([]models.Product, error) {
-	return s.repo.GetAll(name)
+// Search mencari produk dengan ranking relevansi. limit dibatasi 1-100.
+func (s *ProductService) Search(term string, limit int) ([]models.ProductSearchResult, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, errors.New("parameter q wajib diisi")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	results, err := s.repo.Search(term, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]models.Product, len(results))
+	for i := range results {
+		products[i] = results[i].Product
+	}
+	if err := s.imageService.Attach(products); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Images = products[i].Images
+	}
+	return results, nil
 }
 
-func (s *ProductService) Create(data *models.Product) error {
-	return s.repo.Create(data)
+// GetByCategory mengembalikan produk dalam kategori per halaman. page dimulai
+// dari 1 dan pageSize dibatasi 1-100 (default 20).
+func (s *ProductService) GetByCategory(categoryID int, includeSub bool, page, pageSize int) (*models.ProductPage, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	products, total, err := s.repo.GetByCategory(categoryID, includeSub, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.imageService.Attach(products); err != nil {
+		return nil, err
+	}
+
+	return &models.ProductPage{
+		Items:      products,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// Create menyimpan produk baru. changedBy (boleh kosong) dicatat di riwayat
+// harga.
+func (s *ProductService) Create(data *models.Product, changedBy string) error {
+	normalizeProductCodes(data)
+	if err := validateUnits(data); err != nil {
+		return err
+	}
+	if err := validatePriceTiers(data); err != nil {
+		return err
+	}
+	if err := validateBundle(data); err != nil {
+		return err
+	}
+	return s.repo.Create(data, optionalString(changedBy))
 }
 
 func (s *ProductService) GetByID(id int) (*models.Product, error) {
-	return s.repo.GetByID(id)
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []models.Product{*product}
+	if err := s.imageService.Attach(products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
-func (s *ProductService) Update(product *models.Product) error {
-	return s.repo.Update(product)
+// Update mengubah produk. Perubahan harga dicatat di riwayat harga atas nama
+// changedBy.
+func (s *ProductService) Update(product *models.Product, changedBy string) error {
+	normalizeProductCodes(product)
+	if err := validateUnits(product); err != nil {
+		return err
+	}
+	if err := validatePriceTiers(product); err != nil {
+		return err
+	}
+	if err := validateBundle(product); err != nil {
+		return err
+	}
+	return s.repo.Update(product, optionalString(changedBy))
 }
 
 func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+	images, err := s.imageService.GetByProductID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	// Row product_images ikut terhapus (ON DELETE CASCADE), file di storage dibersihkan di sini
+	s.imageService.DeleteFiles(context.Background(), images)
+	return nil
+}
+
+func (s *ProductService) GetStockMovements(id int) ([]models.StockMovement, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.movementRepo.GetByProductID(id)
+}
+
+// normalizeProductCodes mengubah SKU/barcode kosong menjadi NULL supaya tidak
+// bentrok dengan unique index.
+func normalizeProductCodes(product *models.Product) {
+	if product.SKU != nil {
+		if sku := strings.TrimSpace(*product.SKU); sku != "" {
+			product.SKU = &sku
+		} else {
+			product.SKU = nil
+		}
+	}
+	if product.Barcode != nil {
+		if barcode := strings.TrimSpace(*product.Barcode); barcode != "" {
+			product.Barcode = &barcode
+		} else {
+			product.Barcode = nil
+		}
+	}
+}
+
+// validateUnits mengisi unit dasar default ("pcs") dan memastikan unit jual
+// alternatif punya nama unik dan faktor konversi yang valid.
+func validateUnits(product *models.Product) error {
+	product.Unit = strings.TrimSpace(product.Unit)
+	if product.Unit == "" {
+		product.Unit = models.DefaultUnit
+	}
+	if product.Stock < 0 {
+		return errors.New("stock tidak boleh negatif")
+	}
+
+	seen := map[string]bool{strings.ToLower(product.Unit): true}
+	for i := range product.Units {
+		u := &product.Units[i]
+		u.Name = strings.TrimSpace(u.Name)
+		if u.Name == "" {
+			return errors.New("nama unit wajib diisi")
+		}
+		if seen[strings.ToLower(u.Name)] {
+			return fmt.Errorf("unit %s duplikat", u.Name)
+		}
+		seen[strings.ToLower(u.Name)] = true
+
+		if u.Factor <= 0 {
+			return fmt.Errorf("faktor unit %s harus lebih dari 0", u.Name)
+		}
+		if u.Price != nil && *u.Price < 0 {
+			return fmt.Errorf("harga unit %s tidak boleh negatif", u.Name)
+		}
+	}
+	return nil
+}
+
+// validatePriceTiers memastikan setiap tier punya min_quantity dan harga yang
+// valid dan tidak ada dua tier dengan min_quantity sama untuk pembeli yang sama.
+func validatePriceTiers(product *models.Product) error {
+	type tierKey struct {
+		groupID     int
+		minQuantity models.Quantity
+	}
+
+	seen := make(map[tierKey]bool)
+	for _, t := range product.PriceTiers {
+		if t.MinQuantity <= 0 {
+			return errors.New("min_quantity price tier harus lebih dari 0")
+		}
+		if t.Price < 0 {
+			return errors.New("harga price tier tidak boleh negatif")
+		}
+
+		key := tierKey{minQuantity: t.MinQuantity}
+		if t.CustomerGroupID != nil {
+			key.groupID = *t.CustomerGroupID
+		}
+		if seen[key] {
+			return fmt.Errorf("price tier dengan min_quantity %s duplikat", t.MinQuantity)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// validateBundle memastikan bundle punya komponen yang valid. Bundle tidak
+// menyimpan stok sendiri; ketersediaannya dihitung dari stok komponen.
+func validateBundle(product *models.Product) error {
+	if !product.IsBundle {
+		return nil
+	}
+	if product.TrackLots {
+		return errors.New("bundle tidak bisa dilacak per lot, lot dilacak di komponennya")
+	}
+	if len(product.Components) == 0 {
+		return errors.New("bundle harus memiliki minimal satu komponen")
+	}
+
+	seen := make(map[int]bool)
+	for _, c := range product.Components {
+		if c.Quantity <= 0 {
+			return fmt.Errorf("quantity komponen product id %d harus lebih dari 0", c.ComponentID)
+		}
+		if seen[c.ComponentID] {
+			return fmt.Errorf("komponen product id %d duplikat", c.ComponentID)
+		}
+		seen[c.ComponentID] = true
+	}
+
+	product.Stock = 0
+	return nil
 }
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type PurchaseOrderService struct {
+	repo *repositories.PurchaseOrderRepository
+}
+
+func NewPurchaseOrderService(repo *repositories.PurchaseOrderRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo}
+}
+
+func (s *PurchaseOrderService) GetAll(status string, supplierID int) ([]models.PurchaseOrder, error) {
+	return s.repo.GetAll(status, supplierID)
+}
+
+func (s *PurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Create(po *models.PurchaseOrder) error {
+	if err := validatePurchaseOrderItems(po.Items); err != nil {
+		return err
+	}
+	return s.repo.Create(po)
+}
+
+func (s *PurchaseOrderService) Update(po *models.PurchaseOrder) error {
+	if err := validatePurchaseOrderItems(po.Items); err != nil {
+		return err
+	}
+	return s.repo.Update(po)
+}
+
+func (s *PurchaseOrderService) Send(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.Send(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Receive(id int, items []models.ReceiveItem) (*models.PurchaseOrder, error) {
+	if len(items) == 0 {
+		return nil, errors.New("items tidak boleh kosong")
+	}
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity untuk item id %d harus lebih dari 0", item.ItemID)
+		}
+		if item.UnitCost != nil && *item.UnitCost < 0 {
+			return nil, fmt.Errorf("unit_cost untuk item id %d tidak boleh negatif", item.ItemID)
+		}
+		expiryDate, err := normalizeExpiryDate(item.ExpiryDate)
+		if err != nil {
+			return nil, err
+		}
+		items[i].ExpiryDate = expiryDate
+	}
+
+	if err := s.repo.Receive(id, items); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func validatePurchaseOrderItems(items []models.PurchaseOrderItem) error {
+	if len(items) == 0 {
+		return errors.New("items tidak boleh kosong")
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity untuk product id %d harus lebih dari 0", item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return fmt.Errorf("unit_cost untuk product id %d tidak boleh negatif", item.ProductID)
+		}
+	}
+	return nil
+}
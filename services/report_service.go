@@ -0,0 +1,1075 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+// ValidationError menandai parameter laporan yang tidak valid. Handler
+// memetakannya ke 400; error lain dianggap kegagalan internal.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalidParam(message string) error {
+	return &ValidationError{Message: message}
+}
+
+func invalidParamf(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// maxTimeSeriesBuckets membatasi jumlah bucket supaya rentang panjang dengan
+// granularity hour tidak menghasilkan respons raksasa.
+const maxTimeSeriesBuckets = 5000
+
+// Jumlah baris default dan maksimal laporan produk terlaris.
+const (
+	defaultRankingLimit = 20
+	maxRankingLimit     = 500
+)
+
+// Default laporan dead stock: periode 90 hari dan produk dengan sell-through
+// di bawah 10% dianggap lambat terjual.
+const (
+	defaultDeadStockDays          = 90
+	maxDeadStockDays              = 3650
+	defaultDeadStockSlowThreshold = 10
+)
+
+// Batas kumulatif default analisis ABC (persen): 80% nilai pertama kelas A,
+// 15% berikutnya kelas B.
+const (
+	defaultABCCutoffA = 80
+	defaultABCCutoffB = 95
+)
+
+// Default saran pemesanan ulang: pola mingguan dari 8 minggu terakhir,
+// pesanan menutup lead time ditambah 14 hari, dan lead time 7 hari untuk
+// produk tanpa riwayat PO. Safety stock memakai faktor 1,65 (service level
+// sekitar 95%) dikali simpangan baku permintaan harian dan akar lead time.
+const (
+	defaultReorderLookbackWeeks = 8
+	maxReorderLookbackWeeks     = 52
+	defaultReorderCoverageDays  = 14
+	defaultReorderLeadTimeDays  = 7
+	maxReorderDays              = 365
+	reorderSafetyFactor         = 1.65
+)
+
+// Default periode laporan RFM: pelanggan yang bertransaksi dalam setahun
+// terakhir.
+const (
+	defaultRFMDays = 365
+	maxRFMDays     = 3650
+)
+
+type ReportService struct {
+	repo     *repositories.ReportRepository
+	location *time.Location
+}
+
+// NewReportService membuat service laporan yang menghitung batas hari dan
+// bucket waktu dalam zona waktu toko.
+func NewReportService(repo *repositories.ReportRepository, location *time.Location) *ReportService {
+	return &ReportService{repo: repo, location: location}
+}
+
+// Location mengembalikan zona waktu toko.
+func (s *ReportService) Location() *time.Location {
+	return s.location
+}
+
+// Today mengembalikan tanggal hari ini di zona waktu toko.
+func (s *ReportService) Today() time.Time {
+	return storeToday(s.location)
+}
+
+// GetTimeSeries mengembalikan penjualan per bucket dari awal startDate sampai
+// akhir endDate (tanggal lokal toko), termasuk bucket kosong.
+func (s *ReportService) GetTimeSeries(startDate, endDate time.Time, granularity string) (*models.SalesTimeSeries, error) {
+	if granularity == "" {
+		granularity = models.GranularityDay
+	}
+	switch granularity {
+	case models.GranularityHour, models.GranularityDay, models.GranularityWeek, models.GranularityMonth:
+	default:
+		return nil, invalidParam("granularity harus hour, day, week atau month")
+	}
+	start, end, err := s.storeRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketStarts := make([]time.Time, 0)
+	for b := truncateToBucket(start, granularity); b.Before(end); b = nextBucket(b, granularity) {
+		bucketStarts = append(bucketStarts, b)
+		if len(bucketStarts) > maxTimeSeriesBuckets {
+			return nil, invalidParamf("rentang terlalu panjang untuk granularity %s (maksimal %d bucket)", granularity, maxTimeSeriesBuckets)
+		}
+	}
+
+	rows, err := s.repo.GetSalesBuckets(start, end, granularity, s.location.String())
+	if err != nil {
+		return nil, err
+	}
+
+	byStart := make(map[string]models.SalesBucket, len(rows))
+	for _, row := range rows {
+		byStart[salesBucketKey(row.Start, granularity)] = row
+	}
+
+	buckets := make([]models.SalesBucket, len(bucketStarts))
+	for i, b := range bucketStarts {
+		bucket := byStart[salesBucketKey(b, granularity)]
+		bucket.Start = b
+		if bucket.Transactions > 0 {
+			bucket.AverageBasket = (bucket.Revenue + bucket.Transactions/2) / bucket.Transactions
+		}
+		buckets[i] = bucket
+	}
+
+	return &models.SalesTimeSeries{
+		StartDate:   startDate.Format("2006-01-02"),
+		EndDate:     endDate.Format("2006-01-02"),
+		Granularity: granularity,
+		Timezone:    s.location.String(),
+		Buckets:     buckets,
+	}, nil
+}
+
+// salesBucketKey adalah kunci untuk mencocokkan bucket dari database dengan
+// bucket yang dibuat service. Bucket jam dari database berupa waktu absolut,
+// jadi dicocokkan dalam UTC; bucket hari ke atas berupa tanggal lokal tanpa
+// zona, jadi dicocokkan lewat tanggalnya.
+func salesBucketKey(t time.Time, granularity string) string {
+	if granularity == models.GranularityHour {
+		return t.UTC().Format("2006-01-02 15:04")
+	}
+	return t.Format("2006-01-02")
+}
+
+// GetProductRanking mengembalikan limit produk teratas (order top) atau
+// terbawah (order bottom) berdasarkan qty, revenue atau profit dalam rentang
+// tanggal lokal toko.
+func (s *ReportService) GetProductRanking(startDate, endDate time.Time, categoryID int, by, order string, limit int) (*models.ProductRankingReport, error) {
+	if by == "" {
+		by = models.ProductRankByQty
+	}
+	switch by {
+	case models.ProductRankByQty, models.ProductRankByRevenue, models.ProductRankByProfit:
+	default:
+		return nil, invalidParam("by harus qty, revenue atau profit")
+	}
+	if order == "" {
+		order = models.ProductRankTop
+	}
+	if order != models.ProductRankTop && order != models.ProductRankBottom {
+		return nil, invalidParam("order harus top atau bottom")
+	}
+	if limit == 0 {
+		limit = defaultRankingLimit
+	}
+	if limit < 0 || limit > maxRankingLimit {
+		return nil, invalidParamf("limit harus antara 1 dan %d", maxRankingLimit)
+	}
+
+	start, end, err := s.storeRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetProductRanking(start, end, categoryID, by, order == models.ProductRankBottom, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProductRankingReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		By:        by,
+		Order:     order,
+		Items:     items,
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	return report, nil
+}
+
+// GetCategorySales mengembalikan penjualan per kategori beserta share
+// terhadap total periode.
+func (s *ReportService) GetCategorySales(startDate, endDate time.Time, categoryID int) (*models.CategorySalesReport, error) {
+	start, end, err := s.storeRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.GetSalesTotals(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetCategorySales(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].RevenueShare = sharePercent(int64(items[i].Revenue), int64(totals.Revenue))
+		items[i].QuantityShare = sharePercent(int64(items[i].Quantity), int64(totals.Quantity))
+	}
+
+	report := &models.CategorySalesReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Totals:    totals,
+		Items:     items,
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	return report, nil
+}
+
+// GetProductSales mengembalikan penjualan per produk beserta share terhadap
+// total periode.
+func (s *ReportService) GetProductSales(startDate, endDate time.Time, categoryID int) (*models.ProductSalesReport, error) {
+	start, end, err := s.storeRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.GetSalesTotals(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetProductSales(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].RevenueShare = sharePercent(int64(items[i].Revenue), int64(totals.Revenue))
+		items[i].QuantityShare = sharePercent(int64(items[i].Quantity), int64(totals.Quantity))
+	}
+
+	report := &models.ProductSalesReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Totals:    totals,
+		Items:     items,
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	return report, nil
+}
+
+// GetInventoryValuation menilai persediaan per produk, per kategori dan
+// total. asOf kosong berarti saat ini; jika diisi, persediaan dinilai pada
+// akhir tanggal tersebut di zona waktu toko.
+func (s *ReportService) GetInventoryValuation(asOf *time.Time, categoryID int) (*models.InventoryValuation, error) {
+	valuation := &models.InventoryValuation{}
+	var cutoff *time.Time
+	if asOf != nil {
+		if asOf.After(s.Today()) {
+			return nil, invalidParam("as_of tidak boleh di masa depan")
+		}
+		_, end := storeDayBounds(s.location, *asOf, *asOf)
+		cutoff = &end
+		date := asOf.Format("2006-01-02")
+		valuation.AsOf = &date
+	}
+	if categoryID != 0 {
+		valuation.CategoryID = &categoryID
+	}
+
+	products, err := s.repo.GetInventoryValues(cutoff, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	valuation.Products = products
+
+	// Produk sudah terurut per kategori, jadi kategori cukup dikelompokkan
+	// sesuai urutan kemunculan
+	valuation.Categories = make([]models.InventoryCategoryValue, 0)
+	index := make(map[int]int)
+	for _, p := range products {
+		key := 0
+		if p.CategoryID != nil {
+			key = *p.CategoryID
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(valuation.Categories)
+			index[key] = i
+			valuation.Categories = append(valuation.Categories, models.InventoryCategoryValue{
+				CategoryID:   p.CategoryID,
+				CategoryName: p.CategoryName,
+			})
+		}
+		c := &valuation.Categories[i]
+		c.ProductCount++
+		c.Stock += p.Stock
+		c.RetailValue += p.RetailValue
+		c.CostValue += p.CostValue
+
+		valuation.Totals.ProductCount++
+		valuation.Totals.Stock += p.Stock
+		valuation.Totals.RetailValue += p.RetailValue
+		valuation.Totals.CostValue += p.CostValue
+	}
+
+	return valuation, nil
+}
+
+// GetDeadStock mengembalikan produk yang masih punya stok tapi tidak terjual
+// dalam days hari terakhir (termasuk hari ini), atau terjual dengan
+// sell-through di bawah slowThreshold persen. slowThreshold 0 berarti hanya
+// produk yang tidak terjual sama sekali. days 0 dan slowThreshold nil memakai
+// nilai default. Hasil diurutkan dari nilai HPP stok terbesar.
+func (s *ReportService) GetDeadStock(days int, slowThresholdParam *float64, categoryID int) (*models.DeadStockReport, error) {
+	if days == 0 {
+		days = defaultDeadStockDays
+	}
+	slowThreshold := float64(defaultDeadStockSlowThreshold)
+	if slowThresholdParam != nil {
+		slowThreshold = *slowThresholdParam
+	}
+	if days < 1 || days > maxDeadStockDays {
+		return nil, invalidParamf("days harus antara 1 dan %d", maxDeadStockDays)
+	}
+	// Ditulis sebagai negasi supaya NaN ikut ditolak
+	if !(slowThreshold >= 0 && slowThreshold <= 100) {
+		return nil, invalidParam("slow_threshold harus antara 0 dan 100")
+	}
+
+	today := storeToday(s.location)
+	since, _ := storeDayBounds(s.location, today.AddDate(0, 0, -(days-1)), today)
+	products, err := s.repo.GetStockSales(since, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DeadStockReport{
+		AsOf:          today.Format("2006-01-02"),
+		Days:          days,
+		SlowThreshold: slowThreshold,
+		Items:         make([]models.DeadStockItem, 0),
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	for _, item := range products {
+		item.SellThrough = sharePercent(int64(item.SoldQuantity), int64(item.SoldQuantity+item.Stock))
+		switch {
+		case item.SoldQuantity <= 0:
+			item.Status = models.StockStatusDead
+			report.Totals.DeadProducts++
+		case item.SellThrough < slowThreshold:
+			item.Status = models.StockStatusSlow
+			report.Totals.SlowProducts++
+		default:
+			continue
+		}
+		if item.LastSaleAt != nil {
+			y, m, d := item.LastSaleAt.In(s.location).Date()
+			daysSince := int(today.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+			item.DaysSinceLastSale = &daysSince
+		}
+		report.Totals.CostValue += item.CostValue
+		report.Totals.RetailValue += item.RetailValue
+		report.Items = append(report.Items, item)
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.CostValue != b.CostValue {
+			return a.CostValue > b.CostValue
+		}
+		if a.ProductName != b.ProductName {
+			return a.ProductName < b.ProductName
+		}
+		return a.ProductID < b.ProductID
+	})
+	return report, nil
+}
+
+// GetABC mengelompokkan produk ke kelas A/B/C berdasarkan kontribusi
+// kumulatif revenue atau profit dalam rentang tanggal lokal toko. cutoffA
+// dan cutoffB nil memakai default 80 dan 95. Produk tanpa kontribusi positif
+// (tidak terjual atau rugi) selalu kelas C.
+func (s *ReportService) GetABC(startDate, endDate time.Time, categoryID int, by string, cutoffA, cutoffB *float64) (*models.ABCReport, error) {
+	if by == "" {
+		by = models.ABCByRevenue
+	}
+	if by != models.ABCByRevenue && by != models.ABCByProfit {
+		return nil, invalidParam("by harus revenue atau profit")
+	}
+	a, b, err := abcCutoffs(cutoffA, cutoffB)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := s.storeRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.repo.GetProductContributions(start, end, categoryID, by)
+	if err != nil {
+		return nil, err
+	}
+
+	value := func(p models.ProductSalesRanked) int {
+		if by == models.ABCByProfit {
+			return p.Profit
+		}
+		return p.Revenue
+	}
+	total := 0
+	for _, p := range products {
+		total += max(value(p), 0)
+	}
+
+	report := &models.ABCReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		By:        by,
+		CutoffA:   a,
+		CutoffB:   b,
+		Total:     total,
+		Items:     make([]models.ABCProduct, 0, len(products)),
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+
+	classes := map[string]*models.ABCClassSummary{
+		models.ABCClassA: {Class: models.ABCClassA},
+		models.ABCClassB: {Class: models.ABCClassB},
+		models.ABCClassC: {Class: models.ABCClassC},
+	}
+	cumulative := 0
+	for i, p := range products {
+		v := value(p)
+		class := models.ABCClassC
+		if v > 0 {
+			// Kelas ditentukan dari kumulatif sebelum produk ini, supaya produk
+			// yang melewati batas masih masuk kelas di atasnya
+			before := sharePercent(int64(cumulative), int64(total))
+			switch {
+			case before < a:
+				class = models.ABCClassA
+			case before < b:
+				class = models.ABCClassB
+			}
+			cumulative += v
+		}
+
+		summary := classes[class]
+		summary.ProductCount++
+		summary.Value += v
+		report.Items = append(report.Items, models.ABCProduct{
+			Rank:            i + 1,
+			ProductID:       p.ProductID,
+			ProductName:     p.ProductName,
+			CategoryID:      p.CategoryID,
+			CategoryName:    p.CategoryName,
+			Class:           class,
+			Quantity:        p.Quantity,
+			Revenue:         p.Revenue,
+			Profit:          p.Profit,
+			Share:           sharePercent(int64(max(v, 0)), int64(total)),
+			CumulativeShare: sharePercent(int64(cumulative), int64(total)),
+		})
+	}
+
+	for _, class := range []string{models.ABCClassA, models.ABCClassB, models.ABCClassC} {
+		summary := classes[class]
+		summary.ProductShare = sharePercent(int64(summary.ProductCount), int64(len(products)))
+		summary.ValueShare = sharePercent(int64(summary.Value), int64(total))
+		report.Classes = append(report.Classes, *summary)
+	}
+	return report, nil
+}
+
+// abcCutoffs mengembalikan batas kumulatif kelas A dan B; nil memakai
+// default. Batas harus memenuhi 0 < a < b <= 100.
+func abcCutoffs(cutoffA, cutoffB *float64) (float64, float64, error) {
+	a, b := float64(defaultABCCutoffA), float64(defaultABCCutoffB)
+	if cutoffA != nil {
+		a = *cutoffA
+	}
+	if cutoffB != nil {
+		b = *cutoffB
+	}
+	// Ditulis sebagai negasi supaya NaN ikut ditolak
+	if !(a > 0 && a < b && b <= 100) {
+		return 0, 0, invalidParam("cutoff harus memenuhi 0 < a < b <= 100")
+	}
+	return a, b, nil
+}
+
+// GetReorderSuggestions meramal permintaan harian setiap produk dari rata-rata
+// penjualan per hari dalam seminggu selama lookbackWeeks minggu penuh sebelum
+// hari ini, menghitung kapan stok habis, dan menyarankan jumlah pesanan untuk
+// produk yang stok plus pesanan berjalannya sudah di bawah reorder point.
+// lookbackWeeks 0, coverageDays nil dan defaultLeadTime nil memakai nilai
+// default. Hasil diurutkan dari produk yang paling cepat habis.
+func (s *ReportService) GetReorderSuggestions(lookbackWeeks int, coverageDays, defaultLeadTime *int, categoryID, supplierID int) (*models.ReorderReport, error) {
+	if lookbackWeeks == 0 {
+		lookbackWeeks = defaultReorderLookbackWeeks
+	}
+	coverage, leadTime := defaultReorderCoverageDays, defaultReorderLeadTimeDays
+	if coverageDays != nil {
+		coverage = *coverageDays
+	}
+	if defaultLeadTime != nil {
+		leadTime = *defaultLeadTime
+	}
+	if lookbackWeeks < 1 || lookbackWeeks > maxReorderLookbackWeeks {
+		return nil, invalidParamf("lookback_weeks harus antara 1 dan %d", maxReorderLookbackWeeks)
+	}
+	if coverage < 0 || coverage > maxReorderDays {
+		return nil, invalidParamf("coverage_days harus antara 0 dan %d", maxReorderDays)
+	}
+	if leadTime < 0 || leadTime > maxReorderDays {
+		return nil, invalidParamf("default_lead_time_days harus antara 0 dan %d", maxReorderDays)
+	}
+
+	// Hari ini belum selesai, jadi riwayat diambil dari minggu-minggu penuh
+	// sebelumnya supaya setiap hari dalam seminggu muncul sama banyak
+	today := storeToday(s.location)
+	first := today.AddDate(0, 0, -lookbackWeeks*7)
+	start, end := storeDayBounds(s.location, first, today.AddDate(0, 0, -1))
+	sold, err := s.repo.GetDailySoldQuantities(start, end, s.location.String())
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.repo.GetReorderCandidates(categoryID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReorderReport{
+		AsOf:                today.Format("2006-01-02"),
+		LookbackWeeks:       lookbackWeeks,
+		CoverageDays:        coverage,
+		DefaultLeadTimeDays: leadTime,
+		Items:               make([]models.ReorderSuggestion, 0),
+	}
+	if categoryID != 0 {
+		report.CategoryID = &categoryID
+	}
+	if supplierID != 0 {
+		report.SupplierID = &supplierID
+	}
+
+	for _, item := range products {
+		daily := sold[item.ProductID]
+		if len(daily) == 0 {
+			continue
+		}
+		if item.SupplierID == nil {
+			item.LeadTimeDays = leadTime
+		}
+		suggestion, ok, err := reorderSuggestion(item, daily, today, lookbackWeeks, coverage)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		report.Totals.Products++
+		report.Totals.EstimatedCost += suggestion.EstimatedCost
+		report.Items = append(report.Items, suggestion)
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		switch {
+		case a.DaysUntilStockout == nil && b.DaysUntilStockout != nil:
+			return false
+		case a.DaysUntilStockout != nil && b.DaysUntilStockout == nil:
+			return true
+		case a.DaysUntilStockout != nil && *a.DaysUntilStockout != *b.DaysUntilStockout:
+			return *a.DaysUntilStockout < *b.DaysUntilStockout
+		}
+		if a.ProductName != b.ProductName {
+			return a.ProductName < b.ProductName
+		}
+		return a.ProductID < b.ProductID
+	})
+	return report, nil
+}
+
+// reorderSuggestion meramal permintaan item dari penjualan harian daily
+// (kunci tanggal lokal) selama lookbackWeeks minggu penuh sebelum today, lalu
+// mengisi tanggal stok habis, safety stock, reorder point dan jumlah pesanan
+// untuk lead time item ditambah coverage hari. ok false berarti stok plus
+// pesanan berjalan masih di atas reorder point.
+func reorderSuggestion(item models.ReorderSuggestion, daily map[string]models.Quantity, today time.Time, lookbackWeeks, coverage int) (models.ReorderSuggestion, bool, error) {
+	days := lookbackWeeks * 7
+	first := today.AddDate(0, 0, -days)
+
+	// Permintaan dihitung dalam satuan seperseribu unit (skala Quantity)
+	var weekday [7]float64
+	var total, sumSquares float64
+	for i := 0; i < days; i++ {
+		date := first.AddDate(0, 0, i)
+		q := float64(daily[date.Format("2006-01-02")])
+		weekday[weekdayIndex(date)] += q / float64(lookbackWeeks)
+		total += q
+		sumSquares += q * q
+	}
+	mean := total / float64(days)
+	stddev := math.Sqrt(math.Max(sumSquares/float64(days)-mean*mean, 0))
+	forecast := func(day int) float64 {
+		return weekday[weekdayIndex(today.AddDate(0, 0, day))]
+	}
+	demandUntil := func(n int) float64 {
+		sum := 0.0
+		for day := 0; day < n; day++ {
+			sum += forecast(day)
+		}
+		return sum
+	}
+
+	item.AverageDailyDemand = models.Quantity(math.Round(mean))
+	item.WeekdayDemand = make([]models.Quantity, 7)
+	for i, q := range weekday {
+		item.WeekdayDemand[i] = models.Quantity(math.Round(q))
+	}
+
+	// Stok habis pada hari pertama yang permintaannya melebihi sisa stok,
+	// dihitung mulai hari ini
+	remaining := float64(item.Stock)
+	for day := 0; day < maxReorderDays; day++ {
+		remaining -= forecast(day)
+		if remaining < 0 {
+			stockoutDate := today.AddDate(0, 0, day).Format("2006-01-02")
+			item.DaysUntilStockout = &day
+			item.StockoutDate = &stockoutDate
+			break
+		}
+	}
+
+	safety := reorderSafetyFactor * stddev * math.Sqrt(float64(item.LeadTimeDays))
+	item.SafetyStock = models.Quantity(math.Ceil(safety))
+	item.ReorderPoint = models.Quantity(math.Ceil(demandUntil(item.LeadTimeDays) + safety))
+	position := item.Stock + item.OnOrder
+	if position > item.ReorderPoint {
+		return item, false, nil
+	}
+
+	// Pesanan dibulatkan ke atas ke unit utuh
+	target := models.Quantity(math.Ceil(demandUntil(item.LeadTimeDays+coverage) + safety))
+	need := int64(target - position)
+	if need <= 0 {
+		return item, false, nil
+	}
+	item.SuggestedQuantity = models.Quantity((need + models.QuantityScale - 1) / models.QuantityScale * models.QuantityScale)
+	cost, err := item.SuggestedQuantity.MulPrice(item.UnitCost)
+	if err != nil {
+		return item, false, fmt.Errorf("estimasi biaya %s: %w", item.ProductName, err)
+	}
+	item.EstimatedCost = cost
+	return item, true, nil
+}
+
+// ReorderPurchaseOrder menyusun draft purchase order dari saran pemesanan
+// ulang untuk produk dengan supplier terakhir supplierID. Purchase order
+// belum disimpan.
+func ReorderPurchaseOrder(report *models.ReorderReport, supplierID int) (*models.PurchaseOrder, error) {
+	po := &models.PurchaseOrder{
+		SupplierID: supplierID,
+		Notes:      "Dibuat dari saran pemesanan ulang per " + report.AsOf,
+	}
+	for _, item := range report.Items {
+		if item.SupplierID == nil || *item.SupplierID != supplierID {
+			continue
+		}
+		po.Items = append(po.Items, models.PurchaseOrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.SuggestedQuantity,
+			UnitCost:  item.UnitCost,
+		})
+	}
+	if len(po.Items) == 0 {
+		return nil, invalidParamf("tidak ada produk yang perlu dipesan ulang dari supplier id %d", supplierID)
+	}
+	return po, nil
+}
+
+// GetRFM menilai pelanggan yang bertransaksi dalam days hari terakhir
+// (termasuk hari ini) dengan skor recency, frequency dan monetary 1 sampai 5,
+// lalu mengelompokkannya ke segmen. Skor dihitung dari peringkat persentil di
+// antara pelanggan dalam laporan, jadi tidak bergantung pada besaran belanja
+// toko. segment bukan kosong hanya menampilkan pelanggan segmen tersebut.
+// days 0 memakai default 365.
+func (s *ReportService) GetRFM(days, customerGroupID int, segment string) (*models.RFMReport, error) {
+	if days == 0 {
+		days = defaultRFMDays
+	}
+	if days < 1 || days > maxRFMDays {
+		return nil, invalidParamf("days harus antara 1 dan %d", maxRFMDays)
+	}
+	if segment != "" && !slices.Contains(models.RFMSegments, segment) {
+		return nil, invalidParamf("segment harus salah satu dari %s", strings.Join(models.RFMSegments, ", "))
+	}
+
+	today := storeToday(s.location)
+	startDate := today.AddDate(0, 0, -(days - 1))
+	start, end := storeDayBounds(s.location, startDate, today)
+	customers, err := s.repo.GetCustomerActivity(start, end, customerGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	recency := make([]int, len(customers))
+	frequency := make([]int, len(customers))
+	monetary := make([]int, len(customers))
+	for i := range customers {
+		c := &customers[i]
+		y, m, d := c.LastPurchaseAt.In(s.location).Date()
+		c.RecencyDays = int(today.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		// Recency lebih kecil lebih baik, jadi dinilai dari nilai negatifnya
+		recency[i] = -c.RecencyDays
+		frequency[i] = c.Frequency
+		monetary[i] = c.Monetary
+	}
+	recencyScores, frequencyScores, monetaryScores := rfmScores(recency), rfmScores(frequency), rfmScores(monetary)
+
+	report := &models.RFMReport{
+		AsOf:      today.Format("2006-01-02"),
+		StartDate: startDate.Format("2006-01-02"),
+		Days:      days,
+		Segment:   segment,
+		Customers: make([]models.RFMCustomer, 0),
+	}
+	if customerGroupID != 0 {
+		report.CustomerGroupID = &customerGroupID
+	}
+
+	summaries := make(map[string]*models.RFMSegmentSummary)
+	for _, name := range models.RFMSegments {
+		summaries[name] = &models.RFMSegmentSummary{Segment: name}
+	}
+	totalRevenue := 0
+	for i, c := range customers {
+		c.RecencyScore, c.FrequencyScore, c.MonetaryScore = recencyScores[i], frequencyScores[i], monetaryScores[i]
+		c.Score = fmt.Sprintf("%d%d%d", c.RecencyScore, c.FrequencyScore, c.MonetaryScore)
+		c.Segment = rfmSegment(c.RecencyScore, c.FrequencyScore, c.MonetaryScore)
+
+		summary := summaries[c.Segment]
+		summary.Customers++
+		summary.Revenue += c.Monetary
+		totalRevenue += c.Monetary
+		if segment == "" || c.Segment == segment {
+			report.Customers = append(report.Customers, c)
+		}
+	}
+	for _, name := range models.RFMSegments {
+		summary := summaries[name]
+		summary.CustomerShare = sharePercent(int64(summary.Customers), int64(len(customers)))
+		summary.RevenueShare = sharePercent(int64(summary.Revenue), int64(totalRevenue))
+		report.Segments = append(report.Segments, *summary)
+	}
+
+	sort.SliceStable(report.Customers, func(i, j int) bool {
+		a, b := report.Customers[i], report.Customers[j]
+		if a.Segment != b.Segment {
+			return slices.Index(models.RFMSegments, a.Segment) < slices.Index(models.RFMSegments, b.Segment)
+		}
+		if a.Monetary != b.Monetary {
+			return a.Monetary > b.Monetary
+		}
+		if a.CustomerName != b.CustomerName {
+			return a.CustomerName < b.CustomerName
+		}
+		return a.CustomerID < b.CustomerID
+	})
+	return report, nil
+}
+
+// rfmScores memberi skor 1 sampai 5 untuk setiap nilai (lebih besar lebih
+// baik) dari persentil titik tengah peringkatnya, yaitu (jumlah nilai lebih
+// kecil + setengah jumlah nilai yang sama) / n. Nilai yang sama mendapat skor
+// yang sama; jika semua nilai sama, semuanya mendapat skor 3.
+func rfmScores(values []int) []int {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	n := len(values)
+	scores := make([]int, n)
+	for i, v := range values {
+		below := sort.SearchInts(sorted, v)
+		equal := sort.SearchInts(sorted, v+1) - below
+		scores[i] = 1 + 5*(2*below+equal)/(2*n)
+	}
+	return scores
+}
+
+// rfmSegment menentukan segmen dari skor R dan rata-rata skor F dan M.
+func rfmSegment(r, f, m int) string {
+	fm := (f + m + 1) / 2
+	switch {
+	case r >= 4 && fm >= 4:
+		return models.RFMSegmentChampions
+	case r >= 3 && fm >= 3:
+		return models.RFMSegmentLoyal
+	case r >= 4:
+		return models.RFMSegmentNew
+	case r <= 2 && fm >= 3:
+		return models.RFMSegmentAtRisk
+	case r == 1:
+		return models.RFMSegmentLost
+	default:
+		return models.RFMSegmentNeedAttention
+	}
+}
+
+// weekdayIndex mengubah hari dalam seminggu ke indeks Senin = 0 sampai
+// Minggu = 6.
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// GetComparison membandingkan penjualan startDate sampai endDate dengan
+// periode sebelumnya (mode previous) atau periode yang sama tahun lalu (mode
+// last_year).
+func (s *ReportService) GetComparison(startDate, endDate time.Time, categoryID int, mode string) (*models.SalesComparison, error) {
+	if endDate.Before(startDate) {
+		return nil, invalidParam("end_date tidak boleh sebelum start_date")
+	}
+
+	prevStart, prevEnd, err := comparisonPeriod(startDate, endDate, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := storeDayBounds(s.location, startDate, endDate)
+	pStart, pEnd := storeDayBounds(s.location, prevStart, prevEnd)
+
+	current, err := s.repo.GetSalesTotals(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.repo.GetSalesTotals(pStart, pEnd, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	currentProducts, err := s.repo.GetProductSales(start, end, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	previousProducts, err := s.repo.GetProductSales(pStart, pEnd, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &models.SalesComparison{
+		Mode:              mode,
+		StartDate:         startDate.Format("2006-01-02"),
+		EndDate:           endDate.Format("2006-01-02"),
+		PreviousStartDate: prevStart.Format("2006-01-02"),
+		PreviousEndDate:   prevEnd.Format("2006-01-02"),
+		Revenue:           metricChange(current.Revenue, previous.Revenue),
+		Transactions:      metricChange(current.Transactions, previous.Transactions),
+		AverageBasket:     metricChange(averageBasket(current), averageBasket(previous)),
+	}
+
+	// Gabungkan produk dari kedua periode, termasuk yang hanya terjual di
+	// salah satunya
+	byID := make(map[int]*models.ProductQuantityChange)
+	products := make([]*models.ProductQuantityChange, 0)
+	entry := func(id int, name string) *models.ProductQuantityChange {
+		if p, ok := byID[id]; ok {
+			return p
+		}
+		p := &models.ProductQuantityChange{ProductID: id, ProductName: name}
+		byID[id] = p
+		products = append(products, p)
+		return p
+	}
+	for _, p := range currentProducts {
+		entry(p.ProductID, p.ProductName).CurrentQuantity = p.Quantity
+	}
+	for _, p := range previousProducts {
+		entry(p.ProductID, p.ProductName).PreviousQuantity = p.Quantity
+	}
+
+	comparison.Products = make([]models.ProductQuantityChange, len(products))
+	for i, p := range products {
+		p.Change = p.CurrentQuantity - p.PreviousQuantity
+		p.ChangePercent = changePercent(int64(p.CurrentQuantity), int64(p.PreviousQuantity))
+		comparison.Products[i] = *p
+	}
+	sort.SliceStable(comparison.Products, func(i, j int) bool {
+		a, b := comparison.Products[i], comparison.Products[j]
+		if a.CurrentQuantity != b.CurrentQuantity {
+			return a.CurrentQuantity > b.CurrentQuantity
+		}
+		if a.PreviousQuantity != b.PreviousQuantity {
+			return a.PreviousQuantity > b.PreviousQuantity
+		}
+		return a.ProductName < b.ProductName
+	})
+
+	return comparison, nil
+}
+
+// comparisonPeriod mengembalikan tanggal awal dan akhir periode pembanding
+// untuk startDate sampai endDate (inklusif).
+func comparisonPeriod(startDate, endDate time.Time, mode string) (time.Time, time.Time, error) {
+	switch mode {
+	case models.ComparePreviousPeriod:
+		days := int(endDate.Sub(startDate).Hours()/24) + 1
+		prevEnd := startDate.AddDate(0, 0, -1)
+		return prevEnd.AddDate(0, 0, -(days - 1)), prevEnd, nil
+	case models.CompareLastYear:
+		return sameDayLastYear(startDate), sameDayLastYear(endDate), nil
+	default:
+		return time.Time{}, time.Time{}, invalidParam("compare harus previous atau last_year")
+	}
+}
+
+// sameDayLastYear mengembalikan tanggal yang sama setahun sebelumnya. 29
+// Februari menjadi 28 Februari.
+func sameDayLastYear(t time.Time) time.Time {
+	last := t.AddDate(-1, 0, 0)
+	if last.Month() != t.Month() {
+		last = last.AddDate(0, 0, -last.Day())
+	}
+	return last
+}
+
+func averageBasket(totals models.SalesBreakdownTotals) int {
+	if totals.Transactions == 0 {
+		return 0
+	}
+	return (totals.Revenue + totals.Transactions/2) / totals.Transactions
+}
+
+func metricChange(current, previous int) models.MetricChange {
+	return models.MetricChange{
+		Current:       current,
+		Previous:      previous,
+		Change:        current - previous,
+		ChangePercent: changePercent(int64(current), int64(previous)),
+	}
+}
+
+// changePercent menghitung perubahan current terhadap previous dalam persen
+// dengan dua desimal, atau nil jika previous nol.
+func changePercent(current, previous int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	p := math.Round(float64(current-previous)*10000/float64(previous)) / 100
+	return &p
+}
+
+// RebuildRollups menghitung ulang rollup penjualan harian untuk tanggal from
+// sampai to (YYYY-MM-DD, inklusif) di zona waktu toko. String kosong berarti
+// tanpa batas. Mengembalikan jumlah hari yang punya transaksi.
+func (s *ReportService) RebuildRollups(from, to string) (int, error) {
+	fromDate, err := optionalDate(from)
+	if err != nil {
+		return 0, err
+	}
+	toDate, err := optionalDate(to)
+	if err != nil {
+		return 0, err
+	}
+	if fromDate != nil && toDate != nil && *toDate < *fromDate {
+		return 0, errors.New("to tidak boleh sebelum from")
+	}
+	return s.repo.RebuildDailyRollups(fromDate, toDate, s.location.String())
+}
+
+// optionalDate memvalidasi tanggal YYYY-MM-DD; string kosong menjadi nil.
+func optionalDate(s string) (*string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return nil, fmt.Errorf("tanggal %q tidak valid, gunakan YYYY-MM-DD", s)
+	}
+	return &s, nil
+}
+
+// sharePercent menghitung part/total dalam persen dengan dua desimal.
+func sharePercent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
+
+// storeRange mengubah rentang tanggal (inklusif) menjadi [awal startDate,
+// awal hari setelah endDate) di zona waktu toko.
+func (s *ReportService) storeRange(startDate, endDate time.Time) (time.Time, time.Time, error) {
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, invalidParam("end_date tidak boleh sebelum start_date")
+	}
+	start, end := storeDayBounds(s.location, startDate, endDate)
+	return start, end, nil
+}
+
+// storeToday mengembalikan tanggal hari ini di zona waktu location, dalam
+// bentuk tengah malam UTC seperti tanggal hasil parse YYYY-MM-DD.
+func storeToday(location *time.Location) time.Time {
+	y, m, d := time.Now().In(location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// storeDayBounds mengubah tanggal startDate sampai endDate (inklusif) menjadi
+// rentang waktu [awal startDate, awal hari setelah endDate) di location.
+func storeDayBounds(location *time.Location, startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, location)
+	end := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, 0, location)
+	return start, end
+}
+
+// truncateToBucket membulatkan t ke awal bucket-nya di zona waktu t, sama
+// dengan date_trunc di Postgres (minggu dimulai hari Senin). Jam dibulatkan
+// dengan mengurangi menit dan detik lokal, bukan lewat time.Date, supaya jam
+// yang terulang saat DST berakhir tetap menjadi dua bucket berbeda.
+func truncateToBucket(t time.Time, granularity string) time.Time {
+	y, m, d := t.Date()
+	switch granularity {
+	case models.GranularityHour:
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case models.GranularityWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	case models.GranularityMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.GranularityHour:
+		return t.Add(time.Hour)
+	case models.GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case models.GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
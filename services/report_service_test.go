@@ -0,0 +1,534 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"kasir-api/models"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestTruncateToBucket(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+
+	// Jam 01:30 terjadi dua kali di New York pada 1 November 2026
+	firstOneThirty := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(newYork)
+	secondOneThirty := time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC).In(newYork)
+
+	tests := []struct {
+		name        string
+		t           time.Time
+		granularity string
+		want        time.Time
+	}{
+		{"hour", time.Date(2026, 10, 19, 14, 59, 59, 999, jakarta), models.GranularityHour, time.Date(2026, 10, 19, 14, 0, 0, 0, jakarta)},
+		{"hour with half-hour offset", time.Date(2026, 10, 19, 9, 45, 0, 0, kolkata), models.GranularityHour, time.Date(2026, 10, 19, 9, 0, 0, 0, kolkata)},
+		{"first repeated hour at DST end", firstOneThirty, models.GranularityHour, time.Date(2026, 11, 1, 5, 0, 0, 0, time.UTC)},
+		{"second repeated hour at DST end", secondOneThirty, models.GranularityHour, time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC)},
+		{"hour after DST start", time.Date(2026, 3, 8, 3, 15, 0, 0, newYork), models.GranularityHour, time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC)},
+		{"day", time.Date(2026, 10, 19, 23, 59, 0, 0, jakarta), models.GranularityDay, time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta)},
+		{"day on DST start", time.Date(2026, 3, 8, 12, 0, 0, 0, newYork), models.GranularityDay, time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC)},
+		{"day on DST end", secondOneThirty, models.GranularityDay, time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC)},
+		{"week from Monday", time.Date(2026, 10, 19, 10, 0, 0, 0, jakarta), models.GranularityWeek, time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta)},
+		{"week from Sunday goes back to Monday", time.Date(2026, 10, 25, 23, 0, 0, 0, jakarta), models.GranularityWeek, time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta)},
+		{"ISO week across year end", time.Date(2027, 1, 1, 8, 0, 0, 0, jakarta), models.GranularityWeek, time.Date(2026, 12, 28, 0, 0, 0, 0, jakarta)},
+		{"ISO week across month end", time.Date(2026, 3, 1, 8, 0, 0, 0, jakarta), models.GranularityWeek, time.Date(2026, 2, 23, 0, 0, 0, 0, jakarta)},
+		{"week containing DST start", time.Date(2026, 3, 10, 8, 0, 0, 0, newYork), models.GranularityWeek, time.Date(2026, 3, 9, 0, 0, 0, 0, newYork)},
+		{"month", time.Date(2026, 10, 31, 23, 59, 0, 0, jakarta), models.GranularityMonth, time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta)},
+		{"month in leap February", time.Date(2028, 2, 29, 12, 0, 0, 0, jakarta), models.GranularityMonth, time.Date(2028, 2, 1, 0, 0, 0, 0, jakarta)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateToBucket(tt.t, tt.granularity)
+			if !got.Equal(tt.want) {
+				t.Errorf("truncateToBucket(%s, %s) = %s, want %s", tt.t, tt.granularity, got, tt.want)
+			}
+			if got.Location() != tt.t.Location() {
+				t.Errorf("location = %s, want %s", got.Location(), tt.t.Location())
+			}
+		})
+	}
+}
+
+func TestNextBucket(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name        string
+		t           time.Time
+		granularity string
+		want        time.Time
+	}{
+		{"hour", time.Date(2026, 10, 19, 23, 0, 0, 0, jakarta), models.GranularityHour, time.Date(2026, 10, 20, 0, 0, 0, 0, jakarta)},
+		{"hour skips missing hour at DST start", time.Date(2026, 3, 8, 1, 0, 0, 0, newYork), models.GranularityHour, time.Date(2026, 3, 8, 3, 0, 0, 0, newYork)},
+		{"day", time.Date(2026, 10, 31, 0, 0, 0, 0, jakarta), models.GranularityDay, time.Date(2026, 11, 1, 0, 0, 0, 0, jakarta)},
+		{"day across DST start is 23 hours", time.Date(2026, 3, 8, 0, 0, 0, 0, newYork), models.GranularityDay, time.Date(2026, 3, 9, 0, 0, 0, 0, newYork)},
+		{"day across DST end is 25 hours", time.Date(2026, 11, 1, 0, 0, 0, 0, newYork), models.GranularityDay, time.Date(2026, 11, 2, 0, 0, 0, 0, newYork)},
+		{"week across year end", time.Date(2026, 12, 28, 0, 0, 0, 0, jakarta), models.GranularityWeek, time.Date(2027, 1, 4, 0, 0, 0, 0, jakarta)},
+		{"week across DST end", time.Date(2026, 10, 26, 0, 0, 0, 0, newYork), models.GranularityWeek, time.Date(2026, 11, 2, 0, 0, 0, 0, newYork)},
+		{"month rollover", time.Date(2026, 1, 1, 0, 0, 0, 0, jakarta), models.GranularityMonth, time.Date(2026, 2, 1, 0, 0, 0, 0, jakarta)},
+		{"month rollover into next year", time.Date(2026, 12, 1, 0, 0, 0, 0, jakarta), models.GranularityMonth, time.Date(2027, 1, 1, 0, 0, 0, 0, jakarta)},
+		{"month across DST start", time.Date(2026, 3, 1, 0, 0, 0, 0, newYork), models.GranularityMonth, time.Date(2026, 4, 1, 0, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextBucket(tt.t, tt.granularity)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextBucket(%s, %s) = %s, want %s", tt.t, tt.granularity, got, tt.want)
+			}
+		})
+	}
+}
+
+// bucketStarts membuat awal bucket untuk tanggal lokal startDate sampai
+// endDate seperti GetTimeSeries.
+func bucketStarts(location *time.Location, startDate, endDate time.Time, granularity string) []time.Time {
+	start, end := storeDayBounds(location, startDate, endDate)
+	var starts []time.Time
+	for b := truncateToBucket(start, granularity); b.Before(end); b = nextBucket(b, granularity) {
+		starts = append(starts, b)
+	}
+	return starts
+}
+
+func TestHourBucketsAcrossDST(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		date time.Time
+		want int
+	}{
+		{"DST start", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), 23},
+		{"DST end", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), 25},
+		{"normal day", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts := bucketStarts(newYork, tt.date, tt.date, models.GranularityHour)
+			if len(starts) != tt.want {
+				t.Fatalf("got %d hour buckets, want %d", len(starts), tt.want)
+			}
+			keys := make(map[string]bool)
+			for i, b := range starts {
+				if i > 0 && b.Sub(starts[i-1]) != time.Hour {
+					t.Errorf("bucket %d starts %s after the previous one, want 1h", i, b.Sub(starts[i-1]))
+				}
+				if b.Minute() != 0 || b.Second() != 0 {
+					t.Errorf("bucket %d starts at %s, not on the hour", i, b)
+				}
+				key := salesBucketKey(b, models.GranularityHour)
+				if keys[key] {
+					t.Errorf("bucket %d (%s) has duplicate key %s", i, b, key)
+				}
+				keys[key] = true
+			}
+		})
+	}
+}
+
+func TestDayBucketsAcrossDST(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	starts := bucketStarts(newYork, time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), models.GranularityDay)
+	want := []string{"2026-03-07", "2026-03-08", "2026-03-09"}
+	if len(starts) != len(want) {
+		t.Fatalf("got %d day buckets, want %d", len(starts), len(want))
+	}
+	for i, b := range starts {
+		if b.Hour() != 0 || b.Minute() != 0 {
+			t.Errorf("bucket %d starts at %s, not at local midnight", i, b)
+		}
+		// Bucket harian dari database berupa tanggal tanpa zona
+		row := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+		if got := salesBucketKey(b, models.GranularityDay); got != want[i] || got != salesBucketKey(row, models.GranularityDay) {
+			t.Errorf("bucket %d key = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestSalesBucketKeyMatchesDatabaseHour(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	// Bucket jam dari database adalah timestamptz yang di-scan di zona lain
+	bucket := time.Date(2026, 11, 1, 1, 0, 0, 0, newYork).Add(time.Hour) // 01:00 EST
+	row := bucket.UTC()
+	if salesBucketKey(bucket, models.GranularityHour) != salesBucketKey(row, models.GranularityHour) {
+		t.Errorf("key of %s does not match database row %s", bucket, row)
+	}
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSameDayLastYear(t *testing.T) {
+	tests := []struct {
+		in, want time.Time
+	}{
+		{date(2026, 10, 19), date(2025, 10, 19)},
+		{date(2028, 2, 29), date(2027, 2, 28)},
+		{date(2028, 2, 28), date(2027, 2, 28)},
+		{date(2028, 3, 1), date(2027, 3, 1)},
+		{date(2029, 2, 28), date(2028, 2, 28)},
+		{date(2029, 3, 1), date(2028, 3, 1)},
+		{date(2027, 1, 1), date(2026, 1, 1)},
+		{date(2026, 12, 31), date(2025, 12, 31)},
+	}
+	for _, tt := range tests {
+		if got := sameDayLastYear(tt.in); !got.Equal(tt.want) {
+			t.Errorf("sameDayLastYear(%s) = %s, want %s", tt.in.Format("2006-01-02"), got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestComparisonPeriod(t *testing.T) {
+	tests := []struct {
+		name               string
+		start, end         time.Time
+		mode               string
+		wantStart, wantEnd time.Time
+		wantErr            bool
+	}{
+		{"previous single day", date(2026, 10, 19), date(2026, 10, 19), models.ComparePreviousPeriod, date(2026, 10, 18), date(2026, 10, 18), false},
+		{"previous week", date(2026, 10, 19), date(2026, 10, 25), models.ComparePreviousPeriod, date(2026, 10, 12), date(2026, 10, 18), false},
+		{"previous period across month start", date(2026, 3, 1), date(2026, 3, 31), models.ComparePreviousPeriod, date(2026, 1, 29), date(2026, 2, 28), false},
+		{"previous period across year start", date(2027, 1, 1), date(2027, 1, 10), models.ComparePreviousPeriod, date(2026, 12, 22), date(2026, 12, 31), false},
+		{"last year", date(2026, 10, 1), date(2026, 10, 31), models.CompareLastYear, date(2025, 10, 1), date(2025, 10, 31), false},
+		{"last year from leap February", date(2028, 2, 1), date(2028, 2, 29), models.CompareLastYear, date(2027, 2, 1), date(2027, 2, 28), false},
+		{"last year starting on Feb 29", date(2028, 2, 29), date(2028, 3, 6), models.CompareLastYear, date(2027, 2, 28), date(2027, 3, 6), false},
+		{"unknown mode", date(2026, 10, 19), date(2026, 10, 19), "week", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := comparisonPeriod(tt.start, tt.end, tt.mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("err = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !gotStart.Equal(tt.wantStart) || !gotEnd.Equal(tt.wantEnd) {
+				t.Errorf("comparisonPeriod = %s..%s, want %s..%s", gotStart.Format("2006-01-02"), gotEnd.Format("2006-01-02"),
+					tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestStoreDayBoundsAcrossDST(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       time.Duration
+	}{
+		{"DST start", date(2026, 3, 8), date(2026, 3, 8), 23 * time.Hour},
+		{"DST end", date(2026, 11, 1), date(2026, 11, 1), 25 * time.Hour},
+		{"last year's DST end", date(2025, 11, 2), date(2025, 11, 2), 25 * time.Hour},
+		{"week containing DST start", date(2026, 3, 2), date(2026, 3, 8), 7*24*time.Hour - time.Hour},
+	}
+	for _, tt := range tests {
+		start, end := storeDayBounds(newYork, tt.start, tt.end)
+		if got := end.Sub(start); got != tt.want {
+			t.Errorf("%s: range is %s, want %s", tt.name, got, tt.want)
+		}
+		if start.Hour() != 0 || end.Hour() != 0 {
+			t.Errorf("%s: bounds %s..%s are not local midnights", tt.name, start, end)
+		}
+	}
+}
+
+func TestGetDeadStockValidation(t *testing.T) {
+	// Parameter yang tidak valid ditolak sebelum repository dibaca, jadi
+	// service tanpa repository cukup untuk test ini
+	s := &ReportService{location: mustLoadLocation(t, "Asia/Jakarta")}
+	threshold := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name          string
+		days          int
+		slowThreshold *float64
+	}{
+		{"negative threshold", 0, threshold(-0.1)},
+		{"threshold above 100", 0, threshold(100.1)},
+		{"NaN threshold", 0, threshold(math.NaN())},
+		{"infinite threshold", 0, threshold(math.Inf(1))},
+		{"negative infinite threshold", 0, threshold(math.Inf(-1))},
+		{"negative days", -1, nil},
+		{"too many days", maxDeadStockDays + 1, nil},
+	}
+	for _, tt := range tests {
+		_, err := s.GetDeadStock(tt.days, tt.slowThreshold, 0)
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: err = %v, want ValidationError", tt.name, err)
+		}
+	}
+}
+
+func TestABCCutoffs(t *testing.T) {
+	cutoff := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name    string
+		a, b    *float64
+		wantA   float64
+		wantB   float64
+		wantErr bool
+	}{
+		{"defaults", nil, nil, 80, 95, false},
+		{"custom", cutoff(70), cutoff(90), 70, 90, false},
+		{"only a", cutoff(50), nil, 50, 95, false},
+		{"only b", nil, cutoff(100), 80, 100, false},
+		{"fractional", cutoff(0.5), cutoff(99.5), 0.5, 99.5, false},
+		{"a zero", cutoff(0), cutoff(90), 0, 0, true},
+		{"a negative", cutoff(-10), cutoff(90), 0, 0, true},
+		{"a equals b", cutoff(90), cutoff(90), 0, 0, true},
+		{"a above b", cutoff(95), cutoff(90), 0, 0, true},
+		{"a above default b", cutoff(96), nil, 0, 0, true},
+		{"b above 100", cutoff(80), cutoff(100.5), 0, 0, true},
+		{"b below default a", nil, cutoff(60), 0, 0, true},
+		{"a NaN", cutoff(math.NaN()), cutoff(90), 0, 0, true},
+		{"b NaN", cutoff(80), cutoff(math.NaN()), 0, 0, true},
+		{"b infinite", cutoff(80), cutoff(math.Inf(1)), 0, 0, true},
+		{"a negative infinite", cutoff(math.Inf(-1)), cutoff(90), 0, 0, true},
+	}
+	for _, tt := range tests {
+		a, b, err := abcCutoffs(tt.a, tt.b)
+		if tt.wantErr {
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Errorf("%s: err = %v, want ValidationError", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || a != tt.wantA || b != tt.wantB {
+			t.Errorf("%s: got (%v, %v, %v), want (%v, %v, nil)", tt.name, a, b, err, tt.wantA, tt.wantB)
+		}
+	}
+}
+
+func TestReorderSuggestionWeekdayForecast(t *testing.T) {
+	units := func(n int64) models.Quantity { return models.Quantity(n * models.QuantityScale) }
+	today := date(2026, 10, 19) // Senin
+
+	// Dua minggu penuh sebelum hari ini: Senin 10 dan 20 unit, Sabtu 4 dan 0
+	daily := map[string]models.Quantity{
+		"2026-10-05": units(10),
+		"2026-10-12": units(20),
+		"2026-10-10": units(4),
+		"2026-10-19": units(100), // hari ini belum selesai, tidak ikut dihitung
+	}
+	item := models.ReorderSuggestion{Stock: units(20)}
+
+	got, ok, err := reorderSuggestion(item, daily, today, 2, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.Quantity{units(15), 0, 0, 0, 0, units(2), 0}
+	for i := range want {
+		if got.WeekdayDemand[i] != want[i] {
+			t.Errorf("WeekdayDemand = %v, want %v", got.WeekdayDemand, want)
+			break
+		}
+	}
+	// (10 + 20 + 4) / 14 hari = 2,428571 unit
+	if got.AverageDailyDemand != 2429 {
+		t.Errorf("AverageDailyDemand = %s, want 2.429", got.AverageDailyDemand)
+	}
+	// Senin ini 15 (sisa 5), Sabtu 2 (sisa 3), Senin depan 15: habis hari ke-7
+	if got.DaysUntilStockout == nil || *got.DaysUntilStockout != 7 || *got.StockoutDate != "2026-10-26" {
+		t.Errorf("stockout = %v %v, want day 7 on 2026-10-26", derefInt(got.DaysUntilStockout), got.StockoutDate)
+	}
+	// Lead time 0: reorder point 0, stok masih cukup
+	if ok || got.ReorderPoint != 0 || got.SafetyStock != 0 {
+		t.Errorf("ok = %v, reorder point %s, safety %s; want no suggestion", ok, got.ReorderPoint, got.SafetyStock)
+	}
+}
+
+func TestReorderSuggestionQuantities(t *testing.T) {
+	units := func(n int64) models.Quantity { return models.Quantity(n * models.QuantityScale) }
+	today := date(2026, 10, 19) // Senin
+
+	constant := map[string]models.Quantity{}
+	for d := 12; d <= 18; d++ {
+		constant[date(2026, 10, d).Format("2006-01-02")] = units(2)
+	}
+	// Hanya terjual 7 unit pada hari Minggu: rata-rata 1 unit per hari,
+	// simpangan baku sqrt(49/7 - 1) = 2,449 unit
+	sundayOnly := map[string]models.Quantity{"2026-10-18": units(7)}
+
+	tests := []struct {
+		name          string
+		daily         map[string]models.Quantity
+		item          models.ReorderSuggestion
+		coverage      int
+		wantOK        bool
+		wantSafety    models.Quantity
+		wantROP       models.Quantity
+		wantSuggested models.Quantity
+		wantCost      int
+		wantStockout  int
+	}{
+		{
+			// Permintaan tetap 2 unit/hari: tanpa safety stock, reorder point
+			// 3 hari x 2 unit, pesanan menutup 3 + 4 hari dikurangi stok 5
+			name:          "constant demand",
+			daily:         constant,
+			item:          models.ReorderSuggestion{Stock: units(5), LeadTimeDays: 3, UnitCost: 1500},
+			coverage:      4,
+			wantOK:        true,
+			wantSafety:    0,
+			wantROP:       units(6),
+			wantSuggested: units(9),
+			wantCost:      13500,
+			wantStockout:  2,
+		},
+		{
+			name:     "on order covers reorder point",
+			daily:    constant,
+			item:     models.ReorderSuggestion{Stock: units(5), OnOrder: units(2), LeadTimeDays: 3, UnitCost: 1500},
+			coverage: 4,
+			wantOK:   false, wantSafety: 0, wantROP: units(6), wantStockout: 2,
+		},
+		{
+			// Safety 1,65 x 2449,49 x sqrt(4) = 8083,3 -> 8,084 unit.
+			// Senin sampai Kamis diramal 0, jadi reorder point = safety stock.
+			// Target 7 hari = 7 + 8,084; kurang 7,084 dibulatkan ke 8 unit.
+			// Stok 8 tersisa 1 setelah Minggu ini dan habis Minggu depan.
+			name:          "variable demand and lead time",
+			daily:         sundayOnly,
+			item:          models.ReorderSuggestion{Stock: units(8), LeadTimeDays: 4, UnitCost: 1000},
+			coverage:      3,
+			wantOK:        true,
+			wantSafety:    8084,
+			wantROP:       8084,
+			wantSuggested: units(8),
+			wantCost:      8000,
+			wantStockout:  13,
+		},
+		{
+			name:     "lead time zero has no safety stock",
+			daily:    sundayOnly,
+			item:     models.ReorderSuggestion{Stock: units(8), LeadTimeDays: 0, UnitCost: 1000},
+			coverage: 3,
+			wantOK:   false, wantSafety: 0, wantROP: 0, wantStockout: 13,
+		},
+	}
+	for _, tt := range tests {
+		got, ok, err := reorderSuggestion(tt.item, tt.daily, today, 1, tt.coverage)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ok != tt.wantOK {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if got.SafetyStock != tt.wantSafety || got.ReorderPoint != tt.wantROP {
+			t.Errorf("%s: safety %s, reorder point %s; want %s, %s", tt.name, got.SafetyStock, got.ReorderPoint, tt.wantSafety, tt.wantROP)
+		}
+		if got.DaysUntilStockout == nil || *got.DaysUntilStockout != tt.wantStockout {
+			t.Errorf("%s: days until stockout = %v, want %d", tt.name, derefInt(got.DaysUntilStockout), tt.wantStockout)
+		}
+		if !tt.wantOK {
+			continue
+		}
+		if got.SuggestedQuantity != tt.wantSuggested || got.EstimatedCost != tt.wantCost {
+			t.Errorf("%s: suggested %s costing %d, want %s costing %d", tt.name, got.SuggestedQuantity, got.EstimatedCost, tt.wantSuggested, tt.wantCost)
+		}
+	}
+}
+
+func derefInt(p *int) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestRFMScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   []int
+	}{
+		{"empty", []int{}, []int{}},
+		{"single customer", []int{250000}, []int{3}},
+		{"all equal", []int{7, 7, 7, 7}, []int{3, 3, 3, 3}},
+		{"five distinct", []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}},
+		{"input order kept", []int{50, 10, 30}, []int{5, 1, 3}},
+		{"quintiles of ten", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, []int{1, 1, 2, 2, 3, 3, 4, 4, 5, 5}},
+		// Peringkat tengah kelompok 2 tepat di persentil 80: masih skor 5
+		{"tie exactly at score 5 boundary", []int{1, 1, 1, 2, 2}, []int{2, 2, 2, 5, 5}},
+		{"just below score 5 boundary", []int{1, 1, 1, 2, 3}, []int{2, 2, 2, 4, 5}},
+		{"one high spender", []int{100, 100, 100, 100, 900}, []int{3, 3, 3, 3, 5}},
+	}
+	for _, tt := range tests {
+		got := rfmScores(tt.values)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: rfmScores(%v) = %v, want %v", tt.name, tt.values, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: rfmScores(%v) = %v, want %v", tt.name, tt.values, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRFMSegment(t *testing.T) {
+	tests := []struct {
+		r, f, m int
+		want    string
+	}{
+		{5, 5, 5, models.RFMSegmentChampions},
+		{4, 4, 3, models.RFMSegmentChampions}, // rata-rata F dan M 3,5 dibulatkan ke 4
+		{4, 4, 2, models.RFMSegmentLoyal},
+		{3, 5, 5, models.RFMSegmentLoyal},
+		{3, 3, 3, models.RFMSegmentLoyal},
+		{3, 1, 4, models.RFMSegmentLoyal},
+		{5, 1, 1, models.RFMSegmentNew},
+		{4, 2, 2, models.RFMSegmentNew},
+		{2, 5, 5, models.RFMSegmentAtRisk},
+		{1, 3, 2, models.RFMSegmentAtRisk},
+		{1, 2, 2, models.RFMSegmentLost},
+		{1, 1, 1, models.RFMSegmentLost},
+		{2, 2, 2, models.RFMSegmentNeedAttention},
+		{3, 2, 2, models.RFMSegmentNeedAttention},
+		{2, 1, 1, models.RFMSegmentNeedAttention},
+	}
+	seen := make(map[string]bool)
+	for _, tt := range tests {
+		got := rfmSegment(tt.r, tt.f, tt.m)
+		if got != tt.want {
+			t.Errorf("rfmSegment(%d, %d, %d) = %s, want %s", tt.r, tt.f, tt.m, got, tt.want)
+		}
+		seen[got] = true
+	}
+	for _, segment := range models.RFMSegments {
+		if !seen[segment] {
+			t.Errorf("segment %s not covered", segment)
+		}
+	}
+}
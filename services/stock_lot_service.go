@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type StockLotService struct {
+	repo        *repositories.StockLotRepository
+	productRepo *repositories.ProductRepository
+	location    *time.Location
+}
+
+// NewStockLotService membuat service lot stok. location adalah zona waktu
+// toko untuk menentukan tanggal hari ini saat mengecek kedaluwarsa.
+func NewStockLotService(repo *repositories.StockLotRepository, productRepo *repositories.ProductRepository, location *time.Location) *StockLotService {
+	return &StockLotService{repo: repo, productRepo: productRepo, location: location}
+}
+
+func (s *StockLotService) GetByProductID(productID int) ([]models.StockLot, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProductID(productID)
+}
+
+func (s *StockLotService) Receive(productID int, req models.ReceiveLotRequest) (*models.StockLot, error) {
+	req.LotNumber = strings.TrimSpace(req.LotNumber)
+	if req.LotNumber == "" {
+		return nil, errors.New("lot_number wajib diisi")
+	}
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity harus lebih dari 0")
+	}
+	if req.UnitCost != nil && *req.UnitCost < 0 {
+		return nil, errors.New("unit_cost tidak boleh negatif")
+	}
+	expiryDate, err := normalizeExpiryDate(req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+	req.ExpiryDate = expiryDate
+
+	return s.repo.Receive(productID, req)
+}
+
+// GetExpiring mengembalikan lot yang kedaluwarsa dalam days hari ke depan,
+// termasuk lot yang sudah kedaluwarsa tapi masih ada stoknya.
+func (s *StockLotService) GetExpiring(days int) ([]models.ExpiringLot, error) {
+	if days < 0 {
+		return nil, errors.New("days tidak boleh negatif")
+	}
+	today := storeToday(s.location)
+	return s.repo.GetExpiring(today, today.AddDate(0, 0, days))
+}
+
+// normalizeExpiryDate memastikan expiry_date berformat YYYY-MM-DD. Nilai
+// kosong diubah menjadi nil yang berarti barang tanpa kedaluwarsa.
+func normalizeExpiryDate(date *string) (*string, error) {
+	if date == nil || *date == "" {
+		return nil, nil
+	}
+	if _, err := time.Parse("2006-01-02", *date); err != nil {
+		return nil, errors.New("format expiry_date harus YYYY-MM-DD")
+	}
+	return date, nil
+}
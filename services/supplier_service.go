@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type SupplierService struct {
+	repo *repositories.SupplierRepository
+}
+
+func NewSupplierService(repo *repositories.SupplierRepository) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+func (s *SupplierService) GetAll() ([]models.Supplier, error) {
+	return s.repo.GetAll()
+}
+
+func (s *SupplierService) Create(data *models.Supplier) error {
+	if err := validateSupplier(data); err != nil {
+		return err
+	}
+	return s.repo.Create(data)
+}
+
+func (s *SupplierService) GetByID(id int) (*models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *SupplierService) Update(supplier *models.Supplier) error {
+	if err := validateSupplier(supplier); err != nil {
+		return err
+	}
+	return s.repo.Update(supplier)
+}
+
+func (s *SupplierService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validateSupplier(supplier *models.Supplier) error {
+	if supplier.LeadTimeDays != nil && *supplier.LeadTimeDays < 0 {
+		return errors.New("lead_time_days tidak boleh negatif")
+	}
+	return nil
+}
@@ -1,27 +1,60 @@
 package services
 
 import (
+	"fmt"
 	"kasir-api/models"
 	"kasir-api/repositories"
 	"time"
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo     *repositories.TransactionRepository
+	location *time.Location
 }
 
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+// NewTransactionService membuat service transaksi. location adalah zona
+// waktu toko untuk batas hari laporan dan tanggal kedaluwarsa lot.
+func NewTransactionService(repo *repositories.TransactionRepository, location *time.Location) *TransactionService {
+	return &TransactionService{repo: repo, location: location}
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+func (s *TransactionService) Checkout(items []models.CheckoutItem, customerID, customerGroupID *int) (*models.Transaction, error) {
+	if err := validateCheckoutItems(items); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateTransaction(items, customerID, customerGroupID, time.Now().In(s.location))
 }
 
-func (s *TransactionService) GetTodaySummary() (*models.SalesReport, error) {
-	return s.repo.GetTodaySummary()
+// Quote menghitung harga keranjang tanpa membuat transaksi.
+func (s *TransactionService) Quote(items []models.CheckoutItem, customerID, customerGroupID *int) (*models.Transaction, error) {
+	if err := validateCheckoutItems(items); err != nil {
+		return nil, err
+	}
+	return s.repo.Quote(items, customerID, customerGroupID)
 }
 
-func (s *TransactionService) GetSummaryByDateRange(startDate, endDate time.Time) (*models.SalesReport, error) {
-	return s.repo.GetSummaryByDateRange(startDate, endDate)
+func validateCheckoutItems(items []models.CheckoutItem) error {
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity untuk product id %d harus lebih dari 0", item.ProductID)
+		}
+	}
+	return nil
+}
+
+func (s *TransactionService) GetTodaySummary(categoryID int) (*models.SalesReport, error) {
+	today := storeToday(s.location)
+	return s.GetSummaryByDateRange(today, today, categoryID)
+}
+
+// GetSummaryByDateRange menghitung ringkasan dari awal startDate sampai akhir
+// endDate menurut tanggal di zona waktu toko.
+func (s *TransactionService) GetSummaryByDateRange(startDate, endDate time.Time, categoryID int) (*models.SalesReport, error) {
+	start, end := storeDayBounds(s.location, startDate, endDate)
+	return s.repo.GetSummary(start, end, categoryID)
+}
+
+func (s *TransactionService) GetBundleComponentSales(startDate, endDate time.Time) ([]models.BundleComponentSales, error) {
+	start, end := storeDayBounds(s.location, startDate, endDate)
+	return s.repo.GetBundleComponentSales(start, end)
 }
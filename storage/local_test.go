@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	base := t.TempDir()
+	s := &LocalStorage{BaseDir: base}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "products/12/a.jpg", want: filepath.Join(base, "products", "12", "a.jpg")},
+		{key: "/products/12/a.jpg", want: filepath.Join(base, "products", "12", "a.jpg")},
+		{key: "products//12/./a.jpg", want: filepath.Join(base, "products", "12", "a.jpg")},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "../secret", wantErr: true},
+		{key: "products/../../etc/passwd", wantErr: true},
+		{key: "products/12/..", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := s.path(tt.key)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("path(%q) = %q, want error", tt.key, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("path(%q): %v", tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("path(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+		if !strings.HasPrefix(got, base+string(filepath.Separator)) {
+			t.Errorf("path(%q) = %q is outside %q", tt.key, got, base)
+		}
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(filepath.Join(t.TempDir(), "uploads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "products/12/a.jpg"
+	if err := s.Put(ctx, key, strings.NewReader("jpeg bytes"), 10, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "jpeg bytes" {
+		t.Errorf("Get body = %q", got)
+	}
+
+	// Tidak ada file sementara yang tertinggal setelah Put
+	entries, err := os.ReadDir(filepath.Join(s.BaseDir, "products", "12"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want 1", len(entries))
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete missing file: %v", err)
+	}
+	if err := s.Put(ctx, "../escape.jpg", strings.NewReader("x"), 1, "image/jpeg"); err == nil {
+		t.Error("Put outside BaseDir: err = nil, want error")
+	}
+}
@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "minioadmin"
+	testSecretKey = "minioadmin-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "kasir-test"
+)
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+// fakeS3 adalah stand-in S3 minimal untuk PUT, GET dan DELETE object. Setiap
+// request diverifikasi tanda tangan SigV4-nya dari request yang benar-benar
+// diterima, jadi kesalahan host, path atau encoding di klien ikut ketahuan.
+type fakeS3 struct {
+	t         *testing.T
+	pathStyle bool
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verifySignature(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL.EscapedPath(), err)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	bucket, key := "", strings.TrimPrefix(r.URL.Path, "/")
+	if f.pathStyle {
+		bucket, key, _ = strings.Cut(key, "/")
+	} else {
+		bucket, _, _ = strings.Cut(r.Host, ".")
+	}
+	if bucket != testBucket {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got := sha256Hex(body); got != r.Header.Get("x-amz-content-sha256") {
+			http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature menghitung ulang tanda tangan SigV4 dari request yang
+// diterima server.
+func (f *fakeS3) verifySignature(r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	credential, rest, ok := strings.Cut(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 Credential="), ", SignedHeaders=")
+	if !ok {
+		return errors.New("missing or malformed Authorization header")
+	}
+	signedHeaders, signature, ok := strings.Cut(rest, ", Signature=")
+	if !ok {
+		return errors.New("missing signature")
+	}
+	accessKey, scope, _ := strings.Cut(credential, "/")
+	if accessKey != testAccessKey {
+		return errors.New("unknown access key " + accessKey)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		r.Header.Get("x-amz-content-sha256"),
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		r.Header.Get("x-amz-date"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	parts := strings.Split(scope, "/")
+	if len(parts) != 4 || parts[1] != testRegion || parts[2] != "s3" {
+		return errors.New("invalid credential scope " + scope)
+	}
+	key := hmacSHA256([]byte("AWS4"+testSecretKey), parts[0])
+	for _, p := range parts[1:] {
+		key = hmacSHA256(key, p)
+	}
+	want := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(signature), []byte(want)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// newTestS3 mengembalikan S3Storage yang terhubung ke fake S3. Untuk gaya
+// virtual-hosted, semua koneksi diarahkan ke server test apa pun host-nya.
+func newTestS3(t *testing.T, pathStyle bool) (*S3Storage, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, pathStyle: pathStyle, objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := NewS3Storage(server.URL, testRegion, testBucket, testAccessKey, testSecretKey, pathStyle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := server.Listener.Addr().String()
+	s.Client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	return s, fake
+}
+
+// minioFromEnv mengembalikan S3Storage ke MinIO (atau layanan S3-compatible
+// lain) jika S3_TEST_ENDPOINT diisi, misalnya setelah
+// docker run -p 9000:9000 minio/minio server /data dan membuat bucket-nya.
+func minioFromEnv(t *testing.T) *S3Storage {
+	t.Helper()
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+	s, err := NewS3Storage(endpoint, os.Getenv("S3_TEST_REGION"), os.Getenv("S3_TEST_BUCKET"),
+		os.Getenv("S3_TEST_ACCESS_KEY"), os.Getenv("S3_TEST_SECRET_KEY"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestS3Storage(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage func(t *testing.T) *S3Storage
+	}{
+		{"path style", func(t *testing.T) *S3Storage { s, _ := newTestS3(t, true); return s }},
+		{"virtual hosted", func(t *testing.T) *S3Storage { s, _ := newTestS3(t, false); return s }},
+		{"minio", minioFromEnv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testS3RoundTrip(t, tt.storage(t))
+		})
+	}
+}
+
+func testS3RoundTrip(t *testing.T, s *S3Storage) {
+	ctx := context.Background()
+	// Key dengan spasi dan karakter non-ASCII menguji encoding path SigV4
+	key := "products/12/foto produk é.webp"
+	content := []byte("RIFF....WEBPVP8 image bytes")
+
+	if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "image/webp"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get body = %q, want %q", got, content)
+	}
+
+	// Content-Type yang dikirim saat Put harus kembali saat object diambil
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "image/webp" {
+		t.Errorf("Content-Type = %q, want image/webp", ct)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	// Menghapus object yang sudah tidak ada bukan error
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete missing object: %v", err)
+	}
+}
+
+func TestS3StorageErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	s, err := NewS3Storage(server.URL, testRegion, testBucket, testAccessKey, testSecretKey, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Put(context.Background(), "a.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put err = %v, want 403 with S3 error body", err)
+	}
+	if _, err := s.Get(context.Background(), "a.jpg"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get err = %v, want error other than ErrNotFound", err)
+	}
+	if err := s.Delete(context.Background(), "a.jpg"); err == nil {
+		t.Error("Delete err = nil, want error")
+	}
+}
+
+func TestURIEncode(t *testing.T) {
+	tests := []struct {
+		in          string
+		encodeSlash bool
+		want        string
+	}{
+		{"products/12/a.jpg", false, "products/12/a.jpg"},
+		{"products/12/a b.jpg", false, "products/12/a%20b.jpg"},
+		{"a+b~c_d-e.f", false, "a%2Bb~c_d-e.f"},
+		{"é", false, "%C3%A9"},
+		{"bucket/name", true, "bucket%2Fname"},
+	}
+	for _, tt := range tests {
+		if got := uriEncode(tt.in, tt.encodeSlash); got != tt.want {
+			t.Errorf("uriEncode(%q, %v) = %q, want %q", tt.in, tt.encodeSlash, got, tt.want)
+		}
+	}
+}
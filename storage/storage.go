@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound dikembalikan ketika object dengan key yang diminta tidak ada.
+var ErrNotFound = errors.New("object tidak ditemukan")
+
+// Storage adalah backend penyimpanan file (gambar produk, dll). Key selalu
+// berupa path relatif dengan pemisah "/", misalnya "products/12/abc.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}